@@ -12,10 +12,12 @@ require (
 	github.com/joho/godotenv v1.4.0
 	github.com/jung-kurt/gofpdf v1.16.2
 	github.com/sethvargo/go-envconfig v0.8.3
+	github.com/shopspring/decimal v1.3.1
 	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
 	github.com/stretchr/testify v1.8.1
 	go.opencensus.io v0.24.0
 	go.uber.org/zap v1.24.0
+	golang.org/x/image v0.15.0
 )
 
 require (
@@ -30,7 +32,7 @@ require (
 	go.uber.org/atomic v1.10.0 // indirect
 	go.uber.org/multierr v1.9.0 // indirect
 	golang.org/x/crypto v0.5.0 // indirect
-	golang.org/x/sys v0.4.0 // indirect
-	golang.org/x/text v0.7.0
-	gopkg.in/yaml.v3 v3.0.1 // indirect
+	golang.org/x/sys v0.5.0 // indirect
+	golang.org/x/text v0.14.0
+	gopkg.in/yaml.v3 v3.0.1
 )
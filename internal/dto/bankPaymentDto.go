@@ -8,4 +8,5 @@ type BankPaymentDto struct {
 
 	PaymentReference      *string `json:"paymentReference,omitempty"`
 	RemittanceInformation *string `json:"remittanceInformation,omitempty"`
+	Purpose               *string `json:"purpose,omitempty" validate:"omitempty,len=4,alpha,uppercase"`
 }
@@ -1,6 +1,12 @@
 package dto
 
+import "github.com/google/uuid"
+
 type DocumentDto struct {
+	//stored profile of the tenant, its seller information, bank payment data and
+	//style are used for the fields not given by the request
+	ProfileID *uuid.UUID `json:"profileId"`
+
 	Style *DocumentStyleDto `json:"style" validate:"required"`
 
 	SellerInformation *SellerInformationDto `json:"sellerInformation" validate:"required"`
@@ -9,13 +15,20 @@ type DocumentDto struct {
 
 	InvoiceInformation *InvoiceInformationDto `json:"invoiceInformation" validate:"required"`
 
+	//can be null - no title line above the customer address
+	Subject *SubjectDto `json:"subject"`
+
 	//can be null - no specific customer to be written on the invoice
 	CustomerAddress *AddressDto `json:"customerAddress"`
 
 	InvoiceData *InvoiceDto `json:"invoiceData" validate:"required"`
 
-	//string-data-block after the invoice for writing thank you
+	//string-data-block after the invoice for writing thank you, supports markup
+	//(see document.RichText)
 	InvoiceDataSuffix *string `json:"invoiceDataSuffix" validate:"omitempty"`
 
 	BankPaymentData *BankPaymentDto `json:"bankPaymentData"`
+
+	//ISO 4217 currency code of all amounts, EUR when not set
+	Currency *string `json:"currency" validate:"omitempty,iso4217"`
 }
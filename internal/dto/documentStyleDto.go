@@ -1,6 +1,10 @@
 package dto
 
-import "github.com/hodl-repos/pdf-invoice/pkg/document"
+import (
+	"github.com/hodl-repos/pdf-invoice/pkg/bank"
+	"github.com/hodl-repos/pdf-invoice/pkg/document"
+	"github.com/hodl-repos/pdf-invoice/pkg/einvoice"
+)
 
 type DocumentStyleDto struct {
 	LocaleCode   *string `json:"localeCode" validate:"required"`
@@ -10,6 +14,10 @@ type DocumentStyleDto struct {
 
 	Layout *document.LayoutType `json:"layout" validate:"required"`
 
+	//blocks of the document (see templatestore), the built-in template "default"
+	//when not set, only used by /generate
+	Template *string `json:"template"`
+
 	//only possible when A4-Portrait
 	ShowMarkerPuncher *bool `json:"showMarkerPuncher"`
 
@@ -19,5 +27,25 @@ type DocumentStyleDto struct {
 	//only possible when submitting bank-payment-data in main document
 	ShowBankPaymentQrCode *bool `json:"showBankPaymentQrCode"`
 
+	//EPC (default) or the swiss QR-bill at the bottom of the last page
+	PaymentQrCodeType *bank.QrCodeType `json:"paymentQrCodeType" validate:"omitempty,oneof=EPC SWISS_QR_BILL"`
+
+	//replaces the seller information in the footer, supports markup (see
+	//document.RichText)
 	FooterOverride *string `json:"footerOverride"`
+
+	//fonts, sizes and line height of the text blocks
+	Typography *TypographyDto `json:"typography"`
+
+	//colors of the text blocks, tables and lines
+	Theme *ThemeDto `json:"theme"`
+
+	//RTL mirrors the alignment of the texts and the columns of the tables
+	Direction *document.Direction `json:"direction" validate:"omitempty,oneof=LTR RTL"`
+
+	//archivable output, factur-x always uses PDF/A-3b
+	Conformance *document.Conformance `json:"conformance" validate:"omitempty,oneof=PDFA_3B"`
+
+	//embeds a factur-x/zugferd xml with the given profile, only possible for invoices
+	FacturXProfile *einvoice.Profile `json:"facturXProfile" validate:"omitempty,oneof=MINIMUM BASIC EN16931 EXTENDED XRECHNUNG"`
 }
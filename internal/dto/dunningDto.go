@@ -0,0 +1,75 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DunningLevel is the escalation level of a dunning letter
+type DunningLevel string
+
+const (
+	DunningLevelPaymentReminder DunningLevel = "PAYMENT_REMINDER"
+	DunningLevelSecondReminder  DunningLevel = "SECOND_REMINDER"
+	DunningLevelFinalNotice     DunningLevel = "FINAL_NOTICE"
+)
+
+type DunningDto struct {
+	//stored profile of the tenant, its seller information, bank payment data and
+	//style are used for the fields not given by the request
+	ProfileID *uuid.UUID `json:"profileId"`
+
+	Style *DocumentStyleDto `json:"style" validate:"required"`
+
+	SellerInformation *SellerInformationDto `json:"sellerInformation" validate:"required"`
+
+	InvoiceAddress *InvoiceAddressDto `json:"invoiceAddress" validate:"required"`
+
+	DunningInformation *DunningInformationDto `json:"dunningInformation" validate:"required"`
+
+	//can be null - no specific customer to be written on the letter
+	CustomerAddress *AddressDto `json:"customerAddress"`
+
+	OpenInvoices *[]OpenInvoiceDto `json:"openInvoices" validate:"required,min=1,dive"`
+
+	//string-data-block after the open invoices, supports markup (see
+	//document.RichText)
+	DunningDataSuffix *string `json:"dunningDataSuffix" validate:"omitempty"`
+
+	BankPaymentData *BankPaymentDto `json:"bankPaymentData"`
+
+	//ISO 4217 currency code of all amounts, EUR when not set
+	Currency *string `json:"currency" validate:"omitempty,iso4217"`
+}
+
+type DunningInformationDto struct {
+	Level *DunningLevel `json:"level" validate:"required,oneof=PAYMENT_REMINDER SECOND_REMINDER FINAL_NOTICE"`
+
+	DunningNumber *string    `json:"dunningNumber"`
+	DunningDate   *time.Time `json:"dunningDate" validate:"required"`
+
+	//the outstanding amount has to be paid until
+	DueDate *time.Time `json:"dueDate" validate:"required"`
+
+	CustomerIdentifier *string `json:"customerIdentifier"`
+
+	//replaces the localized text of the level, {{.Amount}} and {{.DueDate}}
+	//are replaced by the outstanding amount and the due date
+	EscalationText *string `json:"escalationText"`
+
+	AdditionalInformation *[]AdditionalInvoiceInformationDto `json:"additionalInformation" validate:"omitempty,dive"`
+}
+
+type OpenInvoiceDto struct {
+	InvoiceNumber *string    `json:"invoiceNumber" validate:"required"`
+	InvoiceDate   *time.Time `json:"invoiceDate" validate:"required"`
+	DueDate       *time.Time `json:"dueDate"`
+
+	//gross amount of the invoice
+	Amount *float64 `json:"amount" validate:"required"`
+
+	Paid     *float64 `json:"paid" validate:"omitempty,min=0"`
+	Fees     *float64 `json:"fees" validate:"omitempty,min=0"`
+	Interest *float64 `json:"interest" validate:"omitempty,min=0"`
+}
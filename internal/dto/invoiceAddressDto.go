@@ -10,6 +10,9 @@ type InvoiceAddressDto struct {
 	*AddressDto
 
 	VAT *string `json:"vat,omitempty"`
+
+	//not printed, used as electronic address for electronic invoices
+	Email *string `json:"email,omitempty" validate:"omitempty,email"`
 }
 
 func (data *InvoiceAddressDto) Format(d delimitor.Delimitor) string {
@@ -1,5 +1,7 @@
 package dto
 
+import "github.com/hodl-repos/pdf-invoice/pkg/calculation"
+
 type InvoiceDto struct {
 	ShowNetColumn    *bool `json:"showNetColumn"`
 	ShowGrossColumn  *bool `json:"showGrossColumn"`
@@ -10,8 +12,15 @@ type InvoiceDto struct {
 	ShowTaxSum   *bool `json:"showTaxSum"`
 	ShowGrossSum *bool `json:"showGrossSum"`
 
+	//shows taxable amount and tax per tax rate below the rows
+	ShowTaxBreakdown *bool `json:"showTaxBreakdown"`
+
 	SumDiscountPercentage *float64 `json:"sumDiscountPercentage"`
 	SumDiscountFixed      *float64 `json:"sumDiscountFixed"`
 
+	//calculates net, tax and gross of the rows from amount, unitPrice and taxPercentage,
+	//tax is rounded per LINE or per DOCUMENT (tax rate)
+	CalculationMode *calculation.RoundingMode `json:"calculationMode" validate:"omitempty,oneof=LINE DOCUMENT"`
+
 	Rows *[]InvoiceRowDto `json:"rows"`
 }
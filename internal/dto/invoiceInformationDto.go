@@ -2,20 +2,60 @@ package dto
 
 import "time"
 
+// DocumentType determines the title, the labels and the blocks of the
+// document, offers are given by the offer number
+type DocumentType string
+
+const (
+	DocumentTypeInvoice           DocumentType = "INVOICE"
+	DocumentTypeCreditNote        DocumentType = "CREDIT_NOTE"
+	DocumentTypeDeliveryNote      DocumentType = "DELIVERY_NOTE"
+	DocumentTypeOrderConfirmation DocumentType = "ORDER_CONFIRMATION"
+)
+
 type InvoiceInformationDto struct {
+	DocumentType *DocumentType `json:"documentType" validate:"omitempty,oneof=INVOICE CREDIT_NOTE DELIVERY_NOTE ORDER_CONFIRMATION"`
+
 	OfferNumber *string    `json:"offerNumber" validate:"required_without=InvoiceNumber"`
 	OfferDate   *time.Time `json:"offerDate" validate:"required_with=OfferNumber"`
 
-	DueDate *time.Time `json:"dueDate" validate:"required"`
+	//delivery notes have no due date
+	DueDate *time.Time `json:"dueDate" validate:"required_unless=DocumentType DELIVERY_NOTE"`
 
-	InvoiceNumber *string    `json:"invoiceNumber" validate:"required_without=OfferNumber"`
+	//number and date of all document types except offers, the label depends on the document type
+	InvoiceNumber *string    `json:"invoiceNumber" validate:"required_without=OfferNumber,required_if=DocumentType CREDIT_NOTE,required_if=DocumentType DELIVERY_NOTE,required_if=DocumentType ORDER_CONFIRMATION"`
 	InvoiceDate   *time.Time `json:"invoiceDate" validate:"required_with=InvoiceNumber"`
 
+	//invoice corrected by a credit note
+	CorrectedInvoiceNumber *string    `json:"correctedInvoiceNumber" validate:"required_if=DocumentType CREDIT_NOTE"`
+	CorrectedInvoiceDate   *time.Time `json:"correctedInvoiceDate" validate:"required_with=CorrectedInvoiceNumber"`
+
 	CustomerIdentifier *string `json:"customerIdentifier"`
 
+	//reference of the customer for routing the invoice (e.g. order or cost center)
+	BuyerReference *string `json:"buyerReference"`
+
+	//routing identifier of german public sector customers, required for xrechnung
+	LeitwegID *string `json:"leitwegId"`
+
 	AdditionalInformation *[]AdditionalInvoiceInformationDto `json:"additionalInformation"`
 }
 
+// IsCreditNote reports whether the document is a credit note
+func (data *InvoiceInformationDto) IsCreditNote() bool {
+	return data.DocumentType != nil && *data.DocumentType == DocumentTypeCreditNote
+}
+
+// IsDeliveryNote reports whether the document is a delivery note
+func (data *InvoiceInformationDto) IsDeliveryNote() bool {
+	return data.DocumentType != nil && *data.DocumentType == DocumentTypeDeliveryNote
+}
+
+// IsOrderConfirmation reports whether the document is an order confirmation
+func (data *InvoiceInformationDto) IsOrderConfirmation() bool {
+	return data.DocumentType != nil && *data.DocumentType == DocumentTypeOrderConfirmation
+}
+
 type AdditionalInvoiceInformationDto struct {
 	Title *string `json:"title" validate:"required"`
 	Value *string `json:"value" validate:"required"`
@@ -1,12 +1,16 @@
 package dto
 
 type InvoiceRowDto struct {
-	Name        *string `json:"name" validate:"required"`
+	Name *string `json:"name" validate:"required"`
+	//printed below the name, supports markup (see document.RichText)
 	Description *string `json:"description" validate:"omitempty"`
 
 	Amount     *float64 `json:"amount"`
 	AmountUnit *string  `json:"amountUnit"`
 
+	//net price of one unit, only used with calculationMode
+	UnitPrice *float64 `json:"unitPrice"`
+
 	Net           *float64 `json:"net"`
 	TaxPercentage *float64 `json:"taxPercentage"`
 	Tax           *float64 `json:"tax"`
@@ -14,4 +18,8 @@ type InvoiceRowDto struct {
 
 	DiscountPercentage *float64 `json:"discountPercentage"`
 	DiscountFixed      *float64 `json:"discountFixed"`
+
+	//only shown on delivery notes
+	SerialNumber *string `json:"serialNumber"`
+	BatchNumber  *string `json:"batchNumber"`
 }
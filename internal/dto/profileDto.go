@@ -0,0 +1,19 @@
+package dto
+
+import "github.com/google/uuid"
+
+// ProfileDto is the stored branding of a tenant, the fields are used for the
+// fields of a document which are not given by the request
+type ProfileDto struct {
+	//set by the service
+	ID *uuid.UUID `json:"id,omitempty"`
+
+	Name *string `json:"name" validate:"required"`
+
+	SellerInformation *SellerInformationDto `json:"sellerInformation"`
+
+	BankPaymentData *BankPaymentDto `json:"bankPaymentData"`
+
+	//complete style of the tenant, the logo is the image of the style
+	Style *DocumentStyleDto `json:"style"`
+}
@@ -0,0 +1,12 @@
+package dto
+
+// SubjectDto is the title line and introduction between the header block and
+// the customer address, the placeholders {{.CustomerName}}, {{.DocumentType}},
+// {{.Number}}, {{.Date}} and {{.DueDate}} are replaced in both texts
+type SubjectDto struct {
+	//document type and number when not set
+	Title *string `json:"title"`
+
+	//paragraph below the title
+	Introduction *string `json:"introduction"`
+}
@@ -0,0 +1,23 @@
+package dto
+
+// colors as hex string (#RRGGBB or #RGB), unset colors keep the defaults
+type ThemeDto struct {
+	//headings, labels of the information block and the totals
+	Primary *string `json:"primary" validate:"omitempty,hexcolor"`
+
+	//text of the table heads and the page numbers
+	Accent *string `json:"accent" validate:"omitempty,hexcolor"`
+
+	Text *string `json:"text" validate:"omitempty,hexcolor"`
+
+	//background of every other row of the invoice table
+	Zebra *string `json:"zebra" validate:"omitempty,hexcolor"`
+
+	//lines and markers
+	Border *string `json:"border" validate:"omitempty,hexcolor"`
+
+	TableHeadBackground *string `json:"tableHeadBackground" validate:"omitempty,hexcolor"`
+
+	//defaults to the border color
+	BankBlockBorder *string `json:"bankBlockBorder" validate:"omitempty,hexcolor"`
+}
@@ -0,0 +1,49 @@
+package dto
+
+type TypographyDto struct {
+	Body      *FontDto `json:"body"`
+	Heading   *FontDto `json:"heading"`
+	TableHead *FontDto `json:"tableHead"`
+	Footer    *FontDto `json:"footer"`
+
+	//factor of the font size, e.g. 1.2 = 120%
+	LineHeight *float64 `json:"lineHeight" validate:"omitempty,min=1,max=3"`
+}
+
+type FontDto struct {
+	//the bundled DejaVu, a family of the font store or the name of the font
+	//given by data, other fonts of the request can use the name afterwards
+	Family *string `json:"family" validate:"required_with=Data"`
+
+	//a TrueType font (.ttf) as base64 or base64 data URI of up to 2MB, which is
+	//used for all styles, only the first data of a family is used
+	Data *string `json:"data"`
+
+	Style *FontStyle `json:"style" validate:"omitempty,oneof=REGULAR BOLD ITALIC BOLD_ITALIC"`
+
+	//in pt
+	Size *float64 `json:"size" validate:"omitempty,min=4,max=36"`
+}
+
+type FontStyle string
+
+const (
+	FontStyleRegular    FontStyle = "REGULAR"
+	FontStyleBold       FontStyle = "BOLD"
+	FontStyleItalic     FontStyle = "ITALIC"
+	FontStyleBoldItalic FontStyle = "BOLD_ITALIC"
+)
+
+// PdfStyle returns the style as used by gofpdf.
+func (s FontStyle) PdfStyle() string {
+	switch s {
+	case FontStyleBold:
+		return "B"
+	case FontStyleItalic:
+		return "I"
+	case FontStyleBoldItalic:
+		return "BI"
+	default:
+		return ""
+	}
+}
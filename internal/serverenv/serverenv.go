@@ -4,14 +4,24 @@ package serverenv
 import (
 	"context"
 
+	"github.com/hodl-repos/pdf-invoice/pkg/assetstore"
+	"github.com/hodl-repos/pdf-invoice/pkg/fetcher"
+	"github.com/hodl-repos/pdf-invoice/pkg/fontstore"
 	"github.com/hodl-repos/pdf-invoice/pkg/localize"
 	"github.com/hodl-repos/pdf-invoice/pkg/logging"
+	"github.com/hodl-repos/pdf-invoice/pkg/profilestore"
+	"github.com/hodl-repos/pdf-invoice/pkg/templatestore"
 )
 
 // ServerEnv represents latent environment configuration for servers in this
 // application.
 type ServerEnv struct {
 	localizeService *localize.LocalizeService
+	fontStore       *fontstore.FontStore
+	templateStore   *templatestore.TemplateStore
+	profileStore    *profilestore.ProfileStore
+	assetStore      *assetstore.AssetStore
+	imageFetcher    *fetcher.Fetcher
 }
 
 // Option defines function type to modify a ServerEnv on creation.
@@ -35,10 +45,65 @@ func WithLocalization(service *localize.LocalizeService) Option {
 	}
 }
 
+func WithFontStore(store *fontstore.FontStore) Option {
+	return func(env *ServerEnv) *ServerEnv {
+		env.fontStore = store
+		return env
+	}
+}
+
+func WithTemplateStore(store *templatestore.TemplateStore) Option {
+	return func(env *ServerEnv) *ServerEnv {
+		env.templateStore = store
+		return env
+	}
+}
+
+func WithProfileStore(store *profilestore.ProfileStore) Option {
+	return func(env *ServerEnv) *ServerEnv {
+		env.profileStore = store
+		return env
+	}
+}
+
+func WithAssetStore(store *assetstore.AssetStore) Option {
+	return func(env *ServerEnv) *ServerEnv {
+		env.assetStore = store
+		return env
+	}
+}
+
+func WithImageFetcher(f *fetcher.Fetcher) Option {
+	return func(env *ServerEnv) *ServerEnv {
+		env.imageFetcher = f
+		return env
+	}
+}
+
 func (s *ServerEnv) Localize() *localize.LocalizeService {
 	return s.localizeService
 }
 
+func (s *ServerEnv) FontStore() *fontstore.FontStore {
+	return s.fontStore
+}
+
+func (s *ServerEnv) TemplateStore() *templatestore.TemplateStore {
+	return s.templateStore
+}
+
+func (s *ServerEnv) ProfileStore() *profilestore.ProfileStore {
+	return s.profileStore
+}
+
+func (s *ServerEnv) AssetStore() *assetstore.AssetStore {
+	return s.assetStore
+}
+
+func (s *ServerEnv) ImageFetcher() *fetcher.Fetcher {
+	return s.imageFetcher
+}
+
 // Close shuts down the server env, closing database connections, etc.
 func (s *ServerEnv) Close(ctx context.Context) error {
 	logger := logging.FromContext(ctx)
@@ -1,12 +1,44 @@
 package service
 
-import "github.com/hodl-repos/pdf-invoice/pkg/localize"
+import (
+	"github.com/hodl-repos/pdf-invoice/pkg/assetstore"
+	"github.com/hodl-repos/pdf-invoice/pkg/fetcher"
+	"github.com/hodl-repos/pdf-invoice/pkg/fontstore"
+	"github.com/hodl-repos/pdf-invoice/pkg/localize"
+	"github.com/hodl-repos/pdf-invoice/pkg/profilestore"
+	"github.com/hodl-repos/pdf-invoice/pkg/templatestore"
+)
 
 type Config struct {
-	LocalizeConfig *localize.Config
-	Port           string `env:"PORT, default=12003"`
+	LocalizeConfig      *localize.Config
+	FontStoreConfig     *fontstore.Config
+	TemplateStoreConfig *templatestore.Config
+	ProfileStoreConfig  *profilestore.Config
+	AssetStoreConfig    *assetstore.Config
+	FetcherConfig       *fetcher.Config
+	Port                string `env:"PORT, default=12003"`
 }
 
 func (c *Config) LocalizeServiceConfig() *localize.Config {
 	return c.LocalizeConfig
 }
+
+func (c *Config) FontStoreServiceConfig() *fontstore.Config {
+	return c.FontStoreConfig
+}
+
+func (c *Config) TemplateStoreServiceConfig() *templatestore.Config {
+	return c.TemplateStoreConfig
+}
+
+func (c *Config) ProfileStoreServiceConfig() *profilestore.Config {
+	return c.ProfileStoreConfig
+}
+
+func (c *Config) AssetStoreServiceConfig() *assetstore.Config {
+	return c.AssetStoreConfig
+}
+
+func (c *Config) FetcherServiceConfig() *fetcher.Config {
+	return c.FetcherConfig
+}
@@ -0,0 +1,145 @@
+package v1
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/hodl-repos/pdf-invoice/pkg/apihelper"
+	"github.com/hodl-repos/pdf-invoice/pkg/assetstore"
+	"github.com/hodl-repos/pdf-invoice/pkg/document"
+	"github.com/hodl-repos/pdf-invoice/pkg/logging"
+	"github.com/hodl-repos/pdf-invoice/pkg/standardisedError"
+)
+
+// route param of the asset id, mapped by apihelper.MapUuidHeader
+const AssetIDParam = "assetId"
+
+// max size of an uploaded image of 5MB
+const maxAssetBytes = 5_000_000
+
+// content types of the image types of document.DetectImageType
+var assetContentTypes = map[string]string{
+	"PNG":  "image/png",
+	"JPG":  "image/jpeg",
+	"GIF":  "image/gif",
+	"WEBP": "image/webp",
+	"TIFF": "image/tiff",
+	"BMP":  "image/bmp",
+	"SVG":  "image/svg+xml",
+}
+
+func AssetCreateHandler(assetStore *assetstore.AssetStore) apihelper.HandlerFuncWithError {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		logger := logging.FromContext(ctx)
+
+		logger.Debugln("got request for v1-asset-create")
+
+		defer r.Body.Close()
+
+		data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxAssetBytes))
+		if err != nil {
+			return &standardisedError.StandardisedError{
+				Type:   "validation-error",
+				Title:  "could not read the image",
+				Status: http.StatusRequestEntityTooLarge,
+				Detail: err.Error(),
+			}
+		}
+
+		//only images which can be printed are stored
+		if _, err := document.DetectImageType(data); err != nil {
+			return &standardisedError.StandardisedError{
+				Type:   "validation-error",
+				Title:  "could not load the image",
+				Status: http.StatusBadRequest,
+				Detail: err.Error(),
+			}
+		}
+
+		id, err := assetStore.Create(data)
+		if err != nil {
+			return err
+		}
+
+		apihelper.WriteCreateRessourceResponse(w, r, id)
+
+		return nil
+	}
+}
+
+func AssetGetHandler(assetStore *assetstore.AssetStore) apihelper.HandlerFuncWithError {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		logger := logging.FromContext(ctx)
+
+		logger.Debugln("got request for v1-asset-get")
+
+		id, err := apihelper.GetContextValue[uuid.UUID](ctx, AssetIDParam)
+		if err != nil {
+			return err
+		}
+
+		data, ok := assetStore.Get(*id)
+		if !ok {
+			return assetNotFoundError(*id)
+		}
+
+		imageType, _ := document.DetectImageType(data)
+
+		contentType, ok := assetContentTypes[imageType]
+		if !ok {
+			contentType = "application/octet-stream"
+		}
+
+		w.Header().Set("content-type", contentType)
+		w.Header().Set("x-content-type-options", "nosniff")
+		w.Header().Set("content-security-policy", "default-src 'none'")
+
+		//svg can contain scripts, it is downloaded instead of opened
+		if imageType == "SVG" {
+			w.Header().Set("content-disposition", `attachment; filename="`+id.String()+`.svg"`)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+
+		return nil
+	}
+}
+
+func AssetDeleteHandler(assetStore *assetstore.AssetStore) apihelper.HandlerFuncWithError {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		logger := logging.FromContext(ctx)
+
+		logger.Debugln("got request for v1-asset-delete")
+
+		id, err := apihelper.GetContextValue[uuid.UUID](ctx, AssetIDParam)
+		if err != nil {
+			return err
+		}
+
+		if err := assetStore.Delete(*id); err != nil {
+			if errors.Is(err, assetstore.ErrAssetNotFound) {
+				return assetNotFoundError(*id)
+			}
+			return err
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+		return nil
+	}
+}
+
+func assetNotFoundError(id uuid.UUID) error {
+	return &standardisedError.StandardisedError{
+		Type:   "not-found",
+		Title:  "could not find the asset " + id.String(),
+		Status: http.StatusNotFound,
+		Detail: "the asset does not exist or was deleted",
+	}
+}
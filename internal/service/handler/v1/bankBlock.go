@@ -3,26 +3,60 @@ package v1
 import (
 	"bytes"
 	"math"
+	"net/http"
 
-	go2 "github.com/adam-hanna/arrayOperations"
 	"github.com/hodl-repos/pdf-invoice/internal/dto"
 	"github.com/hodl-repos/pdf-invoice/pkg/bank"
 	"github.com/hodl-repos/pdf-invoice/pkg/document"
 	"github.com/hodl-repos/pdf-invoice/pkg/localize"
 	"github.com/hodl-repos/pdf-invoice/pkg/qr"
+	"github.com/hodl-repos/pdf-invoice/pkg/standardisedError"
 	"github.com/jung-kurt/gofpdf"
+	"github.com/shopspring/decimal"
+	"github.com/skip2/go-qrcode"
+	"golang.org/x/text/currency"
 )
 
-// as this function is called at first - checks for site-breaks are made
-func generateBankBlock(data *dto.DocumentDto, pdf *document.Doc, localizeClient *localize.LocalizeClient) error {
+// as this function is called at first - checks for site-breaks are made, the
+// qr code is only drawn for positive amounts as nothing is payable otherwise
+func generateBankBlock(data *dto.BankPaymentDto, cur currency.Unit, amount decimal.Decimal, pdf *document.Doc, style *documentStyle, localizeClient *localize.LocalizeClient) error {
 	//prepare data
-	bankDto := generateEpcFromDto(data)
-	bankText := prepareBankText(data.BankPaymentData, localizeClient)
+	bankText := prepareBankText(data, localizeClient)
+
+	withCode := amount.IsPositive()
+
+	var qrCode *[]byte
+	if withCode {
+		bankDto, err := generateEpcFromDto(data, cur, amount)
+		if err != nil {
+			return err
+		}
+
+		code, err := bankDto.GenerateCode()
+		if err != nil {
+			return &standardisedError.StandardisedError{
+				Type:   "validation-error",
+				Title:  "could not generate the payment qr code",
+				Status: http.StatusBadRequest,
+				Detail: err.Error(),
+			}
+		}
+
+		//EPC069-12 requires the error correction level M
+		qrCode, err = qr.GenerateQrCode(code, qr.WithRecoveryLevel(qrcode.Medium))
+		if err != nil {
+			return err
+		}
+	}
 
-	qr, _ := qr.GenerateQrCode(bankDto.GenerateCode())
+	//30 on the left is reserved for the qr code
+	codeWidth := 0.0
+	if withCode {
+		codeWidth = 30
+	}
 
 	//calculate if new page is needed
-	lines := pdf.SplitText(bankText, pdf.GetPrintWidth()-30) //30 on the left is reserved for the qr code
+	lines := pdf.SplitText(bankText, pdf.GetPrintWidth()-codeWidth)
 	totalTextHeight := float64(len(lines)) * pdf.GetFontLineHeight()
 	totalBlockHeight := totalTextHeight + 10 //add 10, 5 top and 5 bottom margin
 
@@ -34,40 +68,73 @@ func generateBankBlock(data *dto.DocumentDto, pdf *document.Doc, localizeClient
 	l, t, r, _ := pdf.GetMargins()
 	currentPosition := pdf.GetY()
 
-	pdf.SetMargins(l+30, t, r)
-	pdf.SetXY(l+30, currentPosition+5)
+	//the text is indented by 5 without qr code
+	textOffset := codeWidth
+	if !withCode {
+		textOffset = 5
+	}
+
+	pdf.SetMargins(l+textOffset, t, r)
+	pdf.SetXY(l+textOffset, currentPosition+5)
 	pdf.MCell(0, pdf.GetFontLineHeight(), bankText, "", "LM", false)
 	newPosition := pdf.GetY()
 
 	spaceY := newPosition - (currentPosition + 5)
 
-	imageSize := math.Min(25, spaceY)
+	if withCode {
+		imageSize := math.Min(25, spaceY)
 
-	leftMargin := (30 - imageSize) / 2
-	topMargin := (spaceY - imageSize) / 2
+		leftMargin := (30 - imageSize) / 2
+		topMargin := (spaceY - imageSize) / 2
 
-	pdf.RegisterImageOptionsReader("banktransfer-qr-code", gofpdf.ImageOptions{ImageType: "png", ReadDpi: true}, bytes.NewReader(*qr))
-	pdf.ImageOptions("banktransfer-qr-code", l+leftMargin, currentPosition+5+topMargin, imageSize, imageSize, false, gofpdf.ImageOptions{ReadDpi: true}, 0, "")
+		pdf.RegisterImageOptionsReader("banktransfer-qr-code", gofpdf.ImageOptions{ImageType: "png", ReadDpi: true}, bytes.NewReader(*qrCode))
+		pdf.ImageOptions("banktransfer-qr-code", l+leftMargin, currentPosition+5+topMargin, imageSize, imageSize, false, gofpdf.ImageOptions{ReadDpi: true}, 0, "")
+	}
 
 	pdf.SetMargins(l, t, r)
 
+	drawColor := pdf.GetDrawColorSpec()
+	pdf.SetDrawColorSpec(style.bankBlockBorder)
 	pdf.Rect(l, currentPosition, pdf.GetPrintWidth(), spaceY+10, "D")
+	pdf.SetDrawColorSpec(drawColor)
 
 	pdf.SetY(newPosition + 5)
 
 	return nil
 }
 
-func generateEpcFromDto(data *dto.DocumentDto) bank.EpcDto {
+// the payable amount of a document is the total after the document discount,
+// nothing is payable for credit notes
+func payableAmount(data *dto.DocumentDto, kind *documentKind, cur currency.Unit) (decimal.Decimal, error) {
+	if kind.negative {
+		return decimal.Zero, nil
+	}
+
+	totals, err := calculateInvoiceTotals(data.InvoiceData, cur)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	return totals.gross, nil
+}
+
+func generateEpcFromDto(data *dto.BankPaymentDto, cur currency.Unit, amount decimal.Decimal) (bank.EpcDto, error) {
 	bankDto := bank.EpcDto{}
 	bankDto.SetDefaults()
-	bankDto.InvoiceReference = data.BankPaymentData.PaymentReference
-	bankDto.Text = data.BankPaymentData.RemittanceInformation
-	bankDto.Name = data.BankPaymentData.AccountHolder
-	bankDto.IBAN = data.BankPaymentData.IBAN
-	bankDto.BIC = data.BankPaymentData.BIC
-
-	sum := go2.Reduce(*data.InvoiceData.Rows, func(f float64, ird dto.InvoiceRowDto) float64 { return *ird.Gross }, 0.0)
-	bankDto.SetAmount(sum)
-	return bankDto
+	bankDto.SetRemittance(data.PaymentReference, data.RemittanceInformation)
+	bankDto.Purpose = data.Purpose
+	bankDto.Name = data.AccountHolder
+	bankDto.IBAN = data.IBAN
+	bankDto.BIC = data.BIC
+
+	if err := bankDto.SetAmount(cur, *floatPtr(amount)); err != nil {
+		return bankDto, &standardisedError.StandardisedError{
+			Type:   "validation-error",
+			Title:  "could not generate the payment qr code",
+			Status: http.StatusBadRequest,
+			Detail: err.Error(),
+		}
+	}
+
+	return bankDto, nil
 }
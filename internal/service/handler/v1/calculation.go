@@ -0,0 +1,206 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+
+	"github.com/hodl-repos/pdf-invoice/internal/dto"
+	"github.com/hodl-repos/pdf-invoice/pkg/calculation"
+	"github.com/hodl-repos/pdf-invoice/pkg/standardisedError"
+	"github.com/shopspring/decimal"
+	"golang.org/x/text/currency"
+)
+
+// calculates net, tax and gross of all rows when a calculation mode is set,
+// the calculated values replace the values of the request
+func calculateInvoiceRows(data *dto.InvoiceDto, cur currency.Unit) error {
+	if data.CalculationMode == nil || data.Rows == nil {
+		return nil
+	}
+
+	rows := *data.Rows
+	lines := make([]calculation.Line, len(rows))
+
+	for i, row := range rows {
+		if row.UnitPrice == nil || row.TaxPercentage == nil {
+			return &standardisedError.StandardisedError{
+				Type:   "validation-error",
+				Title:  "could not calculate the invoice",
+				Status: http.StatusBadRequest,
+				Detail: fmt.Sprintf("row %v needs a unitPrice and a taxPercentage when using a calculationMode", i+1),
+			}
+		}
+
+		lines[i] = calculation.Line{
+			Quantity:           decimalFromPtr(row.Amount),
+			UnitPrice:          decimal.NewFromFloat(*row.UnitPrice),
+			TaxRate:            decimal.NewFromFloat(*row.TaxPercentage),
+			DiscountPercentage: decimalFromPtr(row.DiscountPercentage),
+			DiscountFixed:      decimalFromPtr(row.DiscountFixed),
+		}
+	}
+
+	res, err := calculation.Calculate(lines, *data.CalculationMode, withCurrency(cur))
+	if err != nil {
+		return err
+	}
+
+	for i := range rows {
+		rows[i].Net = floatPtr(res.Lines[i].Net)
+		rows[i].Tax = floatPtr(res.Lines[i].Tax)
+		rows[i].Gross = floatPtr(res.Lines[i].Gross)
+	}
+
+	return nil
+}
+
+// sum of all rows with the same tax percentage
+type taxBreakdownLine struct {
+	rate decimal.Decimal
+	net  decimal.Decimal
+	// share of the document discount
+	discount decimal.Decimal
+	tax      decimal.Decimal
+}
+
+// net of the rows reduced by the document discount
+func (l *taxBreakdownLine) taxable() decimal.Decimal {
+	return l.net.Sub(l.discount)
+}
+
+// totals of the invoice after the document discount
+type invoiceTotals struct {
+	// sum of the row nets
+	net      decimal.Decimal
+	discount decimal.Decimal
+	tax      decimal.Decimal
+	gross    decimal.Decimal
+	// taxes are sorted by rate
+	taxes []taxBreakdownLine
+}
+
+// sums the rows per tax percentage and distributes the document discount
+// proportionally to the tax rates, the tax of a rate is reduced by the tax of
+// its discount share
+func calculateInvoiceTotals(data *dto.InvoiceDto, cur currency.Unit) (*invoiceTotals, error) {
+	groups := make(map[string]*taxBreakdownLine)
+	totals := &invoiceTotals{}
+
+	if data.Rows != nil {
+		for _, item := range *data.Rows {
+			if item.Net == nil {
+				return nil, errors.New("NET NOT DEFINED IN ONE COLUMN")
+			}
+			if item.Tax == nil {
+				return nil, errors.New("TAX-VALUE IS NOT DEFINED IN ONE ROW")
+			}
+
+			rate := decimal.NewFromFloat(rowTaxRate(&item))
+
+			//20 and 20.0 are the same rate
+			key := rate.String()
+			group, ok := groups[key]
+			if !ok {
+				group = &taxBreakdownLine{rate: rate}
+				groups[key] = group
+			}
+
+			group.net = group.net.Add(decimal.NewFromFloat(*item.Net))
+			group.tax = group.tax.Add(decimal.NewFromFloat(*item.Tax))
+		}
+	}
+
+	for _, group := range groups {
+		totals.taxes = append(totals.taxes, *group)
+		totals.net = totals.net.Add(group.net)
+	}
+
+	sort.Slice(totals.taxes, func(i, j int) bool { return totals.taxes[i].rate.LessThan(totals.taxes[j].rate) })
+
+	discount, err := calculation.Discount(totals.net, decimalFromPtr(data.SumDiscountPercentage), decimalFromPtr(data.SumDiscountFixed), withCurrency(cur))
+	if err != nil {
+		return nil, &standardisedError.StandardisedError{
+			Type:   "validation-error",
+			Title:  "could not calculate the invoice",
+			Status: http.StatusBadRequest,
+			Detail: err.Error(),
+		}
+	}
+	totals.discount = discount
+
+	weights := make([]decimal.Decimal, len(totals.taxes))
+	for i, group := range totals.taxes {
+		weights[i] = group.net
+	}
+
+	for i, share := range calculation.Distribute(discount, weights, withCurrency(cur)) {
+		group := &totals.taxes[i]
+		group.discount = share
+
+		if data.CalculationMode != nil && *data.CalculationMode == calculation.RoundingPerDocument {
+			group.tax = calculation.Tax(group.taxable(), group.rate, withCurrency(cur))
+		} else {
+			group.tax = group.tax.Sub(calculation.Tax(share, group.rate, withCurrency(cur)))
+		}
+
+		totals.tax = totals.tax.Add(group.tax)
+	}
+
+	totals.gross = totals.net.Sub(totals.discount).Add(totals.tax)
+
+	return totals, nil
+}
+
+// currency of the document, EUR when not set
+func documentCurrency(code *string) (currency.Unit, error) {
+	if code == nil {
+		return currency.EUR, nil
+	}
+
+	cur, err := currency.ParseISO(*code)
+	if err != nil {
+		return currency.Unit{}, &standardisedError.StandardisedError{
+			Type:   "validation-error",
+			Title:  "unknown currency",
+			Status: http.StatusBadRequest,
+			Detail: fmt.Sprintf("%s is not an ISO 4217 currency code", *code),
+		}
+	}
+
+	return cur, nil
+}
+
+// rounds amounts to the minor units of the currency
+func withCurrency(cur currency.Unit) calculation.Option {
+	scale, _ := currency.Standard.Rounding(cur)
+	return calculation.WithPlaces(int32(scale))
+}
+
+// tax percentage of a row, calculated from net and tax when not given
+func rowTaxRate(row *dto.InvoiceRowDto) float64 {
+	if row.TaxPercentage != nil {
+		return *row.TaxPercentage
+	}
+
+	if row.Tax != nil && row.Net != nil && *row.Net != 0 {
+		return math.Round(*row.Tax / *row.Net * 10000) / 100
+	}
+
+	return 0
+}
+
+func decimalFromPtr(value *float64) decimal.Decimal {
+	if value == nil {
+		return decimal.Zero
+	}
+
+	return decimal.NewFromFloat(*value)
+}
+
+func floatPtr(value decimal.Decimal) *float64 {
+	f, _ := value.Float64()
+	return &f
+}
@@ -0,0 +1,22 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/hodl-repos/pdf-invoice/internal/dto"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/currency"
+)
+
+func TestCalculateInvoiceTotalsNegativeNet(t *testing.T) {
+	net, tax, rate := -100., -20., 20.
+	rows := []dto.InvoiceRowDto{{Net: &net, Tax: &tax, TaxPercentage: &rate}}
+
+	// the rows of a credit note are summed without discount
+	totals, err := calculateInvoiceTotals(&dto.InvoiceDto{Rows: &rows}, currency.EUR)
+	assert.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(-100).Equal(totals.net), "net %s", totals.net)
+	assert.True(t, decimal.NewFromInt(-120).Equal(totals.gross), "gross %s", totals.gross)
+	assert.True(t, totals.discount.IsZero(), "discount %s", totals.discount)
+}
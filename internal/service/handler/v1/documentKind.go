@@ -0,0 +1,95 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/hodl-repos/pdf-invoice/internal/dto"
+	"github.com/hodl-repos/pdf-invoice/pkg/localize"
+	"github.com/shopspring/decimal"
+)
+
+// documentKind describes how the type of the document (invoice, offer, credit
+// note, delivery note or order confirmation) is rendered
+type documentKind struct {
+	//localized name of the document type, e.g. for the title
+	name string
+	//localized label of the number in the information block
+	numberLabel string
+	//number and date of the document, empty for documents without number
+	number string
+	date   *time.Time
+
+	//amounts are shown negative, the calculation itself uses positive amounts
+	negative bool
+	//prices and sums are shown, otherwise only quantities
+	priced bool
+	//the bank payment block is shown
+	payable bool
+	//serial and batch numbers of the rows and a signature field are shown
+	delivery bool
+}
+
+func newDocumentKind(data *dto.InvoiceInformationDto, localizeClient *localize.LocalizeClient) *documentKind {
+	kind := &documentKind{priced: true, payable: true}
+
+	switch {
+	case data.IsCreditNote():
+		kind.name = localizeClient.TranslateCreditNote()
+		kind.numberLabel = localizeClient.TranslateCreditNoteNumber()
+		kind.negative = true
+	case data.IsDeliveryNote():
+		kind.name = localizeClient.TranslateDeliveryNote()
+		kind.numberLabel = localizeClient.TranslateDeliveryNoteNumber()
+		kind.priced = false
+		kind.payable = false
+		kind.delivery = true
+	case data.IsOrderConfirmation():
+		kind.name = localizeClient.TranslateOrderConfirmation()
+		kind.numberLabel = localizeClient.TranslateOrderNumber()
+		kind.payable = false
+	case data.InvoiceNumber != nil:
+		kind.name = localizeClient.TranslateInvoice()
+		kind.numberLabel = localizeClient.TranslateInvoiceNumber()
+	default:
+		kind.name = localizeClient.TranslateOffer()
+		kind.numberLabel = localizeClient.TranslateOfferNumber()
+	}
+
+	if data.InvoiceNumber != nil {
+		kind.number = *data.InvoiceNumber
+		kind.date = data.InvoiceDate
+	} else if data.OfferNumber != nil {
+		kind.number = *data.OfferNumber
+		kind.date = data.OfferDate
+	}
+
+	return kind
+}
+
+// title of the document: the name followed by the number
+func (k *documentKind) title() string {
+	if len(k.number) == 0 {
+		return k.name
+	}
+
+	return k.name + " " + k.number
+}
+
+// signed returns the value as shown on the document
+func (k *documentKind) signed(value *float64) *float64 {
+	if value == nil || !k.negative || *value == 0 {
+		return value
+	}
+
+	negated := -*value
+	return &negated
+}
+
+// amount returns the calculated value as shown on the document
+func (k *documentKind) amount(value decimal.Decimal) *float64 {
+	if k.negative {
+		value = value.Neg()
+	}
+
+	return floatPtr(value)
+}
@@ -0,0 +1,112 @@
+package v1
+
+import (
+	"github.com/hodl-repos/pdf-invoice/internal/dto"
+	"github.com/hodl-repos/pdf-invoice/pkg/assetstore"
+	"github.com/hodl-repos/pdf-invoice/pkg/document"
+	"github.com/hodl-repos/pdf-invoice/pkg/fetcher"
+	"github.com/hodl-repos/pdf-invoice/pkg/fontstore"
+	"github.com/hodl-repos/pdf-invoice/pkg/localize"
+)
+
+// GenerateDunning creates a payment reminder or dunning letter listing the open
+// invoices, the escalation text depends on the level
+func GenerateDunning(data *dto.DunningDto, localizeClient *localize.LocalizeClient, fontStore *fontstore.FontStore, assetStore *assetstore.AssetStore, imageFetcher *fetcher.Fetcher) (*document.Doc, error) {
+	style, err := resolveDocumentStyle(data.Style, fontStore)
+	if err != nil {
+		return nil, err
+	}
+
+	pdf := newDocument(data.Style, data.SellerInformation, style, assetStore, imageFetcher, localizeClient)
+
+	info := data.DunningInformation
+	title := dunningTitle(*info.Level, localizeClient)
+	if info.DunningNumber != nil {
+		pdf.SetTitle(title+" "+*info.DunningNumber, true)
+	} else {
+		pdf.SetTitle(title, true)
+	}
+
+	cur, err := documentCurrency(data.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := calculateDunningTotals(*data.OpenInvoices)
+
+	//generate header block
+	information := prepareDunningInformationCells(info, localizeClient)
+	if err := generateHeaderBlock(data.Style, data.InvoiceAddress, information, pdf, style); err != nil {
+		return nil, err
+	}
+
+	//append customer-address if provided
+	if data.CustomerAddress != nil {
+		generateCustomerAddressBlock(data.CustomerAddress, pdf, style, localizeClient)
+	}
+
+	//title and escalation text
+	pdf.SetFontSpec(style.heading)
+	pdf.SetTextColorSpec(style.primary)
+	pdf.MCell(0, pdf.GetFontLineHeight(), title, "", "", false)
+
+	pdf.SetFontSpec(style.body)
+	pdf.SetTextColorSpec(style.text)
+	pdf.Ln(pdf.GetFontLineHeight() / 2)
+	pdf.MCell(0, pdf.GetFontLineHeight(), dunningText(info, formatMoney(floatPtr(totals.outstanding), cur, localizeClient), localizeClient), "", "", false)
+	pdf.Ln(pdf.GetFontLineHeight())
+
+	//generate open invoices
+	generateDunningBlock(*data.OpenInvoices, cur, pdf, style, localizeClient)
+
+	pdf.Ln(pdf.GetFontLineHeight())
+
+	//append data suffix if provided
+	if data.DunningDataSuffix != nil {
+		pdf.SetFontSpec(style.body)
+		pdf.RichText(0, pdf.GetFontLineHeight(), *data.DunningDataSuffix, "")
+
+		pdf.Ln(pdf.GetFontLineHeight())
+	}
+
+	//generate bank-payment-block with the outstanding amount
+	if data.BankPaymentData != nil && data.Style.ShowBankPaymentQrCode != nil && *data.Style.ShowBankPaymentQrCode {
+		if err := generateBankBlock(data.BankPaymentData, cur, totals.outstanding, pdf, style, localizeClient); err != nil {
+			return nil, err
+		}
+	}
+
+	return pdf, nil
+}
+
+func dunningTitle(level dto.DunningLevel, localizeClient *localize.LocalizeClient) string {
+	switch level {
+	case dto.DunningLevelSecondReminder:
+		return localizeClient.TranslateSecondReminder()
+	case dto.DunningLevelFinalNotice:
+		return localizeClient.TranslateFinalNotice()
+	default:
+		return localizeClient.TranslatePaymentReminder()
+	}
+}
+
+// the escalation text of the request or the localized text of the level
+func dunningText(data *dto.DunningInformationDto, amount string, localizeClient *localize.LocalizeClient) string {
+	dueDate := data.DueDate.Format("2006-01-02")
+
+	if data.EscalationText != nil {
+		return fillPlaceholders(*data.EscalationText, map[string]string{
+			"Amount":  amount,
+			"DueDate": dueDate,
+		})
+	}
+
+	switch *data.Level {
+	case dto.DunningLevelSecondReminder:
+		return localizeClient.TranslateDunningTextSecondReminder(amount, dueDate)
+	case dto.DunningLevelFinalNotice:
+		return localizeClient.TranslateDunningTextFinalNotice(amount, dueDate)
+	default:
+		return localizeClient.TranslateDunningTextPaymentReminder(amount, dueDate)
+	}
+}
@@ -0,0 +1,164 @@
+package v1
+
+import (
+	"github.com/hodl-repos/pdf-invoice/internal/dto"
+	"github.com/hodl-repos/pdf-invoice/pkg/document"
+	"github.com/hodl-repos/pdf-invoice/pkg/localize"
+	"github.com/shopspring/decimal"
+	"golang.org/x/text/currency"
+)
+
+// sums of the open invoices of a dunning letter
+type dunningTotals struct {
+	amount   decimal.Decimal
+	paid     decimal.Decimal
+	fees     decimal.Decimal
+	interest decimal.Decimal
+	// amount - paid + fees + interest
+	outstanding decimal.Decimal
+}
+
+func (t *dunningTotals) add(invoice *dto.OpenInvoiceDto) {
+	t.amount = t.amount.Add(decimalFromPtr(invoice.Amount))
+	t.paid = t.paid.Add(decimalFromPtr(invoice.Paid))
+	t.fees = t.fees.Add(decimalFromPtr(invoice.Fees))
+	t.interest = t.interest.Add(decimalFromPtr(invoice.Interest))
+	t.outstanding = t.amount.Sub(t.paid).Add(t.fees).Add(t.interest)
+}
+
+func calculateDunningTotals(invoices []dto.OpenInvoiceDto) *dunningTotals {
+	totals := &dunningTotals{}
+
+	for i := range invoices {
+		totals.add(&invoices[i])
+	}
+
+	return totals
+}
+
+func generateDunningBlock(invoices []dto.OpenInvoiceDto, cur currency.Unit, pdf *document.Doc, style *documentStyle, localizeClient *localize.LocalizeClient) {
+	rawData := prepareDunningData(invoices, cur, localizeClient)
+
+	table, _ := document.NewDocTable(pdf, rawData)
+	table.SetAllCellBorders(false)
+	table.SetHeadType(document.HeadFirstRow)
+	table.SetHeadFont(style.tableHead)
+	table.SetAllCellPaddings(document.Padding{1, 1, 1, 1})
+
+	//the invoice number takes the remaining width, all other columns fit their content
+	nrCols := len(rawData[0])
+	colTypes := make([]document.ColumnType, 0)
+	alTypes := make([]document.AlignmentType, 0)
+	cellTypes := make([]document.CellType, 0)
+	colTypes = append(colTypes, document.ColDyn)
+	alTypes = append(alTypes, document.AlignLeft)
+	cellTypes = append(cellTypes, document.CellMulti)
+	for i := 1; i < nrCols; i++ {
+		colTypes = append(colTypes, document.ColCalc)
+		alTypes = append(alTypes, document.AlignRight)
+		cellTypes = append(cellTypes, document.CellSingle)
+	}
+	table.SetColTypes(colTypes)
+	table.SetCellAlingsPerColumn(alTypes)
+	table.SetCellTypesPerColumn(cellTypes)
+
+	//highlight head and totals
+	bg := fillColorFunc(style.tableHeadBackground)
+	table.SetCellStyleFuncsRow(0, bg)
+	table.SetCellStyleFuncsRow(len(rawData)-1, bg)
+	table.SetCellTextColorsRow(0, &style.accent)
+
+	table.Generate()
+}
+
+// optional columns of the open invoices, a column is shown if at least one
+// invoice has a value
+type dunningColumns struct {
+	dueDate  bool
+	paid     bool
+	fees     bool
+	interest bool
+}
+
+func newDunningColumns(invoices []dto.OpenInvoiceDto) dunningColumns {
+	var columns dunningColumns
+	for _, invoice := range invoices {
+		columns.dueDate = columns.dueDate || invoice.DueDate != nil
+		columns.paid = columns.paid || invoice.Paid != nil
+		columns.fees = columns.fees || invoice.Fees != nil
+		columns.interest = columns.interest || invoice.Interest != nil
+	}
+
+	return columns
+}
+
+// row of the shown columns
+func (c dunningColumns) row(number, date, dueDate, amount, paid, fees, interest, outstanding string) []string {
+	row := []string{number, date}
+	if c.dueDate {
+		row = append(row, dueDate)
+	}
+	row = append(row, amount)
+	if c.paid {
+		row = append(row, paid)
+	}
+	if c.fees {
+		row = append(row, fees)
+	}
+	if c.interest {
+		row = append(row, interest)
+	}
+	return append(row, outstanding)
+}
+
+// prepared rows of the open invoices with the optional columns, see
+// dunningColumns, the last row contains the totals
+func prepareDunningData(invoices []dto.OpenInvoiceDto, cur currency.Unit, localizeClient *localize.LocalizeClient) [][]string {
+	line := newDunningColumns(invoices).row
+
+	tmp := make([][]string, 0)
+
+	tmp = append(tmp, line(
+		localizeClient.TranslateInvoiceNumber(),
+		localizeClient.TranslateDate(),
+		localizeClient.TranslateDueOn(),
+		localizeClient.TranslateInvoiceAmount(),
+		localizeClient.TranslatePaid(),
+		localizeClient.TranslateFees(),
+		localizeClient.TranslateInterest(),
+		localizeClient.TranslateOutstanding()))
+
+	for _, invoice := range invoices {
+		dueDate := "-"
+		if invoice.DueDate != nil {
+			dueDate = invoice.DueDate.Format("2006-01-02")
+		}
+
+		row := dunningTotals{}
+		row.add(&invoice)
+
+		tmp = append(tmp, line(
+			*invoice.InvoiceNumber,
+			invoice.InvoiceDate.Format("2006-01-02"),
+			dueDate,
+			formatMoney(invoice.Amount, cur, localizeClient),
+			formatMoney(invoice.Paid, cur, localizeClient),
+			formatMoney(invoice.Fees, cur, localizeClient),
+			formatMoney(invoice.Interest, cur, localizeClient),
+			formatMoney(floatPtr(row.outstanding), cur, localizeClient)))
+	}
+
+	totals := calculateDunningTotals(invoices)
+
+	tmp = append(tmp, line(
+		localizeClient.TranslateTotal(),
+		"",
+		"",
+		formatMoney(floatPtr(totals.amount), cur, localizeClient),
+		formatMoney(floatPtr(totals.paid), cur, localizeClient),
+		formatMoney(floatPtr(totals.fees), cur, localizeClient),
+		formatMoney(floatPtr(totals.interest), cur, localizeClient),
+		formatMoney(floatPtr(totals.outstanding), cur, localizeClient)))
+
+	return tmp
+}
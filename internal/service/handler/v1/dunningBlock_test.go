@@ -0,0 +1,82 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hodl-repos/pdf-invoice/internal/dto"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func float(value float64) *float64 {
+	return &value
+}
+
+func assertDecimal(t *testing.T, expected string, actual decimal.Decimal) {
+	t.Helper()
+	assert.True(t, decimal.RequireFromString(expected).Equal(actual), "expected %s, got %s", expected, actual)
+}
+
+func openInvoice(number string, amount float64) dto.OpenInvoiceDto {
+	date := time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC)
+	return dto.OpenInvoiceDto{InvoiceNumber: &number, InvoiceDate: &date, Amount: &amount}
+}
+
+func TestCalculateDunningTotals(t *testing.T) {
+	first := openInvoice("RE-1", 100.1)
+	first.Paid = float(50.05)
+	first.Fees = float(5)
+
+	second := openInvoice("RE-2", 0.2)
+	second.Interest = float(0.1)
+
+	totals := calculateDunningTotals([]dto.OpenInvoiceDto{first, second})
+	assertDecimal(t, "100.3", totals.amount)
+	assertDecimal(t, "50.05", totals.paid)
+	assertDecimal(t, "5", totals.fees)
+	assertDecimal(t, "0.1", totals.interest)
+
+	// 100.3 - 50.05 + 5 + 0.1 without float rounding errors
+	assertDecimal(t, "55.35", totals.outstanding)
+
+	// the outstanding amount of each invoice
+	row := dunningTotals{}
+	row.add(&second)
+	assertDecimal(t, "0.3", row.outstanding)
+
+	// overpaid invoices reduce the outstanding amount
+	third := openInvoice("RE-3", 10)
+	third.Paid = float(15)
+	totals = calculateDunningTotals([]dto.OpenInvoiceDto{first, third})
+	assertDecimal(t, "50.05", totals.outstanding)
+
+	assertDecimal(t, "0", calculateDunningTotals(nil).outstanding)
+}
+
+func TestDunningColumns(t *testing.T) {
+	row := func(columns dunningColumns) []string {
+		return columns.row("number", "date", "dueDate", "amount", "paid", "fees", "interest", "outstanding")
+	}
+
+	// only the required columns without optional values
+	columns := newDunningColumns([]dto.OpenInvoiceDto{openInvoice("RE-1", 10), openInvoice("RE-2", 20)})
+	assert.Equal(t, dunningColumns{}, columns)
+	assert.Equal(t, []string{"number", "date", "amount", "outstanding"}, row(columns))
+
+	// a column is shown if one invoice has a value
+	dueDate := time.Date(2023, 2, 10, 0, 0, 0, 0, time.UTC)
+	first := openInvoice("RE-1", 10)
+	first.DueDate = &dueDate
+	second := openInvoice("RE-2", 20)
+	second.Interest = float(0)
+
+	columns = newDunningColumns([]dto.OpenInvoiceDto{first, second})
+	assert.Equal(t, dunningColumns{dueDate: true, interest: true}, columns)
+	assert.Equal(t, []string{"number", "date", "dueDate", "amount", "interest", "outstanding"}, row(columns))
+
+	second.Paid = float(5)
+	second.Fees = float(1)
+	columns = newDunningColumns([]dto.OpenInvoiceDto{first, second})
+	assert.Equal(t, []string{"number", "date", "dueDate", "amount", "paid", "fees", "interest", "outstanding"}, row(columns))
+}
@@ -0,0 +1,283 @@
+package v1
+
+import (
+	"math"
+	"net/http"
+
+	"github.com/hodl-repos/pdf-invoice/internal/dto"
+	"github.com/hodl-repos/pdf-invoice/pkg/document"
+	"github.com/hodl-repos/pdf-invoice/pkg/einvoice"
+	"github.com/hodl-repos/pdf-invoice/pkg/standardisedError"
+)
+
+// embeds the factur-x xml and the corresponding xmp metadata into the pdf
+func attachFacturX(data *dto.DocumentDto, pdf *document.Doc) error {
+	profile := *data.Style.FacturXProfile
+
+	invoice, err := generateEInvoiceFromDto(data)
+	if err != nil {
+		return err
+	}
+
+	xmlData, err := einvoice.GenerateCII(invoice, profile)
+	if err != nil {
+		return err
+	}
+
+	//MINIMUM is not a valid invoice on its own, all other profiles are equivalent to the pdf
+	relationship := document.AFRelationshipAlternative
+	if profile == einvoice.ProfileMinimum {
+		relationship = document.AFRelationshipData
+	}
+
+	pdf.AddAttachment(document.Attachment{
+		Content:      xmlData,
+		Filename:     einvoice.FacturXFilename,
+		Description:  "Factur-X/ZUGFeRD invoice",
+		MimeType:     "text/xml",
+		Relationship: relationship,
+	})
+	pdf.AddXmpDescription(einvoice.FacturXXmpDescription(profile))
+
+	return nil
+}
+
+// generates the xrechnung xml of the document in the given syntax
+func GenerateXRechnung(data *dto.DocumentDto, syntax einvoice.Syntax) ([]byte, error) {
+	cur, err := documentCurrency(data.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := calculateInvoiceRows(data.InvoiceData, cur); err != nil {
+		return nil, err
+	}
+
+	invoice, err := generateEInvoiceFromDto(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return einvoice.Generate(invoice, einvoice.ProfileXRechnung, syntax)
+}
+
+func generateEInvoiceFromDto(data *dto.DocumentDto) (*einvoice.Invoice, error) {
+	info := data.InvoiceInformation
+
+	if info.InvoiceNumber == nil {
+		return nil, &standardisedError.StandardisedError{
+			Type:   "validation-error",
+			Title:  "could not generate an electronic invoice",
+			Status: http.StatusBadRequest,
+			Detail: "electronic invoices can only be generated for invoices, not for offers",
+		}
+	}
+
+	if info.IsDeliveryNote() || info.IsOrderConfirmation() {
+		return nil, &standardisedError.StandardisedError{
+			Type:   "validation-error",
+			Title:  "could not generate an electronic invoice",
+			Status: http.StatusBadRequest,
+			Detail: "electronic invoices can only be generated for invoices and credit notes",
+		}
+	}
+
+	if data.InvoiceData.Rows == nil {
+		return nil, &standardisedError.StandardisedError{
+			Type:   "validation-error",
+			Title:  "could not generate an electronic invoice",
+			Status: http.StatusBadRequest,
+			Detail: "electronic invoices require the rows of the invoice data",
+		}
+	}
+
+	//both are the buyer reference (BT-10) of the electronic invoice
+	if info.BuyerReference != nil && info.LeitwegID != nil {
+		return nil, &standardisedError.StandardisedError{
+			Type:   "validation-error",
+			Title:  "could not generate an electronic invoice",
+			Status: http.StatusBadRequest,
+			Detail: "buyerReference and leitwegId cannot be given both",
+		}
+	}
+
+	cur, err := documentCurrency(data.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice := &einvoice.Invoice{
+		Number:    *info.InvoiceNumber,
+		TypeCode:  einvoice.TypeCodeInvoice,
+		IssueDate: *info.InvoiceDate,
+		DueDate:   info.DueDate,
+		Currency:  cur.String(),
+		Seller:    generateEInvoiceSellerFromDto(data.SellerInformation),
+		Buyer:     generateEInvoicePartyFromDto(data.InvoiceAddress.AddressDto),
+	}
+
+	//the amounts of credit notes are positive, the type code reverses them
+	if info.IsCreditNote() {
+		invoice.TypeCode = einvoice.TypeCodeCreditNote
+		invoice.PrecedingInvoice = &einvoice.DocumentReference{
+			Number:    *info.CorrectedInvoiceNumber,
+			IssueDate: *info.CorrectedInvoiceDate,
+		}
+	}
+
+	if data.InvoiceAddress.VAT != nil {
+		invoice.Buyer.VATID = *data.InvoiceAddress.VAT
+	}
+
+	if data.InvoiceAddress.Email != nil {
+		invoice.Buyer.Email = *data.InvoiceAddress.Email
+	}
+
+	if info.BuyerReference != nil {
+		invoice.BuyerReference = *info.BuyerReference
+	}
+
+	//public sector customers are addressed by their leitweg-id
+	if info.LeitwegID != nil {
+		invoice.BuyerReference = *info.LeitwegID
+		invoice.Buyer.ElectronicAddress = *info.LeitwegID
+		invoice.Buyer.ElectronicAddressScheme = einvoice.SchemeLeitwegID
+	}
+
+	if data.InvoiceDataSuffix != nil {
+		invoice.Notes = append(invoice.Notes, document.PlainRichText(*data.InvoiceDataSuffix))
+	}
+
+	for _, row := range *data.InvoiceData.Rows {
+		line, err := generateEInvoiceLineFromDto(&row)
+		if err != nil {
+			return nil, err
+		}
+
+		invoice.Lines = append(invoice.Lines, *line)
+	}
+
+	//the document discount is distributed to the tax rates
+	totals, err := calculateInvoiceTotals(data.InvoiceData, cur)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, group := range totals.taxes {
+		if group.discount.IsZero() {
+			continue
+		}
+
+		amount, _ := group.discount.Float64()
+		rate, _ := group.rate.Float64()
+		invoice.Allowances = append(invoice.Allowances, einvoice.Allowance{Amount: amount, TaxRate: rate})
+	}
+
+	if bank := data.BankPaymentData; bank != nil {
+		invoice.Payment = &einvoice.Payment{
+			IBAN:        *bank.IBAN,
+			AccountName: *bank.AccountHolder,
+		}
+
+		if bank.BIC != nil {
+			invoice.Payment.BIC = *bank.BIC
+		}
+
+		if bank.PaymentReference != nil {
+			invoice.Payment.Reference = *bank.PaymentReference
+		}
+	}
+
+	return invoice, nil
+}
+
+func generateEInvoiceSellerFromDto(data *dto.SellerInformationDto) einvoice.Party {
+	party := generateEInvoicePartyFromDto(data.Address)
+
+	if data.VAT != nil {
+		party.VATID = *data.VAT
+	}
+
+	if data.CorporateRegisterNumber != nil {
+		party.CorporateRegisterNumber = *data.CorporateRegisterNumber
+	}
+
+	if data.Email != nil {
+		party.Email = *data.Email
+	}
+
+	if data.Phone != nil {
+		party.Phone = *data.Phone
+	}
+
+	return party
+}
+
+func generateEInvoicePartyFromDto(data *dto.AddressDto) einvoice.Party {
+	party := einvoice.Party{
+		Name: *data.Name,
+	}
+
+	if data.Street1 != nil {
+		party.Street1 = *data.Street1
+	}
+
+	if data.Street2 != nil {
+		party.Street2 = *data.Street2
+	}
+
+	if data.Zip != nil {
+		party.Zip = *data.Zip
+	}
+
+	if data.City != nil {
+		party.City = *data.City
+	}
+
+	if data.Country != nil {
+		party.CountryCode = einvoice.CountryCode(*data.Country)
+	}
+
+	return party
+}
+
+func generateEInvoiceLineFromDto(row *dto.InvoiceRowDto) (*einvoice.Line, error) {
+	if row.Net == nil {
+		return nil, &standardisedError.StandardisedError{
+			Type:   "validation-error",
+			Title:  "could not generate an electronic invoice",
+			Status: http.StatusBadRequest,
+			Detail: "every row needs a net value for electronic invoices",
+		}
+	}
+
+	line := &einvoice.Line{
+		Name:      *row.Name,
+		Quantity:  1,
+		UnitCode:  "C62",
+		NetAmount: *row.Net,
+	}
+
+	if row.Description != nil {
+		line.Description = document.PlainRichText(*row.Description)
+	}
+
+	if row.Amount != nil && *row.Amount != 0 {
+		line.Quantity = *row.Amount
+	}
+
+	if row.AmountUnit != nil {
+		line.UnitCode = einvoice.UnitCode(*row.AmountUnit)
+	}
+
+	//the price must not be negative (BR-27), credits and discounts carry their
+	//sign on the quantity
+	line.NetPrice = math.Abs(line.NetAmount / line.Quantity)
+	if (line.NetAmount < 0) != (line.Quantity < 0) && line.NetAmount != 0 {
+		line.Quantity = -line.Quantity
+	}
+
+	line.TaxRate = rowTaxRate(row)
+
+	return line, nil
+}
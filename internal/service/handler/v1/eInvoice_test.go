@@ -0,0 +1,27 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/hodl-repos/pdf-invoice/internal/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateEInvoiceLineNegative(t *testing.T) {
+	name, amount, net := "Discount", 2., -30.
+	row := &dto.InvoiceRowDto{Name: &name, Amount: &amount, Net: &net}
+
+	// the price is positive, the quantity carries the sign of the credit
+	line, err := generateEInvoiceLineFromDto(row)
+	assert.NoError(t, err)
+	assert.Equal(t, 15., line.NetPrice)
+	assert.Equal(t, -2., line.Quantity)
+	assert.Equal(t, -30., line.NetAmount)
+
+	// a negative quantity of a positive net is turned around
+	amount, net = -2, 30
+	line, err = generateEInvoiceLineFromDto(row)
+	assert.NoError(t, err)
+	assert.Equal(t, 15., line.NetPrice)
+	assert.Equal(t, 2., line.Quantity)
+}
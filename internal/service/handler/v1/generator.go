@@ -2,15 +2,83 @@ package v1
 
 import (
 	"github.com/hodl-repos/pdf-invoice/internal/dto"
+	"github.com/hodl-repos/pdf-invoice/pkg/assetstore"
 	"github.com/hodl-repos/pdf-invoice/pkg/delimitor"
 	"github.com/hodl-repos/pdf-invoice/pkg/document"
+	"github.com/hodl-repos/pdf-invoice/pkg/fetcher"
+	"github.com/hodl-repos/pdf-invoice/pkg/fontstore"
 	"github.com/hodl-repos/pdf-invoice/pkg/localize"
+	"github.com/hodl-repos/pdf-invoice/pkg/templatestore"
 	"github.com/jung-kurt/gofpdf"
 )
 
-func Generate(data *dto.DocumentDto, localizeClient *localize.LocalizeClient) (*document.Doc, error) {
+// Generate prints the document with the blocks of the template selected by
+// the style, the built-in template when none is selected
+func Generate(data *dto.DocumentDto, localizeClient *localize.LocalizeClient, fontStore *fontstore.FontStore, templateStore *templatestore.TemplateStore, assetStore *assetstore.AssetStore, imageFetcher *fetcher.Fetcher) (*document.Doc, error) {
+	style, err := resolveDocumentStyle(data.Style, fontStore)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := resolveTemplate(data.Style, templateStore)
+	if err != nil {
+		return nil, err
+	}
+
+	//the fonts of the blocks have to be known when creating the pdf
+	fonts, err := resolveTemplateFonts(template, style, fontStore)
+	if err != nil {
+		return nil, err
+	}
+
+	pdf := newDocument(data.Style, data.SellerInformation, style, assetStore, imageFetcher, localizeClient)
+
+	kind := newDocumentKind(data.InvoiceInformation, localizeClient)
+	pdf.SetTitle(kind.title(), true)
+
+	cur, err := documentCurrency(data.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	//calculate rows server-side if requested
+	if err := calculateInvoiceRows(data.InvoiceData, cur); err != nil {
+		return nil, err
+	}
+
+	for i := range template.Blocks {
+		blockStyle := *style
+		blockStyle.body = fonts[i]
+
+		if err := generateTemplateBlock(&template.Blocks[i], data, cur, kind, pdf, &blockStyle, localizeClient); err != nil {
+			return nil, err
+		}
+	}
+
+	//embed electronic invoice
+	if data.Style.FacturXProfile != nil {
+		if err := attachFacturX(data, pdf); err != nil {
+			return nil, err
+		}
+	}
+
+	return pdf, nil
+}
+
+// creates the pdf with the defaults (DIN), the fonts and colors of the style
+// and the footer containing the page numbers and the seller information
+func newDocument(data *dto.DocumentStyleDto, seller *dto.SellerInformationDto, style *documentStyle, assetStore *assetstore.AssetStore, imageFetcher *fetcher.Fetcher, localizeClient *localize.LocalizeClient) *document.Doc {
+	var conformance document.Conformance
+	if data.Conformance != nil {
+		conformance = *data.Conformance
+	}
+
+	//factur-x requires PDF/A-3
+	if data.FacturXProfile != nil {
+		conformance = document.ConformancePdfA3b
+	}
+
 	defaultsFunction := func(pdf *gofpdf.Fpdf) {
-		pdf.SetFont("Arial", "", 10)
 		pdf.SetLineWidth(0.2)
 		pdf.SetCellMargin(0)
 
@@ -19,90 +87,111 @@ func Generate(data *dto.DocumentDto, localizeClient *localize.LocalizeClient) (*
 	}
 
 	//create pdf with custom defaults (DIN)
-	pdf := document.NewA4WithDefaults(&defaultsFunction)
+	var direction document.Direction
+	if data.Direction != nil {
+		direction = *data.Direction
+	}
+
+	options := []document.Option{
+		document.WithConformance(conformance),
+		document.WithDirection(direction),
+		document.WithFonts(style.fonts...),
+		document.WithFontFamily(style.body.Family),
+		document.WithAssets(assetStore),
+	}
+
+	//the document uses the default fetcher otherwise
+	if imageFetcher != nil {
+		options = append(options, document.WithImageFetcher(imageFetcher))
+	}
+
+	//the positions of the layout are given for its page size
+	size := document.PageSizeA4
+	if layout, ok := document.GetLayout(*data.Layout); ok {
+		size = layout.PageSize()
+	}
+
+	pdf := document.NewWithDefaults(size, &defaultsFunction, options...)
+
+	pdf.SetFontSpec(style.body)
+	pdf.SetLineHeight(style.lineHeight)
+	pdf.SetTextColorSpec(style.text)
+	pdf.SetDrawColorSpec(style.border)
 
 	pdf.AliasNbPages("{nb}")
 
 	//perpare footer
-	footerData := prepareFooterString(data)
+	footerData := prepareFooterString(data, seller)
+
+	//only the footer given by the request contains markup
+	footerRich := data.FooterOverride != nil
+
+	//the footer is measured in the font it is printed with
+	pdf.SetFontSpec(style.footer)
 
-	footerLines := pdf.SplitText(footerData, pdf.GetPrintWidth())
-	totalFooterTextHeight := float64(len(footerLines)) * pdf.GetFontLineHeight()
+	footerLines := len(pdf.SplitText(footerData, pdf.GetPrintWidth()))
+	if footerRich {
+		footerLines = pdf.CountRichTextLines(footerData, pdf.GetPrintWidth())
+	}
+	totalFooterTextHeight := float64(footerLines) * pdf.GetFontLineHeight()
 	totalFooterBlockHeight := totalFooterTextHeight + 10 + 4.23 + 4.23 + pdf.GetFontLineHeight()
 	pdf.SetAutoPageBreak(true, totalFooterBlockHeight)
 
+	pdf.SetFontSpec(style.body)
+
 	pdf.SetFooterFunc(func() {
-		if data.Style.ShowMarkerFolding != nil && *data.Style.ShowMarkerFolding {
-			if *data.Style.Layout == document.LayoutTypeDIN5008A {
-				pdf.Line(0, 87, 10, 87)
-				pdf.Line(0, 192, 10, 192)
-			}
+		//e.g. the page of the swiss QR-bill
+		if !pdf.HasFooter() {
+			return
+		}
 
-			if *data.Style.Layout == document.LayoutTypeDIN5008B {
-				pdf.Line(0, 105, 10, 105)
-				pdf.Line(0, 210, 10, 210)
+		if data.ShowMarkerFolding != nil && *data.ShowMarkerFolding {
+			if layout, ok := document.GetLayout(*data.Layout); ok {
+				for _, y := range layout.FoldMarks() {
+					pdf.Line(0, y, 10, y)
+				}
 			}
 		}
 
-		if data.Style.ShowMarkerPuncher != nil && *data.Style.ShowMarkerPuncher {
+		if data.ShowMarkerPuncher != nil && *data.ShowMarkerPuncher {
 			_, h := pdf.GetPageSize()
 			pdf.Line(0, h/2, 14, h/2)
 		}
 
-		pdf.SetFont("Arial", "", 8)
+		//gofpdf restores the font after the footer, GetFont has to match
+		font := pdf.GetFont()
+		defer pdf.SetFontSpec(font)
+
+		pdf.SetFontSpec(style.footer)
 
 		//always display page numbers
 		pdf.SetY(-(totalFooterTextHeight + 10 + 4.23 + pdf.GetFontLineHeight()))
 		pageNoString := localizeClient.TranslatePageNumberWithTotalCount(pdf.PageNo(), "{nb}")
+		pdf.SetTextColorSpec(style.accent)
 		pdf.MCell(pdf.GetPrintWidth(), pdf.GetFontLineHeight(), pageNoString, "", "R", false)
+		pdf.SetTextColorSpec(style.text)
 
 		//always display footer
 		pdf.SetY(-(totalFooterTextHeight + 10))
-		pdf.MCell(pdf.GetPrintWidth(), pdf.GetFontLineHeight(), footerData, "", "M", false)
+		if footerRich {
+			pdf.RichText(pdf.GetPrintWidth(), pdf.GetFontLineHeight(), footerData, "")
+		} else {
+			pdf.MCell(pdf.GetPrintWidth(), pdf.GetFontLineHeight(), footerData, "", "M", false)
+		}
 	})
 
-	//generate invoice header block
-	if err := generateHeaderBlock(data, pdf, localizeClient); err != nil {
-		return nil, err
-	}
-
-	//append customer-address if provided
-	if data.CustomerAddress != nil {
-		pdf.SetFont("Arial", "B", 12)
-		pdf.MCell(0, pdf.GetFontLineHeight(), localizeClient.TranslateContractingParty(), "", "", false)
-
-		pdf.SetFont("Arial", "", 10)
-		pdf.MCell(0, pdf.GetFontLineHeight(), data.CustomerAddress.Format(delimitor.NewLine), "", "", false)
+	return pdf
+}
 
-		pdf.Ln(pdf.GetFontLineHeight())
-	}
+// the contracting party below the header block
+func generateCustomerAddressBlock(address *dto.AddressDto, pdf *document.Doc, style *documentStyle, localizeClient *localize.LocalizeClient) {
+	pdf.SetFontSpec(style.heading)
+	pdf.SetTextColorSpec(style.primary)
+	pdf.MCell(0, pdf.GetFontLineHeight(), localizeClient.TranslateContractingParty(), "", "", false)
 
-	//generate invoice-block
-	err := generateInvoiceBlock(data.InvoiceData, pdf, localizeClient)
-	if err != nil {
-		return nil, err
-	}
-	err = generateInvoiceSumBlock(data.InvoiceData, pdf, localizeClient)
-	if err != nil {
-		return nil, err
-	}
+	pdf.SetFontSpec(style.body)
+	pdf.SetTextColorSpec(style.text)
+	pdf.MCell(0, pdf.GetFontLineHeight(), address.Format(delimitor.NewLine), "", "", false)
 
 	pdf.Ln(pdf.GetFontLineHeight())
-
-	//append data suffix if provided
-	if data.InvoiceDataSuffix != nil {
-		pdf.SetFont("Arial", "", 10)
-		pdf.MCell(0, pdf.GetFontLineHeight(), *data.InvoiceDataSuffix, "", "", false)
-
-		pdf.Ln(pdf.GetFontLineHeight())
-	}
-
-	//generate bank-payment-block
-	if data.BankPaymentData != nil && data.Style.ShowBankPaymentQrCode != nil && *data.Style.ShowBankPaymentQrCode {
-		if err := generateBankBlock(data, pdf, localizeClient); err != nil {
-			return nil, err
-		}
-	}
-
-	return pdf, nil
 }
@@ -6,11 +6,22 @@ import (
 
 	"github.com/hodl-repos/pdf-invoice/internal/dto"
 	"github.com/hodl-repos/pdf-invoice/pkg/apihelper"
+	"github.com/hodl-repos/pdf-invoice/pkg/assetstore"
+	"github.com/hodl-repos/pdf-invoice/pkg/einvoice"
+	"github.com/hodl-repos/pdf-invoice/pkg/fetcher"
+	"github.com/hodl-repos/pdf-invoice/pkg/fontstore"
+	"github.com/hodl-repos/pdf-invoice/pkg/jsonutil"
 	"github.com/hodl-repos/pdf-invoice/pkg/localize"
 	"github.com/hodl-repos/pdf-invoice/pkg/logging"
+	"github.com/hodl-repos/pdf-invoice/pkg/profilestore"
+	"github.com/hodl-repos/pdf-invoice/pkg/templatestore"
+	"github.com/hodl-repos/pdf-invoice/pkg/validation"
 )
 
-func Handler(localizationProvider *localize.LocalizeService) apihelper.HandlerFuncWithError {
+// max request size of /generate, which leaves room for inline base64 images
+const maxGenerateBodyBytes = 4_000_000
+
+func Handler(localizationProvider *localize.LocalizeService, fontStore *fontstore.FontStore, templateStore *templatestore.TemplateStore, profileStore *profilestore.ProfileStore, assetStore *assetstore.AssetStore, imageFetcher *fetcher.Fetcher) apihelper.HandlerFuncWithError {
 	return func(w http.ResponseWriter, r *http.Request) error {
 		ctx := r.Context()
 		logger := logging.FromContext(ctx)
@@ -22,7 +33,19 @@ func Handler(localizationProvider *localize.LocalizeService) apihelper.HandlerFu
 
 		var request dto.DocumentDto
 
-		err := apihelper.UnmarshalJsonAndValidateWithError(w, r, &request)
+		err := jsonutil.UnmarshalWithErrorLimit(w, r, &request, maxGenerateBodyBytes)
+		if err != nil {
+			return err
+		}
+
+		//the fields of the profile are validated together with the request
+		if request.ProfileID != nil {
+			if err := applyProfile(&request, profileStore); err != nil {
+				return err
+			}
+		}
+
+		err = validation.ValidateStruct(&request)
 		if err != nil {
 			return err
 		}
@@ -35,7 +58,7 @@ func Handler(localizationProvider *localize.LocalizeService) apihelper.HandlerFu
 
 		logger.Debugln("generating pdf")
 
-		pdf, err := Generate(&request, localizationClient)
+		pdf, err := Generate(&request, localizationClient, fontStore, templateStore, assetStore, imageFetcher)
 		if err != nil {
 			return err
 		}
@@ -56,3 +79,118 @@ func Handler(localizationProvider *localize.LocalizeService) apihelper.HandlerFu
 		return nil
 	}
 }
+
+func DunningHandler(localizationProvider *localize.LocalizeService, fontStore *fontstore.FontStore, profileStore *profilestore.ProfileStore, assetStore *assetstore.AssetStore, imageFetcher *fetcher.Fetcher) apihelper.HandlerFuncWithError {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		logger := logging.FromContext(ctx)
+
+		logger.Debugln("got request for v1-dunning")
+
+		//#region unmarshal
+		logger.Debugln("deserializing dto")
+
+		var request dto.DunningDto
+
+		err := jsonutil.UnmarshalWithError(w, r, &request)
+		if err != nil {
+			return err
+		}
+
+		//the fields of the profile are validated together with the request
+		if request.ProfileID != nil {
+			if err := applyDunningProfile(&request, profileStore); err != nil {
+				return err
+			}
+		}
+
+		err = validation.ValidateStruct(&request)
+		if err != nil {
+			return err
+		}
+
+		//#endregion unmarshal
+
+		localizationClient := localizationProvider.CreateClient(*request.Style.LocaleCode, *request.Style.LanguageCode)
+
+		logger.Debugln("generating pdf")
+
+		pdf, err := GenerateDunning(&request, localizationClient, fontStore, assetStore, imageFetcher)
+		if err != nil {
+			return err
+		}
+
+		logger.Debugln("serializing pdf")
+
+		var buf bytes.Buffer
+		if err := pdf.Output(&buf); err != nil {
+			return err
+		}
+
+		logger.Debugln("sending response")
+
+		w.Header().Set("content-type", "application/pdf")
+		w.WriteHeader(http.StatusOK)
+		w.Write(buf.Bytes())
+
+		return nil
+	}
+}
+
+func XRechnungHandler(profileStore *profilestore.ProfileStore) apihelper.HandlerFuncWithError {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		logger := logging.FromContext(ctx)
+
+		logger.Debugln("got request for v1-xrechnung")
+
+		//ubl is the default syntax
+		syntax, err := apihelper.GetUrlQueryValue[einvoice.Syntax](r.URL.Query(), "syntax", einvoice.SyntaxParser)
+		if err != nil {
+			return err
+		}
+		if syntax == nil {
+			ubl := einvoice.SyntaxUBL
+			syntax = &ubl
+		}
+
+		//#region unmarshal
+		logger.Debugln("deserializing dto")
+
+		var request dto.DocumentDto
+
+		err = jsonutil.UnmarshalWithError(w, r, &request)
+		if err != nil {
+			return err
+		}
+
+		//the fields of the profile are validated together with the request
+		if request.ProfileID != nil {
+			if err := applyProfile(&request, profileStore); err != nil {
+				return err
+			}
+		}
+
+		err = validation.ValidateStruct(&request)
+		if err != nil {
+			return err
+		}
+
+		//#endregion unmarshal
+
+		logger.Debugln("generating xrechnung")
+
+		xmlData, err := GenerateXRechnung(&request, *syntax)
+		if err != nil {
+			return err
+		}
+
+		logger.Debugln("sending response")
+
+		w.Header().Set("content-type", "application/xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write(xmlData)
+
+		return nil
+	}
+}
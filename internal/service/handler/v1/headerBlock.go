@@ -2,143 +2,73 @@ package v1
 
 import (
 	"net/http"
+	"strings"
 
-	go2 "github.com/adam-hanna/arrayOperations"
 	"github.com/hodl-repos/pdf-invoice/internal/dto"
 	"github.com/hodl-repos/pdf-invoice/pkg/delimitor"
 	"github.com/hodl-repos/pdf-invoice/pkg/document"
-	"github.com/hodl-repos/pdf-invoice/pkg/localize"
 	"github.com/hodl-repos/pdf-invoice/pkg/standardisedError"
-	"github.com/jung-kurt/gofpdf"
-)
-
-type headerBlockGeneratorsType struct {
-	Name     document.LayoutType
-	Function func(*dto.DocumentDto, *document.Doc, *localize.LocalizeClient)
-}
-
-var (
-	headerBlockGenerators = []headerBlockGeneratorsType{
-		{
-			Name:     document.LayoutTypeDIN5008A,
-			Function: createDIN5008ABlock,
-		},
-		{
-			Name:     document.LayoutTypeDIN5008B,
-			Function: createDIN5008BBlock,
-		},
-	}
 )
 
 // as this function is called at first - no checks for site-breaks are made
-func generateHeaderBlock(data *dto.DocumentDto, pdf *document.Doc, localizeClient *localize.LocalizeClient) error {
-	formatFc, ok := go2.FindOne(headerBlockGenerators, func(abgt headerBlockGeneratorsType) bool { return abgt.Name == *data.Style.Layout })
+func generateHeaderBlock(data *dto.DocumentStyleDto, address *dto.InvoiceAddressDto, information [][]string, pdf *document.Doc, style *documentStyle) error {
+	layout, ok := document.GetLayout(*data.Layout)
 
 	if !ok {
+		names := make([]string, 0)
+		for _, name := range document.LayoutTypes() {
+			names = append(names, string(name))
+		}
+
 		return &standardisedError.StandardisedError{
 			Type:   "validation-error",
 			Title:  "could not find a correct generator for the address-block",
 			Status: http.StatusBadRequest,
-			Detail: "only din5008a and din5008b are currently supported",
+			Detail: "supported layouts are " + strings.Join(names, ", "),
 		}
 	}
 
-	formatFc.Function(data, pdf, localizeClient)
-
-	return nil
+	return createHeaderBlock(layout, data, address, information, pdf, style)
 }
 
-func createDIN5008ABlock(data *dto.DocumentDto, pdf *document.Doc, localizeClient *localize.LocalizeClient) {
-	pdf.SetXY(25, 27+17.57)
+func createHeaderBlock(layout document.Layout, data *dto.DocumentStyleDto, address *dto.InvoiceAddressDto, information [][]string, pdf *document.Doc, style *documentStyle) error {
+	window := layout.AddressWindow()
+	pdf.SetXY(window.X, window.Y)
 
-	//TODO: limit to 27.3 max-height
-	pdf.MCell(80, pdf.GetFontLineHeight() /* 27.3 */, data.InvoiceAddress.Format(delimitor.NewLine), "", "LT", false)
+	//TODO: limit to window.Height
+	pdf.MCell(window.Width, pdf.GetFontLineHeight(), address.Format(delimitor.NewLine), "", "LT", false)
 
 	lOld, _, rOld, _ := pdf.GetMargins()
+	pageWidth, _ := pdf.GetPageSize()
 
-	pdf.SetLeftMargin(125)
-	pdf.SetRightMargin(10)
-	pdf.SetXY(125, 32)
-	table, _ := document.NewDocTable(pdf, prepareInformationCells(data.InvoiceInformation, localizeClient))
+	info := layout.InfoBlock()
+	pdf.SetLeftMargin(info.X)
+	pdf.SetRightMargin(pageWidth - info.X - info.Width)
+	pdf.SetXY(info.X, info.Y)
+	table, _ := document.NewDocTable(pdf, information)
 	table.SetAllCellPaddings(document.Padding{0, 0, 0, 1})
 	table.SetAllCellBorders(false)
+	table.SetCellTextColorsPerColumn([]*document.Color{&style.primary, nil})
 
 	table.Generate()
 
 	pdf.SetLeftMargin(lOld)
 	pdf.SetRightMargin(rOld)
 
-	if data.Style.Image != nil {
-		drawImage(pdf, data.Style.Image, 125, 10, 200, 27)
+	if data.Image != nil {
+		logo := layout.LogoArea()
+		if err := pdf.DrawImage(data.Image, logo.X, logo.Y, logo.Width, logo.Height); err != nil {
+			return &standardisedError.StandardisedError{
+				Type:   "validation-error",
+				Title:  "could not load the image",
+				Status: http.StatusBadRequest,
+				Detail: err.Error(),
+			}
+		}
 	}
 
 	//set to content position
-	pdf.SetXY(25, 98.5)
-}
-
-func createDIN5008BBlock(data *dto.DocumentDto, pdf *document.Doc, localizeClient *localize.LocalizeClient) {
-	pdf.SetXY(25, 45+17.7)
-
-	//TODO: limit to 27.3 max-height
-	pdf.MCell(80, pdf.GetFontLineHeight() /* 27.3 */, data.InvoiceAddress.Format(delimitor.NewLine), "", "LT", false)
-
-	lOld, _, rOld, _ := pdf.GetMargins()
-
-	pdf.SetLeftMargin(125)
-	pdf.SetRightMargin(10)
-	pdf.SetXY(125, 50)
-	table, _ := document.NewDocTable(pdf, prepareInformationCells(data.InvoiceInformation, localizeClient))
-	table.SetAllCellPaddings(document.Padding{0, 0, 0, 1})
-	table.SetAllCellBorders(false)
-
-	table.Generate()
-
-	pdf.SetLeftMargin(lOld)
-	pdf.SetRightMargin(rOld)
-
-	if data.Style.Image != nil {
-		drawImage(pdf, data.Style.Image, 125, 10, 200, 45)
-	}
-	//set to content position
-	pdf.SetXY(25, 98.5)
-}
-
-func drawImage(pdf *document.Doc, dto *document.Image, startX, startY, endX, endY float64) error {
-	addedImage, err := pdf.AddImage(dto)
-
-	if err != nil {
-		return err
-	}
-
-	shouldWidth := endX - startX
-	shouldHeight := endY - startY
-
-	drawWidth := shouldWidth
-	drawHeight := shouldHeight
-
-	ratioArea := drawWidth / drawHeight
-	ratioImage := addedImage.Width() / addedImage.Height()
-
-	if ratioArea >= ratioImage {
-		//image gets margin left/right
-		drawWidth = drawHeight * ratioImage
-	} else {
-		//image get margin top/bottom
-		drawHeight = drawWidth / ratioImage
-	}
-
-	drawWidthOffset := (shouldWidth - drawWidth) / 2.0
-	drawHeightOffset := (shouldHeight - drawHeight) / 2.0
-
-	pdf.Fpdf.ImageOptions(*dto.ImageUrl,
-		startX+drawWidthOffset,
-		startY+drawHeightOffset,
-		drawWidth,
-		drawHeight,
-		false,
-		gofpdf.ImageOptions{ReadDpi: true},
-		0,
-		"")
+	pdf.SetXY(lOld, layout.ContentStartY())
 
 	return nil
 }
@@ -5,15 +5,16 @@ import (
 
 	"github.com/hodl-repos/pdf-invoice/internal/dto"
 	"github.com/hodl-repos/pdf-invoice/pkg/delimitor"
+	"github.com/hodl-repos/pdf-invoice/pkg/document"
 	"github.com/hodl-repos/pdf-invoice/pkg/localize"
 )
 
-func prepareFooterString(data *dto.DocumentDto) string {
-	if data.Style.FooterOverride != nil {
-		return *data.Style.FooterOverride
+func prepareFooterString(style *dto.DocumentStyleDto, seller *dto.SellerInformationDto) string {
+	if style.FooterOverride != nil {
+		return *style.FooterOverride
 	}
 
-	return data.SellerInformation.Format(delimitor.Tab)
+	return seller.Format(delimitor.Tab)
 }
 
 func ap(table *[][]string, header, content string) {
@@ -24,19 +25,54 @@ func ap(table *[][]string, header, content string) {
 }
 
 // prepared invoice-rows with 2 colums
-func prepareInformationCells(data *dto.InvoiceInformationDto, localizeClient *localize.LocalizeClient) [][]string {
+func prepareInformationCells(data *dto.InvoiceInformationDto, kind *documentKind, localizeClient *localize.LocalizeClient) [][]string {
 	//name is required
 	tmp := make([][]string, 0)
 
-	if data.InvoiceNumber != nil {
-		ap(&tmp, localizeClient.TranslateInvoiceNumber(), *data.InvoiceNumber)
-		ap(&tmp, localizeClient.TranslateDate(), data.InvoiceDate.Format("2006-01-02"))
-	} else if data.OfferNumber != nil {
-		ap(&tmp, localizeClient.TranslateOfferNumber(), *data.OfferNumber)
-		ap(&tmp, localizeClient.TranslateDate(), data.OfferDate.Format("2006-01-02"))
+	if len(kind.number) > 0 {
+		ap(&tmp, kind.numberLabel, kind.number)
+		ap(&tmp, localizeClient.TranslateDate(), kind.date.Format("2006-01-02"))
 	}
 
-	ap(&tmp, localizeClient.TranslateDueDate(), data.DueDate.Format("2006-01-02"))
+	if data.IsCreditNote() {
+		ap(&tmp, localizeClient.TranslateCorrectedInvoice(), *data.CorrectedInvoiceNumber)
+		ap(&tmp, localizeClient.TranslateCorrectedInvoiceDate(), data.CorrectedInvoiceDate.Format("2006-01-02"))
+	}
+
+	if data.DueDate != nil {
+		ap(&tmp, localizeClient.TranslateDueDate(), data.DueDate.Format("2006-01-02"))
+	}
+
+	if data.LeitwegID != nil {
+		ap(&tmp, localizeClient.TranslateLeitwegID(), *data.LeitwegID)
+	} else if data.BuyerReference != nil {
+		ap(&tmp, localizeClient.TranslateBuyerReference(), *data.BuyerReference)
+	}
+
+	if data.AdditionalInformation != nil {
+		for _, additional := range *data.AdditionalInformation {
+			ap(&tmp, *additional.Title, *additional.Value)
+		}
+	}
+
+	return tmp
+}
+
+// prepared dunning-rows with 2 columns
+func prepareDunningInformationCells(data *dto.DunningInformationDto, localizeClient *localize.LocalizeClient) [][]string {
+	tmp := make([][]string, 0)
+
+	if data.DunningNumber != nil {
+		ap(&tmp, localizeClient.TranslateDunningNumber(), *data.DunningNumber)
+	}
+
+	ap(&tmp, localizeClient.TranslateDate(), data.DunningDate.Format("2006-01-02"))
+
+	if data.CustomerIdentifier != nil {
+		ap(&tmp, localizeClient.TranslateCustomerIdentifier(), *data.CustomerIdentifier)
+	}
+
+	ap(&tmp, localizeClient.TranslatePaymentDueBy(), data.DueDate.Format("2006-01-02"))
 
 	if data.AdditionalInformation != nil {
 		for _, additional := range *data.AdditionalInformation {
@@ -47,6 +83,28 @@ func prepareInformationCells(data *dto.InvoiceInformationDto, localizeClient *lo
 	return tmp
 }
 
+// replaces the placeholders {{.Name}} of a text given by the request by the
+// values, unknown placeholders are kept
+func fillPlaceholders(text string, values map[string]string) string {
+	pairs := make([]string, 0, 2*len(values))
+	for name, value := range values {
+		pairs = append(pairs, "{{."+name+"}}", value)
+	}
+
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+// fillRichPlaceholders is fillPlaceholders for a text printed by RichText, the
+// markup characters of the values are escaped
+func fillRichPlaceholders(text string, values map[string]string) string {
+	escaped := make(map[string]string, len(values))
+	for name, value := range values {
+		escaped[name] = document.EscapeRichText(value)
+	}
+
+	return fillPlaceholders(text, escaped)
+}
+
 func prepareBankText(data *dto.BankPaymentDto, localizeClient *localize.LocalizeClient) string {
 	// name is required
 	var sb strings.Builder
@@ -0,0 +1,18 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/hodl-repos/pdf-invoice/pkg/document"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFillRichPlaceholders(t *testing.T) {
+	text := fillRichPlaceholders("Dear **{{.CustomerName}}**", map[string]string{
+		"CustomerName": "*ACME* [x](y) Smith_Co_",
+	})
+
+	// the markup of the template is kept, the value is printed as it is
+	assert.Equal(t, "Dear *ACME* [x](y) Smith_Co_", document.PlainRichText(text))
+	assert.Equal(t, `Dear **\*ACME\* \[x\](y) Smith\_Co\_**`, text)
+}
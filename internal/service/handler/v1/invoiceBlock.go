@@ -7,126 +7,232 @@ import (
 	"github.com/hodl-repos/pdf-invoice/internal/dto"
 	"github.com/hodl-repos/pdf-invoice/pkg/document"
 	"github.com/hodl-repos/pdf-invoice/pkg/localize"
-	"github.com/jung-kurt/gofpdf"
+	"github.com/hodl-repos/pdf-invoice/pkg/templatestore"
+	"golang.org/x/text/currency"
 )
 
-func generateInvoiceBlock(data *dto.InvoiceDto, pdf *document.Doc, localizeClient *localize.LocalizeClient) error {
-	rawData := prepareInvoiceData(data, localizeClient)
+func generateInvoiceBlock(data *dto.InvoiceDto, templateColumns []templatestore.Column, cur currency.Unit, kind *documentKind, pdf *document.Doc, style *documentStyle, localizeClient *localize.LocalizeClient) error {
+	columns := prepareInvoiceColumns(data, templateColumns, kind, localizeClient)
+	rawData := prepareInvoiceData(data, columns, cur, kind, localizeClient)
 
 	table, _ := document.NewDocTable(pdf, rawData)
 	table.SetAllCellBorders(false)
 	table.SetHeadType(document.HeadFirstRow)
+	table.SetHeadFont(style.tableHead)
 	table.SetAllCellPaddings(document.Padding{1, 1, 1, 1})
-	table.SetAllCellTypes(document.CellMulti)
 
 	//set every col type to calc, but not the first one
 	nrCols := len(rawData[0])
 	colTypes := make([]document.ColumnType, 0)
 	alTypes := make([]document.AlignmentType, 0)
+	cellTypes := make([]document.CellType, 0)
+	widths := make([]float64, 0)
 	colTypes = append(colTypes, document.ColDyn)
 	alTypes = append(alTypes, document.AlignLeft)
+	cellTypes = append(cellTypes, document.CellRich)
+	widths = append(widths, 0)
 	for i := 1; i < nrCols; i++ {
 		colTypes = append(colTypes, document.ColFixed)
 		alTypes = append(alTypes, document.AlignRight)
+		cellTypes = append(cellTypes, document.CellMulti)
+		widths = append(widths, columns[i-1].width)
 	}
 	table.SetColTypes(colTypes)
 	table.SetCellAlingsPerColumn(alTypes)
-	table.SetAllColFixedWidths(25.0)
+	table.SetCellTypesPerColumn(cellTypes)
+	table.SetColFixedWidths(widths)
 
-	bg := func(fpdf gofpdf.Fpdf) {
-		fpdf.SetFillColor(220, 220, 220)
-	}
-	table.SetCellStyleFuncsPerAlternateRows(&bg, nil)
+	table.SetCellStyleFuncsPerAlternateRows(fillColorFunc(style.zebra), nil)
+	table.SetCellStyleFuncsRow(0, fillColorFunc(style.tableHeadBackground))
+	table.SetCellTextColorsRow(0, &style.accent)
 
 	table.Generate()
 
 	return nil
 }
 
-func prepareInvoiceData(data *dto.InvoiceDto, localizeClient *localize.LocalizeClient) [][]string {
+func prepareInvoiceData(data *dto.InvoiceDto, columns []invoiceColumn, cur currency.Unit, kind *documentKind, localizeClient *localize.LocalizeClient) [][]string {
 	tmp := make([][]string, 0)
 
-	showDiscountColumn := go2.Reduce(*data.Rows, func(b bool, ird dto.InvoiceRowDto) bool {
-		return ird.DiscountFixed != nil || ird.DiscountPercentage != nil
-	}, false)
-
-	headerRow := prepareInvoiceLine(
-		data,
-		showDiscountColumn,
-		localizeClient.TranslateName(),
-		localizeClient.TranslateAmount(),
-		localizeClient.TranslateNet(),
-		localizeClient.TranslateTax(),
-		localizeClient.TranslateDiscount(),
-		localizeClient.TranslateGross())
+	headerRow := []string{localizeClient.TranslateName()}
+	for _, column := range columns {
+		headerRow = append(headerRow, column.title)
+	}
 
 	tmp = append(tmp, headerRow)
 
 	for _, row := range *data.Rows {
-		amountString := formatAmount(row.Amount, row.AmountUnit, localizeClient)
-		netString := formatMoney(row.Net, localizeClient)
-		taxString := formatMoney(row.Tax, localizeClient)
-		grossString := formatMoney(row.Gross, localizeClient)
-		discountString := formatDiscount(row.TaxPercentage, row.Tax, localizeClient)
-
-		titleString := *row.Name
+		//the description contains markup, the name is printed as it is
+		titleString := document.EscapeRichText(*row.Name)
 		if row.Description != nil {
 			titleString = fmt.Sprintf("%s\n%s", titleString, *row.Description)
 		}
 
-		line := prepareInvoiceLine(data, showDiscountColumn, titleString, amountString, netString, taxString, discountString, grossString)
+		line := prepareInvoiceLine(columns, titleString, map[templatestore.ColumnField]string{
+			templatestore.ColumnAmount:        formatAmount(row.Amount, row.AmountUnit, localizeClient),
+			templatestore.ColumnSerialNumber:  formatOptional(row.SerialNumber),
+			templatestore.ColumnBatchNumber:   formatOptional(row.BatchNumber),
+			templatestore.ColumnNet:           formatMoney(kind.signed(row.Net), cur, localizeClient),
+			templatestore.ColumnTaxPercentage: formatTaxRate(&row, localizeClient),
+			templatestore.ColumnTax:           formatMoney(kind.signed(row.Tax), cur, localizeClient),
+			templatestore.ColumnDiscount:      formatDiscount(row.DiscountPercentage, row.DiscountFixed, cur, localizeClient),
+			templatestore.ColumnGross:         formatMoney(kind.signed(row.Gross), cur, localizeClient),
+		})
 		tmp = append(tmp, line)
 	}
 
 	return tmp
 }
 
+// visible column of the invoice-block beside the name
+type invoiceColumn struct {
+	field templatestore.ColumnField
+	title string
+	width float64
+}
+
+// the columns are given by the template or depend on the style of the invoice
+// data and the document type, documents without prices (delivery notes) always
+// show the amount and the serial and batch numbers if at least one row has them
+func prepareInvoiceColumns(data *dto.InvoiceDto, templateColumns []templatestore.Column, kind *documentKind, localizeClient *localize.LocalizeClient) []invoiceColumn {
+	titles := map[templatestore.ColumnField]string{
+		templatestore.ColumnAmount:        localizeClient.TranslateAmount(),
+		templatestore.ColumnSerialNumber:  localizeClient.TranslateSerialNumber(),
+		templatestore.ColumnBatchNumber:   localizeClient.TranslateBatchNumber(),
+		templatestore.ColumnNet:           localizeClient.TranslateNet(),
+		templatestore.ColumnTaxPercentage: localizeClient.TranslateTaxRate(),
+		templatestore.ColumnTax:           localizeClient.TranslateTax(),
+		templatestore.ColumnDiscount:      localizeClient.TranslateDiscount(),
+		templatestore.ColumnGross:         localizeClient.TranslateGross(),
+	}
+
+	columns := make([]invoiceColumn, 0)
+
+	if len(templateColumns) > 0 {
+		for _, column := range templateColumns {
+			if column.Field.IsPrice() && !kind.priced {
+				continue
+			}
+
+			title := titles[column.Field]
+			if len(column.Title) > 0 {
+				title = column.Title
+			}
+
+			width := 25.0
+			if column.Width > 0 {
+				width = column.Width
+			}
+
+			columns = append(columns, invoiceColumn{field: column.Field, title: title, width: width})
+		}
+
+		return columns
+	}
+
+	isSet := func(value *bool) bool {
+		return value != nil && *value
+	}
+
+	visible := map[templatestore.ColumnField]bool{
+		templatestore.ColumnAmount: isSet(data.ShowAmountColumn),
+	}
+
+	if kind.delivery {
+		visible[templatestore.ColumnAmount] = true
+		visible[templatestore.ColumnSerialNumber] = go2.Reduce(*data.Rows, func(b bool, ird dto.InvoiceRowDto) bool {
+			return b || ird.SerialNumber != nil
+		}, false)
+		visible[templatestore.ColumnBatchNumber] = go2.Reduce(*data.Rows, func(b bool, ird dto.InvoiceRowDto) bool {
+			return b || ird.BatchNumber != nil
+		}, false)
+	}
+
+	if kind.priced {
+		visible[templatestore.ColumnNet] = isSet(data.ShowNetColumn)
+		visible[templatestore.ColumnTax] = isSet(data.ShowTaxColumn)
+		visible[templatestore.ColumnDiscount] = go2.Reduce(*data.Rows, func(b bool, ird dto.InvoiceRowDto) bool {
+			return b || ird.DiscountFixed != nil || ird.DiscountPercentage != nil
+		}, false)
+		visible[templatestore.ColumnGross] = isSet(data.ShowGrossColumn)
+	}
+
+	for _, field := range []templatestore.ColumnField{
+		templatestore.ColumnAmount,
+		templatestore.ColumnSerialNumber,
+		templatestore.ColumnBatchNumber,
+		templatestore.ColumnNet,
+		templatestore.ColumnTax,
+		templatestore.ColumnDiscount,
+		templatestore.ColumnGross,
+	} {
+		if visible[field] {
+			columns = append(columns, invoiceColumn{field: field, title: titles[field], width: 25})
+		}
+	}
+
+	return columns
+}
+
+// the amount with its unit, rows without amount are one piece
 func formatAmount(value *float64, unit *string, localizeClient *localize.LocalizeClient) string {
-	if value == nil || unit == nil {
+	if value == nil {
 		return "1"
 	}
 
+	if unit == nil {
+		return localizeClient.FFloat64(*value)
+	}
+
 	return fmt.Sprintf("%v %s", localizeClient.FFloat64(*value), *unit)
 }
 
-func formatMoney(value *float64, localizeClient *localize.LocalizeClient) string {
+func formatMoney(value *float64, cur currency.Unit, localizeClient *localize.LocalizeClient) string {
+	if value == nil {
+		return "-"
+	}
+
+	return localizeClient.FMoney(*value, cur)
+}
+
+func formatOptional(value *string) string {
 	if value == nil {
 		return "-"
 	}
 
-	return fmt.Sprintf("%v EUR", localizeClient.FFloat64(*value))
+	return *value
+}
+
+func formatTaxRate(row *dto.InvoiceRowDto, localizeClient *localize.LocalizeClient) string {
+	if row.TaxPercentage == nil && (row.Tax == nil || row.Net == nil) {
+		return "-"
+	}
+
+	return formatPercentage(rowTaxRate(row), localizeClient)
 }
 
-func formatDiscount(percentage, fixed *float64, localizeClient *localize.LocalizeClient) string {
+func formatDiscount(percentage, fixed *float64, cur currency.Unit, localizeClient *localize.LocalizeClient) string {
 	if fixed != nil {
-		return formatMoney(fixed, localizeClient)
+		return formatMoney(fixed, cur, localizeClient)
 	}
 
 	if percentage != nil {
-		return fmt.Sprintf("%v%", localizeClient.FFloat64(*percentage))
+		return formatPercentage(*percentage, localizeClient)
 	}
 
 	return "-"
 }
 
-func prepareInvoiceLine(style *dto.InvoiceDto, showDiscount bool, title, amount, net, tax, discount, gross string) []string {
-	row := make([]string, 0)
+func formatPercentage(value float64, localizeClient *localize.LocalizeClient) string {
+	return fmt.Sprintf("%v%%", localizeClient.FFloat64(value))
+}
 
-	row = append(row, title)
-	if style.ShowAmountColumn != nil && *style.ShowAmountColumn {
-		row = append(row, amount)
-	}
-	if style.ShowNetColumn != nil && *style.ShowNetColumn {
-		row = append(row, net)
-	}
-	if style.ShowTaxColumn != nil && *style.ShowTaxColumn {
-		row = append(row, tax)
-	}
-	if showDiscount {
-		row = append(row, discount)
-	}
-	if style.ShowGrossColumn != nil && *style.ShowGrossColumn {
-		row = append(row, gross)
+// cells of one row of the invoice-block in the order of the columns
+func prepareInvoiceLine(columns []invoiceColumn, title string, values map[templatestore.ColumnField]string) []string {
+	row := []string{title}
+
+	for _, column := range columns {
+		row = append(row, values[column.field])
 	}
 
 	return row
@@ -1,24 +1,30 @@
 package v1
 
 import (
-	"errors"
+	"fmt"
 
 	"github.com/hodl-repos/pdf-invoice/internal/dto"
 	"github.com/hodl-repos/pdf-invoice/pkg/document"
 	"github.com/hodl-repos/pdf-invoice/pkg/localize"
+	"golang.org/x/text/currency"
 )
 
-func generateInvoiceSumBlock(data *dto.InvoiceDto, pdf *document.Doc, localizeClient *localize.LocalizeClient) error {
-	rawData, err := prepareInvoiceSumData(data, localizeClient)
+func generateInvoiceSumBlock(data *dto.InvoiceDto, cur currency.Unit, kind *documentKind, pdf *document.Doc, style *documentStyle, localizeClient *localize.LocalizeClient) error {
+	rawData, err := prepareInvoiceSumData(data, cur, kind, localizeClient)
 
 	if err != nil {
 		return err
 	}
 
+	//the sums are placed on the right half, on the left for right-to-left
 	l, t, r, _ := pdf.Fpdf.GetMargins()
 	w, _ := pdf.Fpdf.GetPageSize()
-	pdf.SetMargins(w/2.0, t, r)
-	pdf.SetX(w / 2.0)
+	if pdf.IsRTL() {
+		pdf.SetMargins(l, t, w/2.0)
+	} else {
+		pdf.SetMargins(w/2.0, t, r)
+		pdf.SetX(w / 2.0)
+	}
 
 	pdf.SetFontStyle("B")
 
@@ -26,6 +32,7 @@ func generateInvoiceSumBlock(data *dto.InvoiceDto, pdf *document.Doc, localizeCl
 	table.SetAllCellBorders(false)
 	table.SetAllCellPaddings(document.Padding{1, 1, 1, 1})
 	table.SetAllCellTypes(document.CellMulti)
+	table.SetAllCellTextColors(&style.primary)
 
 	table.SetCellAlingsPerColumn([]document.AlignmentType{document.AlignLeft, document.AlignRight})
 
@@ -37,57 +44,47 @@ func generateInvoiceSumBlock(data *dto.InvoiceDto, pdf *document.Doc, localizeCl
 	return nil
 }
 
-func prepareInvoiceSumData(data *dto.InvoiceDto, localizeClient *localize.LocalizeClient) (*[][]string, error) {
+func prepareInvoiceSumData(data *dto.InvoiceDto, cur currency.Unit, kind *documentKind, localizeClient *localize.LocalizeClient) (*[][]string, error) {
+	totals, err := calculateInvoiceTotals(data, cur)
+
+	if err != nil {
+		return nil, err
+	}
+
 	tmp := make([][]string, 0)
 
 	if data.ShowNetSum != nil && *data.ShowNetSum {
-		netSum := 0.0
-
-		for _, item := range *data.Rows {
-			if item.Net == nil {
-				return nil, errors.New("NET NOT DEFINED IN ONE COLUMN")
-			}
+		tmp = append(tmp, []string{
+			localizeClient.TranslateNet(),
+			formatMoney(kind.amount(totals.net), cur, localizeClient),
+		})
+	}
 
-			netSum += *item.Net
+	//the discount is always shown as it reduces the total, it has the
+	//opposite sign of the total
+	if !totals.discount.IsZero() {
+		title := localizeClient.TranslateDiscount()
+		if data.SumDiscountPercentage != nil && *data.SumDiscountPercentage != 0 {
+			title = fmt.Sprintf("%s %s", title, formatPercentage(*data.SumDiscountPercentage, localizeClient))
 		}
 
 		tmp = append(tmp, []string{
-			localizeClient.TranslateNet(),
-			formatMoney(&netSum, localizeClient),
+			title,
+			formatMoney(kind.amount(totals.discount.Neg()), cur, localizeClient),
 		})
 	}
 
 	if data.ShowTaxSum != nil && *data.ShowTaxSum {
-		taxSum := 0.0
-
-		for _, item := range *data.Rows {
-			if item.Tax == nil {
-				return nil, errors.New("TAX-VALUE IS NOT DEFINED IN ONE ROW")
-			}
-
-			taxSum += *item.Tax
-		}
-
 		tmp = append(tmp, []string{
 			localizeClient.TranslateTax(),
-			formatMoney(&taxSum, localizeClient),
+			formatMoney(kind.amount(totals.tax), cur, localizeClient),
 		})
 	}
 
 	if data.ShowGrossSum != nil && *data.ShowGrossSum {
-		grossSum := 0.0
-
-		for _, item := range *data.Rows {
-			if item.Gross == nil {
-				return nil, errors.New("GROSS IS NOT DEFINED IN ONE ROW")
-			}
-
-			grossSum += *item.Gross
-		}
-
 		tmp = append(tmp, []string{
 			localizeClient.TranslateGross(),
-			formatMoney(&grossSum, localizeClient),
+			formatMoney(kind.amount(totals.gross), cur, localizeClient),
 		})
 	}
 
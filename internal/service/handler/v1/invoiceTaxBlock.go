@@ -0,0 +1,79 @@
+package v1
+
+import (
+	"github.com/hodl-repos/pdf-invoice/internal/dto"
+	"github.com/hodl-repos/pdf-invoice/pkg/document"
+	"github.com/hodl-repos/pdf-invoice/pkg/localize"
+	"golang.org/x/text/currency"
+)
+
+func generateInvoiceTaxBlock(data *dto.InvoiceDto, cur currency.Unit, kind *documentKind, pdf *document.Doc, style *documentStyle, localizeClient *localize.LocalizeClient) error {
+	rawData, err := prepareInvoiceTaxData(data, cur, kind, localizeClient)
+
+	if err != nil {
+		return err
+	}
+
+	pdf.Ln(pdf.GetFontLineHeight())
+
+	table, _ := document.NewDocTable(pdf, rawData)
+	table.SetAllCellBorders(false)
+	table.SetHeadType(document.HeadFirstRow)
+	table.SetHeadFont(style.tableHead)
+	table.SetAllCellPaddings(document.Padding{1, 1, 1, 1})
+	table.SetAllCellTypes(document.CellMulti)
+
+	table.SetColTypes([]document.ColumnType{document.ColDyn, document.ColFixed, document.ColFixed, document.ColFixed})
+	table.SetCellAlingsPerColumn([]document.AlignmentType{document.AlignLeft, document.AlignRight, document.AlignRight, document.AlignRight})
+	table.SetAllColFixedWidths(30.0)
+
+	//highlight head and totals
+	bg := fillColorFunc(style.tableHeadBackground)
+	table.SetCellStyleFuncsRow(0, bg)
+	table.SetCellStyleFuncsRow(len(rawData)-1, bg)
+	table.SetCellTextColorsRow(0, &style.accent)
+
+	table.Generate()
+
+	return nil
+}
+
+// prepared tax-rows with 4 columns (rate, taxable amount, tax, gross), the
+// taxable amount is reduced by the document discount, the last row contains
+// the totals
+func prepareInvoiceTaxData(data *dto.InvoiceDto, cur currency.Unit, kind *documentKind, localizeClient *localize.LocalizeClient) ([][]string, error) {
+	totals, err := calculateInvoiceTotals(data, cur)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tmp := make([][]string, 0)
+
+	tmp = append(tmp, []string{
+		localizeClient.TranslateTaxRate(),
+		localizeClient.TranslateTaxableAmount(),
+		localizeClient.TranslateTax(),
+		localizeClient.TranslateGross(),
+	})
+
+	for _, group := range totals.taxes {
+		rate, _ := group.rate.Float64()
+
+		tmp = append(tmp, []string{
+			formatPercentage(rate, localizeClient),
+			formatMoney(kind.amount(group.taxable()), cur, localizeClient),
+			formatMoney(kind.amount(group.tax), cur, localizeClient),
+			formatMoney(kind.amount(group.taxable().Add(group.tax)), cur, localizeClient),
+		})
+	}
+
+	tmp = append(tmp, []string{
+		localizeClient.TranslateTotal(),
+		formatMoney(kind.amount(totals.net.Sub(totals.discount)), cur, localizeClient),
+		formatMoney(kind.amount(totals.tax), cur, localizeClient),
+		formatMoney(kind.amount(totals.gross), cur, localizeClient),
+	})
+
+	return tmp, nil
+}
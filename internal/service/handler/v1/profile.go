@@ -0,0 +1,111 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+
+	"github.com/google/uuid"
+	"github.com/hodl-repos/pdf-invoice/internal/dto"
+	"github.com/hodl-repos/pdf-invoice/pkg/document"
+	"github.com/hodl-repos/pdf-invoice/pkg/profilestore"
+	"github.com/hodl-repos/pdf-invoice/pkg/standardisedError"
+)
+
+// loads the profile with the id from the store
+func getProfile(id uuid.UUID, profileStore *profilestore.ProfileStore) (*dto.ProfileDto, error) {
+	data, ok := profileStore.Get(id)
+	if !ok {
+		return nil, profileNotFoundError(id)
+	}
+
+	profile := &dto.ProfileDto{}
+	if err := json.Unmarshal(data, profile); err != nil {
+		return nil, err
+	}
+
+	profile.ID = &id
+
+	return profile, nil
+}
+
+func profileNotFoundError(id uuid.UUID) error {
+	return &standardisedError.StandardisedError{
+		Type:   "not-found",
+		Title:  "could not find the profile " + id.String(),
+		Status: http.StatusNotFound,
+		Detail: "the profile does not exist or was deleted",
+	}
+}
+
+// applyProfile fills the seller information, bank payment data and style which
+// are not given by the request with the profile, the request wins field by
+// field
+func applyProfile(data *dto.DocumentDto, profileStore *profilestore.ProfileStore) error {
+	profile, err := getProfile(*data.ProfileID, profileStore)
+	if err != nil {
+		return err
+	}
+
+	data.SellerInformation = mergeFields(data.SellerInformation, profile.SellerInformation)
+	data.BankPaymentData = mergeFields(data.BankPaymentData, profile.BankPaymentData)
+	data.Style = mergeFields(data.Style, profile.Style)
+
+	return nil
+}
+
+// applyDunningProfile fills the dunning letter like applyProfile
+func applyDunningProfile(data *dto.DunningDto, profileStore *profilestore.ProfileStore) error {
+	profile, err := getProfile(*data.ProfileID, profileStore)
+	if err != nil {
+		return err
+	}
+
+	data.SellerInformation = mergeFields(data.SellerInformation, profile.SellerInformation)
+	data.BankPaymentData = mergeFields(data.BankPaymentData, profile.BankPaymentData)
+	data.Style = mergeFields(data.Style, profile.Style)
+
+	return nil
+}
+
+// images are taken as a whole, their sources exclude each other
+var imageType = reflect.TypeOf(&document.Image{})
+
+// mergeFields returns a copy of data with the nil pointer fields set to the
+// fields of the profile, nested structs are merged the same way
+func mergeFields[T any](data, profile *T) *T {
+	if data == nil {
+		return profile
+	}
+	if profile == nil {
+		return data
+	}
+
+	merged := new(T)
+	mergeValue(reflect.ValueOf(merged).Elem(), reflect.ValueOf(data).Elem(), reflect.ValueOf(profile).Elem())
+
+	return merged
+}
+
+// mergeValue sets dst to data with the nil pointer fields set to the fields of
+// the profile, the structs of data and profile are not changed
+func mergeValue(dst, data, profile reflect.Value) {
+	dst.Set(data)
+
+	for i := 0; i < dst.NumField(); i++ {
+		field, profileField := dst.Field(i), profile.Field(i)
+		if field.Kind() != reflect.Pointer || !field.CanSet() || profileField.IsNil() {
+			continue
+		}
+
+		switch {
+		case field.IsNil():
+			field.Set(profileField)
+
+		case field.Type().Elem().Kind() == reflect.Struct && field.Type() != imageType:
+			merged := reflect.New(field.Type().Elem())
+			mergeValue(merged.Elem(), field.Elem(), profileField.Elem())
+			field.Set(merged)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/hodl-repos/pdf-invoice/internal/dto"
+	"github.com/hodl-repos/pdf-invoice/pkg/apihelper"
+	"github.com/hodl-repos/pdf-invoice/pkg/jsonutil"
+	"github.com/hodl-repos/pdf-invoice/pkg/logging"
+	"github.com/hodl-repos/pdf-invoice/pkg/profilestore"
+)
+
+// route param of the profile id, mapped by apihelper.MapUuidHeader
+const ProfileIDParam = "profileId"
+
+func ProfileCreateHandler(profileStore *profilestore.ProfileStore) apihelper.HandlerFuncWithError {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		logger := logging.FromContext(ctx)
+
+		logger.Debugln("got request for v1-profile-create")
+
+		var request dto.ProfileDto
+
+		err := apihelper.UnmarshalJsonAndValidateWithError(w, r, &request)
+		if err != nil {
+			return err
+		}
+
+		data, err := marshalProfile(&request)
+		if err != nil {
+			return err
+		}
+
+		id, err := profileStore.Create(data)
+		if err != nil {
+			return err
+		}
+
+		apihelper.WriteCreateRessourceResponse(w, r, id)
+
+		return nil
+	}
+}
+
+func ProfileGetHandler(profileStore *profilestore.ProfileStore) apihelper.HandlerFuncWithError {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		logger := logging.FromContext(ctx)
+
+		logger.Debugln("got request for v1-profile-get")
+
+		id, err := apihelper.GetContextValue[uuid.UUID](ctx, ProfileIDParam)
+		if err != nil {
+			return err
+		}
+
+		profile, err := getProfile(*id, profileStore)
+		if err != nil {
+			return err
+		}
+
+		return jsonutil.MarshalResponseWithError(w, http.StatusOK, profile)
+	}
+}
+
+func ProfileUpdateHandler(profileStore *profilestore.ProfileStore) apihelper.HandlerFuncWithError {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		logger := logging.FromContext(ctx)
+
+		logger.Debugln("got request for v1-profile-update")
+
+		id, err := apihelper.GetContextValue[uuid.UUID](ctx, ProfileIDParam)
+		if err != nil {
+			return err
+		}
+
+		var request dto.ProfileDto
+
+		err = apihelper.UnmarshalJsonAndValidateWithError(w, r, &request)
+		if err != nil {
+			return err
+		}
+
+		data, err := marshalProfile(&request)
+		if err != nil {
+			return err
+		}
+
+		if err := profileStore.Update(*id, data); err != nil {
+			if errors.Is(err, profilestore.ErrProfileNotFound) {
+				return profileNotFoundError(*id)
+			}
+			return err
+		}
+
+		request.ID = id
+
+		return jsonutil.MarshalResponseWithError(w, http.StatusOK, &request)
+	}
+}
+
+func ProfileDeleteHandler(profileStore *profilestore.ProfileStore) apihelper.HandlerFuncWithError {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		logger := logging.FromContext(ctx)
+
+		logger.Debugln("got request for v1-profile-delete")
+
+		id, err := apihelper.GetContextValue[uuid.UUID](ctx, ProfileIDParam)
+		if err != nil {
+			return err
+		}
+
+		if err := profileStore.Delete(*id); err != nil {
+			if errors.Is(err, profilestore.ErrProfileNotFound) {
+				return profileNotFoundError(*id)
+			}
+			return err
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+		return nil
+	}
+}
+
+// the id is given by the path, it is not stored with the profile
+func marshalProfile(profile *dto.ProfileDto) (json.RawMessage, error) {
+	profile.ID = nil
+
+	return json.Marshal(profile)
+}
@@ -0,0 +1,50 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/hodl-repos/pdf-invoice/internal/dto"
+	"github.com/hodl-repos/pdf-invoice/pkg/document"
+	"github.com/stretchr/testify/assert"
+)
+
+func str(value string) *string {
+	return &value
+}
+
+func TestMergeFieldsNested(t *testing.T) {
+	profile := &dto.DocumentStyleDto{
+		Typography: &dto.TypographyDto{
+			Body:    &dto.FontDto{Family: str("Roboto"), Size: float(10)},
+			Heading: &dto.FontDto{Family: str("Lato")},
+		},
+		Theme: &dto.ThemeDto{Primary: str("#112233")},
+		Image: &document.Image{ImageUrl: str("https://example.com/logo.png")},
+	}
+	request := &dto.DocumentStyleDto{
+		Typography: &dto.TypographyDto{Body: &dto.FontDto{Size: float(12)}},
+		Image:      &document.Image{},
+	}
+
+	// the request wins field by field in nested structs as well
+	merged := mergeFields(request, profile)
+	assert.Equal(t, "Roboto", *merged.Typography.Body.Family)
+	assert.Equal(t, 12., *merged.Typography.Body.Size)
+	assert.Equal(t, "Lato", *merged.Typography.Heading.Family)
+	assert.Equal(t, "#112233", *merged.Theme.Primary)
+
+	// the sources of an image exclude each other, it is not merged
+	assert.Nil(t, merged.Image.ImageUrl)
+
+	// request and profile are not changed
+	assert.Nil(t, request.Typography.Body.Family)
+	assert.Equal(t, 10., *profile.Typography.Body.Size)
+
+	seller := mergeFields(
+		&dto.SellerInformationDto{Address: &dto.AddressDto{Street1: str("Hauptplatz 1")}},
+		&dto.SellerInformationDto{Address: &dto.AddressDto{Name: str("ACME"), Street1: str("Ring 2")}, VAT: str("ATU12345678")},
+	)
+	assert.Equal(t, "ACME", *seller.Address.Name)
+	assert.Equal(t, "Hauptplatz 1", *seller.Address.Street1)
+	assert.Equal(t, "ATU12345678", *seller.VAT)
+}
@@ -0,0 +1,44 @@
+package v1
+
+import (
+	"github.com/hodl-repos/pdf-invoice/pkg/document"
+	"github.com/hodl-repos/pdf-invoice/pkg/localize"
+)
+
+// confirmation of receipt with lines for place, date and signature of the
+// customer, the block is not split over pages
+func generateSignatureBlock(pdf *document.Doc, style *documentStyle, localizeClient *localize.LocalizeClient) {
+	pdf.SetFontSpec(style.body)
+	pdf.SetTextColorSpec(style.text)
+
+	//text, 15 space for writing and the labels below the lines
+	lineHeight := pdf.GetFontLineHeight()
+	totalBlockHeight := lineHeight + 15 + lineHeight
+
+	if totalBlockHeight > pdf.GetRemainingPrintHeight() {
+		pdf.AddPage()
+	}
+
+	pdf.MCell(0, lineHeight, localizeClient.TranslateGoodsReceived(), "", "", false)
+
+	//two lines next to each other with 10 space between them
+	l, _, _, _ := pdf.GetMargins()
+	width := (pdf.GetPrintWidth() - 10) / 2
+	y := pdf.GetY() + 15
+
+	pdf.Line(l, y, l+width, y)
+	pdf.Line(l+width+10, y, l+2*width+10, y)
+
+	pdf.SetFontSpec(style.footer)
+	pdf.SetTextColorSpec(style.accent)
+
+	pdf.SetXY(l, y)
+	pdf.MCell(width, lineHeight, localizeClient.TranslatePlaceAndDate(), "", "", false)
+	pdf.SetXY(l+width+10, y)
+	pdf.MCell(width, lineHeight, localizeClient.TranslateSignature(), "", "", false)
+
+	pdf.SetFontSpec(style.body)
+	pdf.SetTextColorSpec(style.text)
+	pdf.SetXY(l, y+lineHeight)
+	pdf.Ln(lineHeight)
+}
@@ -0,0 +1,237 @@
+package v1
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hodl-repos/pdf-invoice/internal/dto"
+	"github.com/hodl-repos/pdf-invoice/pkg/document"
+	"github.com/hodl-repos/pdf-invoice/pkg/fontstore"
+	"github.com/hodl-repos/pdf-invoice/pkg/standardisedError"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// documentStyle contains the resolved fonts and colors of the text blocks of a
+// document
+type documentStyle struct {
+	body      document.FontSpec
+	heading   document.FontSpec
+	tableHead document.FontSpec
+	footer    document.FontSpec
+
+	lineHeight float64
+
+	primary             document.Color
+	accent              document.Color
+	text                document.Color
+	zebra               document.Color
+	border              document.Color
+	tableHeadBackground document.Color
+	bankBlockBorder     document.Color
+
+	//font families of the font store used by the document
+	fonts []*document.Font
+}
+
+// resolveDocumentStyle applies the typography and the theme of the request onto
+// the defaults, font families other than the bundled one are taken from the
+// font store
+func resolveDocumentStyle(data *dto.DocumentStyleDto, fontStore *fontstore.FontStore) (*documentStyle, error) {
+	style := &documentStyle{
+		body:       document.FontSpec{Family: document.FontFamilyDejaVu, Style: "", Size: 10},
+		heading:    document.FontSpec{Family: document.FontFamilyDejaVu, Style: "B", Size: 12},
+		footer:     document.FontSpec{Family: document.FontFamilyDejaVu, Style: "", Size: 8},
+		lineHeight: 1.2,
+
+		primary:             document.ColorBlack,
+		accent:              document.ColorBlack,
+		text:                document.ColorBlack,
+		zebra:               document.ColorLightGray,
+		border:              document.ColorBlack,
+		tableHeadBackground: document.ColorLightGray,
+	}
+
+	if err := resolveTheme(data.Theme, style); err != nil {
+		return nil, err
+	}
+
+	typography := data.Typography
+	if typography == nil {
+		style.tableHead = style.body
+		return style, nil
+	}
+
+	used := usedFonts{}
+	var err error
+
+	if style.body, err = resolveFont(typography.Body, style.body, fontStore, &used); err != nil {
+		return nil, err
+	}
+
+	//the other blocks use the family of the body by default
+	style.heading.Family = style.body.Family
+	style.footer.Family = style.body.Family
+
+	if style.heading, err = resolveFont(typography.Heading, style.heading, fontStore, &used); err != nil {
+		return nil, err
+	}
+	if style.tableHead, err = resolveFont(typography.TableHead, style.body, fontStore, &used); err != nil {
+		return nil, err
+	}
+	if style.footer, err = resolveFont(typography.Footer, style.footer, fontStore, &used); err != nil {
+		return nil, err
+	}
+
+	if typography.LineHeight != nil {
+		style.lineHeight = *typography.LineHeight
+	}
+
+	style.fonts = used
+
+	return style, nil
+}
+
+// usedFonts are the font families of the font store and of the request in the
+// order of their first use, the pdf does not change between runs
+type usedFonts []*document.Font
+
+func (u *usedFonts) add(font *document.Font) {
+	if u.get(font.Family) == nil {
+		*u = append(*u, font)
+	}
+}
+
+func (u *usedFonts) get(family string) *document.Font {
+	for _, used := range *u {
+		if strings.EqualFold(used.Family, family) {
+			return used
+		}
+	}
+
+	return nil
+}
+
+func resolveFont(data *dto.FontDto, def document.FontSpec, fontStore *fontstore.FontStore, used *usedFonts) (document.FontSpec, error) {
+	if data == nil {
+		return def, nil
+	}
+
+	font := def
+
+	if data.Family != nil && strings.EqualFold(*data.Family, document.FontFamilyDejaVu) {
+		if data.Data != nil {
+			return font, fontFamilyTakenError(*data.Family)
+		}
+
+		font.Family = document.FontFamilyDejaVu
+	} else if data.Family != nil {
+		//fonts given by data before in the request are used by their name
+		usedFont := used.get(*data.Family)
+		storeFont, inStore := fontStore.Get(*data.Family)
+
+		switch {
+		case data.Data != nil && inStore:
+			return font, fontFamilyTakenError(*data.Family)
+
+		case data.Data != nil && usedFont == nil:
+			requestFont, err := document.NewFontFromData(*data.Family, *data.Data)
+			if err != nil {
+				return font, &standardisedError.StandardisedError{
+					Type:   "validation-error",
+					Title:  "could not load the font " + *data.Family,
+					Status: http.StatusBadRequest,
+					Detail: err.Error(),
+				}
+			}
+
+			usedFont = requestFont
+
+		case usedFont == nil && inStore:
+			usedFont = storeFont
+
+		case usedFont == nil:
+			return font, &standardisedError.StandardisedError{
+				Type:   "validation-error",
+				Title:  "could not find the font family " + *data.Family,
+				Status: http.StatusBadRequest,
+				Detail: "available font families are " + strings.Join(append([]string{document.FontFamilyDejaVu}, fontStore.Families()...), ", "),
+			}
+		}
+
+		font.Family = usedFont.Family
+		used.add(usedFont)
+	}
+
+	if data.Style != nil {
+		font.Style = data.Style.PdfStyle()
+	}
+
+	if data.Size != nil {
+		font.Size = *data.Size
+	}
+
+	return font, nil
+}
+
+func fontFamilyTakenError(family string) error {
+	return &standardisedError.StandardisedError{
+		Type:   "validation-error",
+		Title:  "the font family " + family + " already exists",
+		Status: http.StatusBadRequest,
+		Detail: "fonts given by data require a family which is neither bundled nor in the font store",
+	}
+}
+
+func resolveTheme(data *dto.ThemeDto, style *documentStyle) error {
+	style.bankBlockBorder = style.border
+
+	if data == nil {
+		return nil
+	}
+
+	colors := []struct {
+		color *document.Color
+		value *string
+	}{
+		{&style.primary, data.Primary},
+		{&style.accent, data.Accent},
+		{&style.text, data.Text},
+		{&style.zebra, data.Zebra},
+		{&style.border, data.Border},
+		{&style.tableHeadBackground, data.TableHeadBackground},
+		{&style.bankBlockBorder, data.BankBlockBorder},
+	}
+
+	for _, c := range colors {
+		if c.value == nil {
+			continue
+		}
+
+		color, err := document.ParseHexColor(*c.value)
+		if err != nil {
+			return &standardisedError.StandardisedError{
+				Type:   "validation-error",
+				Title:  "could not parse the color " + *c.value,
+				Status: http.StatusBadRequest,
+				Detail: err.Error(),
+			}
+		}
+
+		*c.color = color
+	}
+
+	if data.BankBlockBorder == nil {
+		style.bankBlockBorder = style.border
+	}
+
+	return nil
+}
+
+// fillColorFunc returns a style func of DocTable filling the cell with the color
+func fillColorFunc(color document.Color) *func(gofpdf.Fpdf) {
+	f := func(fpdf gofpdf.Fpdf) {
+		fpdf.SetFillColor(color.R, color.G, color.B)
+	}
+
+	return &f
+}
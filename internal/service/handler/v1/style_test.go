@@ -0,0 +1,41 @@
+package v1
+
+import (
+	"encoding/base64"
+	"os"
+	"testing"
+
+	"github.com/hodl-repos/pdf-invoice/internal/dto"
+	"github.com/hodl-repos/pdf-invoice/pkg/document"
+	"github.com/hodl-repos/pdf-invoice/pkg/standardisedError"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveFontData(t *testing.T) {
+	regular, err := os.ReadFile("../../../../pkg/document/fonts/DejaVuSansCondensed.ttf")
+	assert.NoError(t, err)
+
+	data := base64.StdEncoding.EncodeToString(regular)
+	def := document.FontSpec{Family: document.FontFamilyDejaVu, Size: 10}
+	used := usedFonts{}
+
+	font, err := resolveFont(&dto.FontDto{Family: str("Brand"), Data: &data}, def, nil, &used)
+	assert.NoError(t, err)
+	assert.Equal(t, document.FontSpec{Family: "Brand", Size: 10}, font)
+	assert.Len(t, used, 1)
+
+	// the other fonts of the request use the name
+	font, err = resolveFont(&dto.FontDto{Family: str("brand")}, def, nil, &used)
+	assert.NoError(t, err)
+	assert.Equal(t, "Brand", font.Family)
+	assert.Len(t, used, 1)
+
+	// the bundled font is not replaced
+	_, err = resolveFont(&dto.FontDto{Family: str("DejaVu"), Data: &data}, def, nil, &used)
+	assert.IsType(t, &standardisedError.StandardisedError{}, err)
+
+	invalid := base64.StdEncoding.EncodeToString([]byte("OTTO font"))
+	_, err = resolveFont(&dto.FontDto{Family: str("Other"), Data: &invalid}, def, nil, &used)
+	assert.IsType(t, &standardisedError.StandardisedError{}, err)
+	assert.Len(t, used, 1)
+}
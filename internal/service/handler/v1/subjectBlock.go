@@ -0,0 +1,50 @@
+package v1
+
+import (
+	"github.com/hodl-repos/pdf-invoice/internal/dto"
+	"github.com/hodl-repos/pdf-invoice/pkg/document"
+)
+
+// title line and the optional introduction, the placeholders are replaced by
+// the values of the document
+func generateSubjectBlock(data *dto.SubjectDto, kind *documentKind, values map[string]string, pdf *document.Doc, style *documentStyle) {
+	title := kind.title()
+	if data.Title != nil {
+		title = fillPlaceholders(*data.Title, values)
+	}
+
+	pdf.SetFontSpec(style.heading)
+	pdf.SetTextColorSpec(style.primary)
+	pdf.MCell(0, pdf.GetFontLineHeight(), title, "", "", false)
+
+	pdf.SetFontSpec(style.body)
+	pdf.SetTextColorSpec(style.text)
+
+	if data.Introduction != nil {
+		pdf.Ln(pdf.GetFontLineHeight() / 2)
+		pdf.MCell(0, pdf.GetFontLineHeight(), fillPlaceholders(*data.Introduction, values), "", "", false)
+	}
+
+	pdf.Ln(pdf.GetFontLineHeight())
+}
+
+// values of the placeholders in the subject
+func prepareSubjectValues(data *dto.DocumentDto, kind *documentKind) map[string]string {
+	values := map[string]string{
+		"CustomerName": *data.InvoiceAddress.Name,
+		"DocumentType": kind.name,
+		"Number":       kind.number,
+		"Date":         "",
+		"DueDate":      "",
+	}
+
+	if kind.date != nil {
+		values["Date"] = kind.date.Format("2006-01-02")
+	}
+
+	if data.InvoiceInformation.DueDate != nil {
+		values["DueDate"] = data.InvoiceInformation.DueDate.Format("2006-01-02")
+	}
+
+	return values
+}
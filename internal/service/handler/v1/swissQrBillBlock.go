@@ -0,0 +1,338 @@
+package v1
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+
+	"github.com/hodl-repos/pdf-invoice/internal/dto"
+	"github.com/hodl-repos/pdf-invoice/pkg/bank"
+	"github.com/hodl-repos/pdf-invoice/pkg/document"
+	"github.com/hodl-repos/pdf-invoice/pkg/einvoice"
+	"github.com/hodl-repos/pdf-invoice/pkg/localize"
+	"github.com/hodl-repos/pdf-invoice/pkg/qr"
+	"github.com/hodl-repos/pdf-invoice/pkg/standardisedError"
+	"github.com/jung-kurt/gofpdf"
+	"github.com/skip2/go-qrcode"
+	"golang.org/x/text/currency"
+)
+
+// dimensions of the QR-bill in mm
+const (
+	qrBillHeight       = 105.0
+	qrBillReceiptWidth = 62.0
+	qrBillMargin       = 5.0
+	qrBillCodeSize     = 46.0
+	ptToMm             = 25.4 / 72
+)
+
+// the QR-bill is drawn at the bottom of the current page (a new page is added
+// if there is not enough space), it replaces the footer of this page and the
+// cursor is placed below it, following text continues on a new page
+func generateSwissQrBillBlock(data *dto.DocumentDto, cur currency.Unit, pdf *document.Doc, localizeClient *localize.LocalizeClient) error {
+	bill, err := generateSwissQrBillFromDto(data, cur)
+	if err != nil {
+		return err
+	}
+
+	//the QR-bill requires error correction level M
+	qrCode, err := qr.GenerateQrCode(bill.GenerateCode(), qr.WithRecoveryLevel(qrcode.Medium))
+	if err != nil {
+		return err
+	}
+
+	w, h := pdf.GetPageSize()
+	top := h - qrBillHeight
+
+	//space for the separation text above the bill
+	if pdf.GetY() > top-qrBillMargin {
+		pdf.AddPage()
+	}
+
+	pdf.SkipFooter()
+
+	//the QR-bill is always printed in black
+	font := pdf.GetFont()
+	textColor, drawColor := pdf.GetTextColorSpec(), pdf.GetDrawColorSpec()
+	pdf.SetTextColorSpec(document.ColorBlack)
+	pdf.SetDrawColorSpec(document.ColorBlack)
+
+	autoPageBreak, bottomMargin := pdf.GetAutoPageBreak()
+	pdf.SetAutoPageBreak(false, 0)
+	l, t, r, _ := pdf.GetMargins()
+	pdf.SetMargins(0, t, 0)
+
+	drawSwissQrBillSeparation(pdf, top, w, h, localizeClient)
+	drawSwissQrBillReceipt(pdf, bill, top, localizeClient)
+	drawSwissQrBillPaymentPart(pdf, bill, qrCode, top, localizeClient)
+
+	pdf.SetMargins(l, t, r)
+	pdf.SetAutoPageBreak(autoPageBreak, bottomMargin)
+	pdf.SetXY(l, h)
+	pdf.SetFontSpec(font)
+	pdf.SetTextColorSpec(textColor)
+	pdf.SetDrawColorSpec(drawColor)
+
+	return nil
+}
+
+func generateSwissQrBillFromDto(data *dto.DocumentDto, cur currency.Unit) (*bank.SwissQrBillDto, error) {
+	bill := &bank.SwissQrBillDto{
+		Creditor: swissAddressFromDto(data.SellerInformation.Address),
+	}
+	bill.Creditor.Name = *data.BankPaymentData.AccountHolder
+
+	//the debtor is filled in by hand when the address is incomplete
+	if debtor := swissAddressFromDto(data.InvoiceAddress.AddressDto); len(debtor.PostalCode) > 0 && len(debtor.Town) > 0 && len(debtor.Country) > 0 {
+		bill.Debtor = &debtor
+	}
+
+	if data.BankPaymentData.RemittanceInformation != nil {
+		bill.Message = *data.BankPaymentData.RemittanceInformation
+	}
+
+	totals, err := calculateInvoiceTotals(data.InvoiceData, cur)
+	if err != nil {
+		return nil, err
+	}
+
+	reference := ""
+	if data.BankPaymentData.PaymentReference != nil {
+		reference = *data.BankPaymentData.PaymentReference
+	}
+
+	err = bill.SetIBAN(*data.BankPaymentData.IBAN)
+	if err == nil {
+		err = bill.SetAmount(cur, *floatPtr(totals.gross))
+	}
+	if err == nil {
+		err = bill.SetReference(reference)
+	}
+	if err == nil {
+		err = bill.Validate()
+	}
+
+	if err != nil {
+		return nil, &standardisedError.StandardisedError{
+			Type:   "validation-error",
+			Title:  "could not generate the swiss QR-bill",
+			Status: http.StatusBadRequest,
+			Detail: err.Error(),
+		}
+	}
+
+	return bill, nil
+}
+
+func swissAddressFromDto(data *dto.AddressDto) bank.SwissAddress {
+	address := bank.SwissAddress{
+		Name: *data.Name,
+	}
+
+	if data.Street1 != nil {
+		address.Street = *data.Street1
+	}
+
+	if data.Zip != nil {
+		address.PostalCode = *data.Zip
+	}
+
+	if data.City != nil {
+		address.Town = *data.City
+	}
+
+	if data.Country != nil {
+		address.Country = einvoice.CountryCode(*data.Country)
+	}
+
+	return address
+}
+
+// printed lines of an address, the country code is only shown for foreign
+// addresses
+func swissAddressLines(address *bank.SwissAddress) []string {
+	lines := []string{address.Name}
+
+	if street := strings.TrimSpace(address.Street + " " + address.BuildingNumber); len(street) > 0 {
+		lines = append(lines, street)
+	}
+
+	postalCode := address.PostalCode
+	if address.Country != "CH" && address.Country != "LI" {
+		postalCode = address.Country + "-" + postalCode
+	}
+
+	return append(lines, postalCode+" "+address.Town)
+}
+
+// amounts are printed with a space as thousands separator
+func formatSwissAmount(amount string) string {
+	integer, fraction, _ := strings.Cut(amount, ".")
+
+	var sb strings.Builder
+	for i, r := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			sb.WriteString(" ")
+		}
+		sb.WriteRune(r)
+	}
+
+	return sb.String() + "." + fraction
+}
+
+func drawSwissQrBillSeparation(pdf *document.Doc, top, w, h float64, localizeClient *localize.LocalizeClient) {
+	pdf.SetFont("", "", 7)
+	pdf.SetXY(0, top-3)
+	pdf.CFormat(w, 3, localizeClient.TranslateQrBillSeparate(), "", 0, "C", false, 0, "")
+
+	pdf.SetDashPattern([]float64{1, 1}, 0)
+	pdf.Line(0, top, w, top)
+	pdf.Line(qrBillReceiptWidth, top, qrBillReceiptWidth, h)
+	pdf.SetDashPattern([]float64{}, 0)
+}
+
+func drawSwissQrBillReceipt(pdf *document.Doc, bill *bank.SwissQrBillDto, top float64, localizeClient *localize.LocalizeClient) {
+	x := qrBillMargin
+	width := qrBillReceiptWidth - 2*qrBillMargin
+
+	drawSwissQrBillTitle(pdf, x, top+qrBillMargin, localizeClient.TranslateQrBillReceipt())
+
+	//information
+	y := top + 12
+	account := append([]string{bank.FormatIBAN(bill.IBAN)}, swissAddressLines(&bill.Creditor)...)
+	y = drawSwissQrBillSection(pdf, x, y, width, 6, 8, localizeClient.TranslateQrBillAccount(), account)
+
+	if len(bill.Reference) > 0 {
+		y = drawSwissQrBillSection(pdf, x, y, width, 6, 8, localizeClient.TranslateQrBillReference(), []string{bank.FormatSwissReference(bill.Reference)})
+	}
+
+	if bill.Debtor != nil {
+		drawSwissQrBillSection(pdf, x, y, width, 6, 8, localizeClient.TranslateQrBillPayableBy(), swissAddressLines(bill.Debtor))
+	} else {
+		y = drawSwissQrBillSection(pdf, x, y, width, 6, 8, localizeClient.TranslateQrBillPayableByNameAddress(), nil)
+		drawCornerMarks(pdf, x, y-9*ptToMm, 52, 20)
+	}
+
+	//amount
+	drawSwissQrBillAmount(pdf, bill, x, top+68, 12, 6, 8, localizeClient)
+
+	//acceptance point
+	pdf.SetFont("", "B", 6)
+	pdf.SetXY(x, top+82)
+	pdf.CFormat(width, 9*ptToMm, localizeClient.TranslateQrBillAcceptancePoint(), "", 0, "R", false, 0, "")
+}
+
+func drawSwissQrBillPaymentPart(pdf *document.Doc, bill *bank.SwissQrBillDto, qrCode *[]byte, top float64, localizeClient *localize.LocalizeClient) {
+	x := qrBillReceiptWidth + qrBillMargin
+
+	drawSwissQrBillTitle(pdf, x, top+qrBillMargin, localizeClient.TranslateQrBillPaymentPart())
+
+	//qr code with the swiss cross in the center
+	codeY := top + 17
+	pdf.RegisterImageOptionsReader("swiss-qr-bill-code", gofpdf.ImageOptions{ImageType: "png", ReadDpi: true}, bytes.NewReader(*qrCode))
+	pdf.ImageOptions("swiss-qr-bill-code", x, codeY, qrBillCodeSize, qrBillCodeSize, false, gofpdf.ImageOptions{ReadDpi: true}, 0, "")
+	drawSwissCross(pdf, x+qrBillCodeSize/2, codeY+qrBillCodeSize/2)
+
+	//amount
+	drawSwissQrBillAmount(pdf, bill, x, top+68, 14, 8, 10, localizeClient)
+
+	//information
+	infoX := qrBillReceiptWidth + 56
+	width := 210 - infoX - qrBillMargin
+	y := top + qrBillMargin
+
+	account := append([]string{bank.FormatIBAN(bill.IBAN)}, swissAddressLines(&bill.Creditor)...)
+	y = drawSwissQrBillSection(pdf, infoX, y, width, 8, 10, localizeClient.TranslateQrBillAccount(), account)
+
+	if len(bill.Reference) > 0 {
+		y = drawSwissQrBillSection(pdf, infoX, y, width, 8, 10, localizeClient.TranslateQrBillReference(), []string{bank.FormatSwissReference(bill.Reference)})
+	}
+
+	if len(bill.Message) > 0 {
+		y = drawSwissQrBillSection(pdf, infoX, y, width, 8, 10, localizeClient.TranslateQrBillAdditionalInformation(), []string{bill.Message})
+	}
+
+	if bill.Debtor != nil {
+		drawSwissQrBillSection(pdf, infoX, y, width, 8, 10, localizeClient.TranslateQrBillPayableBy(), swissAddressLines(bill.Debtor))
+	} else {
+		y = drawSwissQrBillSection(pdf, infoX, y, width, 8, 10, localizeClient.TranslateQrBillPayableByNameAddress(), nil)
+		drawCornerMarks(pdf, infoX, y-11*ptToMm, 65, 25)
+	}
+}
+
+func drawSwissQrBillTitle(pdf *document.Doc, x, y float64, title string) {
+	pdf.SetFont("", "B", 11)
+	pdf.SetXY(x, y)
+	pdf.CFormat(0, 11*ptToMm, title, "", 0, "L", false, 0, "")
+}
+
+// draws a heading with its values (line spacing is the value font size + 1pt)
+// and returns the position after a blank line
+func drawSwissQrBillSection(pdf *document.Doc, x, y, width, headingSize, valueSize float64, heading string, values []string) float64 {
+	lineHeight := (valueSize + 1) * ptToMm
+
+	pdf.SetFont("", "B", headingSize)
+	pdf.SetXY(x, y)
+	pdf.CFormat(width, lineHeight, heading, "", 2, "L", false, 0, "")
+
+	if len(values) > 0 {
+		pdf.SetFont("", "", valueSize)
+		pdf.SetX(x)
+		pdf.MCell(width, lineHeight, strings.Join(values, "\n"), "", "L", false)
+	}
+
+	return pdf.GetY() + lineHeight
+}
+
+// currency and amount, amountOffset is the x offset of the amount column
+func drawSwissQrBillAmount(pdf *document.Doc, bill *bank.SwissQrBillDto, x, y, amountOffset, headingSize, valueSize float64, localizeClient *localize.LocalizeClient) {
+	lineHeight := (valueSize + 1) * ptToMm
+
+	pdf.SetFont("", "B", headingSize)
+	pdf.SetXY(x, y)
+	pdf.CFormat(amountOffset, lineHeight, localizeClient.TranslateQrBillCurrency(), "", 0, "L", false, 0, "")
+	pdf.CFormat(0, lineHeight, localizeClient.TranslateQrBillAmount(), "", 0, "L", false, 0, "")
+
+	pdf.SetFont("", "", valueSize)
+	pdf.SetXY(x, y+lineHeight)
+	pdf.CFormat(amountOffset, lineHeight, bill.Currency, "", 0, "L", false, 0, "")
+
+	if bill.Amount != nil {
+		pdf.CFormat(0, lineHeight, formatSwissAmount(*bill.Amount), "", 0, "L", false, 0, "")
+	}
+}
+
+// white bordered swiss cross of 7x7mm
+func drawSwissCross(pdf *document.Doc, cx, cy float64) {
+	armWidth := 1.17
+	armLength := 3.9
+
+	pdf.SetFillColor(255, 255, 255)
+	pdf.Rect(cx-3.5, cy-3.5, 7, 7, "F")
+
+	pdf.SetFillColor(0, 0, 0)
+	pdf.Rect(cx-3, cy-3, 6, 6, "F")
+
+	pdf.SetFillColor(255, 255, 255)
+	pdf.Rect(cx-armWidth/2, cy-armLength/2, armWidth, armLength, "F")
+	pdf.Rect(cx-armLength/2, cy-armWidth/2, armLength, armWidth, "F")
+}
+
+// corner marks of a field which is filled in by hand
+func drawCornerMarks(pdf *document.Doc, x, y, w, h float64) {
+	length := 3.0
+
+	lineWidth := pdf.GetLineWidth()
+	pdf.SetLineWidth(0.75 * ptToMm)
+
+	pdf.Line(x, y, x+length, y)
+	pdf.Line(x, y, x, y+length)
+	pdf.Line(x+w-length, y, x+w, y)
+	pdf.Line(x+w, y, x+w, y+length)
+	pdf.Line(x, y+h, x+length, y+h)
+	pdf.Line(x, y+h-length, x, y+h)
+	pdf.Line(x+w-length, y+h, x+w, y+h)
+	pdf.Line(x+w, y+h-length, x+w, y+h)
+
+	pdf.SetLineWidth(lineWidth)
+}
@@ -0,0 +1,198 @@
+package v1
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hodl-repos/pdf-invoice/internal/dto"
+	"github.com/hodl-repos/pdf-invoice/pkg/bank"
+	"github.com/hodl-repos/pdf-invoice/pkg/document"
+	"github.com/hodl-repos/pdf-invoice/pkg/fontstore"
+	"github.com/hodl-repos/pdf-invoice/pkg/localize"
+	"github.com/hodl-repos/pdf-invoice/pkg/standardisedError"
+	"github.com/hodl-repos/pdf-invoice/pkg/templatestore"
+	"golang.org/x/text/currency"
+)
+
+// resolveTemplate returns the template selected by the style, the built-in
+// template when none is selected
+func resolveTemplate(data *dto.DocumentStyleDto, templateStore *templatestore.TemplateStore) (*templatestore.Template, error) {
+	var name string
+	if data.Template != nil {
+		name = *data.Template
+	}
+
+	template, ok := templateStore.Get(name)
+	if !ok {
+		return nil, &standardisedError.StandardisedError{
+			Type:   "validation-error",
+			Title:  "could not find the template " + name,
+			Status: http.StatusBadRequest,
+			Detail: "available templates are " + strings.Join(templateStore.Names(), ", "),
+		}
+	}
+
+	return template, nil
+}
+
+// resolveTemplateFonts returns the body font of every block of the template,
+// font families of the font store are added to the fonts of the style
+func resolveTemplateFonts(template *templatestore.Template, style *documentStyle, fontStore *fontstore.FontStore) ([]document.FontSpec, error) {
+	used := append(usedFonts{}, style.fonts...)
+
+	fonts := make([]document.FontSpec, 0)
+
+	for _, block := range template.Blocks {
+		if block.Font == nil {
+			fonts = append(fonts, style.body)
+			continue
+		}
+
+		data := &dto.FontDto{}
+		if len(block.Font.Family) > 0 {
+			data.Family = &block.Font.Family
+		}
+		if len(block.Font.Style) > 0 {
+			fontStyle := dto.FontStyle(block.Font.Style)
+			data.Style = &fontStyle
+		}
+		if block.Font.Size > 0 {
+			data.Size = &block.Font.Size
+		}
+
+		font, err := resolveFont(data, style.body, fontStore, &used)
+		if err != nil {
+			return nil, err
+		}
+
+		fonts = append(fonts, font)
+	}
+
+	style.fonts = used
+
+	return fonts, nil
+}
+
+// generateTemplateBlock prints one block of the template, blocks without data
+// in the request are skipped. Blocks with a position are printed at the
+// position, the next block continues where the previous one ended.
+func generateTemplateBlock(block *templatestore.Block, data *dto.DocumentDto, cur currency.Unit, kind *documentKind, pdf *document.Doc, style *documentStyle, localizeClient *localize.LocalizeClient) error {
+	//the previous block ended the page, e.g. the swiss QR-bill at its bottom
+	if pdf.GetRemainingPrintHeight() <= 0 {
+		pdf.AddPage()
+	}
+
+	if block.Position != nil {
+		l, _, r, _ := pdf.GetMargins()
+		page, x, y := pdf.PageNo(), pdf.GetX(), pdf.GetY()
+
+		defer func() {
+			pdf.SetLeftMargin(l)
+			pdf.SetRightMargin(r)
+
+			//a block breaking over pages is continued below it
+			if pdf.PageNo() == page {
+				pdf.SetXY(x, y)
+			} else {
+				pdf.SetX(l)
+			}
+		}()
+
+		pageWidth, _ := pdf.GetPageSize()
+		pdf.SetLeftMargin(block.Position.X)
+		if block.Position.Width > 0 {
+			pdf.SetRightMargin(pageWidth - block.Position.X - block.Position.Width)
+		}
+		pdf.SetXY(block.Position.X, block.Position.Y)
+	}
+
+	pdf.SetFontSpec(style.body)
+	pdf.SetTextColorSpec(style.text)
+
+	switch block.Type {
+	case templatestore.BlockHeader:
+		information := prepareInformationCells(data.InvoiceInformation, kind, localizeClient)
+		return generateHeaderBlock(data.Style, data.InvoiceAddress, information, pdf, style)
+
+	case templatestore.BlockSubject:
+		if data.Subject != nil {
+			generateSubjectBlock(data.Subject, kind, prepareSubjectValues(data, kind), pdf, style)
+		}
+
+	case templatestore.BlockCustomerAddress:
+		if data.CustomerAddress != nil {
+			generateCustomerAddressBlock(data.CustomerAddress, pdf, style, localizeClient)
+		}
+
+	case templatestore.BlockItems:
+		return generateInvoiceBlock(data.InvoiceData, block.Columns, cur, kind, pdf, style, localizeClient)
+
+	case templatestore.BlockTaxBreakdown:
+		//documents without prices have no sums
+		if kind.priced && data.InvoiceData.ShowTaxBreakdown != nil && *data.InvoiceData.ShowTaxBreakdown {
+			return generateInvoiceTaxBlock(data.InvoiceData, cur, kind, pdf, style, localizeClient)
+		}
+
+	case templatestore.BlockSums:
+		if kind.priced {
+			return generateInvoiceSumBlock(data.InvoiceData, cur, kind, pdf, style, localizeClient)
+		}
+
+	case templatestore.BlockSuffix:
+		if data.InvoiceDataSuffix != nil {
+			pdf.RichText(0, pdf.GetFontLineHeight(), *data.InvoiceDataSuffix, "")
+
+			pdf.Ln(pdf.GetFontLineHeight())
+		}
+
+	case templatestore.BlockSignature:
+		//confirmation of receipt on delivery notes
+		if kind.delivery {
+			generateSignatureBlock(pdf, style, localizeClient)
+		}
+
+	case templatestore.BlockBankPayment:
+		return generateBankPaymentBlock(data, cur, kind, pdf, style, localizeClient)
+
+	case templatestore.BlockText:
+		pdf.RichText(0, pdf.GetFontLineHeight(), fillRichPlaceholders(block.Text, prepareSubjectValues(data, kind)), templateAlign(block.Align))
+
+	case templatestore.BlockSpace:
+		if block.Height > 0 {
+			pdf.Ln(block.Height)
+		} else {
+			pdf.Ln(pdf.GetFontLineHeight())
+		}
+	}
+
+	return nil
+}
+
+// bank-payment-block, without payment code if nothing is payable
+func generateBankPaymentBlock(data *dto.DocumentDto, cur currency.Unit, kind *documentKind, pdf *document.Doc, style *documentStyle, localizeClient *localize.LocalizeClient) error {
+	if !kind.payable || data.BankPaymentData == nil || data.Style.ShowBankPaymentQrCode == nil || !*data.Style.ShowBankPaymentQrCode {
+		return nil
+	}
+
+	amount, err := payableAmount(data, kind, cur)
+	if err != nil {
+		return err
+	}
+
+	if amount.IsPositive() && data.Style.PaymentQrCodeType != nil && *data.Style.PaymentQrCodeType == bank.QrCodeTypeSwissQrBill {
+		return generateSwissQrBillBlock(data, cur, pdf, localizeClient)
+	}
+
+	return generateBankBlock(data.BankPaymentData, cur, amount, pdf, style, localizeClient)
+}
+
+func templateAlign(align templatestore.Align) string {
+	switch align {
+	case templatestore.AlignCenter:
+		return "C"
+	case templatestore.AlignRight:
+		return "R"
+	default:
+		return ""
+	}
+}
@@ -10,5 +10,30 @@ import (
 func (s *Server) v1Router(r chi.Router) {
 	r.Get("/ping", apihelper.HandlePing())
 
-	r.Post("/generate", errorhandling.WithError(v1.Handler(s.env.Localize())))
+	r.Post("/generate", errorhandling.WithError(v1.Handler(s.env.Localize(), s.env.FontStore(), s.env.TemplateStore(), s.env.ProfileStore(), s.env.AssetStore(), s.env.ImageFetcher())))
+	r.Post("/dunning", errorhandling.WithError(v1.DunningHandler(s.env.Localize(), s.env.FontStore(), s.env.ProfileStore(), s.env.AssetStore(), s.env.ImageFetcher())))
+	r.Post("/xrechnung", errorhandling.WithError(v1.XRechnungHandler(s.env.ProfileStore())))
+
+	r.Route("/profiles", func(r chi.Router) {
+		r.Post("/", errorhandling.WithError(v1.ProfileCreateHandler(s.env.ProfileStore())))
+
+		r.Route("/{"+v1.ProfileIDParam+"}", func(r chi.Router) {
+			r.Use(apihelper.MapUuidHeader(v1.ProfileIDParam))
+
+			r.Get("/", errorhandling.WithError(v1.ProfileGetHandler(s.env.ProfileStore())))
+			r.Put("/", errorhandling.WithError(v1.ProfileUpdateHandler(s.env.ProfileStore())))
+			r.Delete("/", errorhandling.WithError(v1.ProfileDeleteHandler(s.env.ProfileStore())))
+		})
+	})
+
+	r.Route("/assets", func(r chi.Router) {
+		r.Post("/", errorhandling.WithError(v1.AssetCreateHandler(s.env.AssetStore())))
+
+		r.Route("/{"+v1.AssetIDParam+"}", func(r chi.Router) {
+			r.Use(apihelper.MapUuidHeader(v1.AssetIDParam))
+
+			r.Get("/", errorhandling.WithError(v1.AssetGetHandler(s.env.AssetStore())))
+			r.Delete("/", errorhandling.WithError(v1.AssetDeleteHandler(s.env.AssetStore())))
+		})
+	})
 }
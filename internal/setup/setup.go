@@ -7,8 +7,13 @@ import (
 	"github.com/sethvargo/go-envconfig"
 
 	"github.com/hodl-repos/pdf-invoice/internal/serverenv"
+	"github.com/hodl-repos/pdf-invoice/pkg/assetstore"
+	"github.com/hodl-repos/pdf-invoice/pkg/fetcher"
+	"github.com/hodl-repos/pdf-invoice/pkg/fontstore"
 	"github.com/hodl-repos/pdf-invoice/pkg/localize"
 	"github.com/hodl-repos/pdf-invoice/pkg/logging"
+	"github.com/hodl-repos/pdf-invoice/pkg/profilestore"
+	"github.com/hodl-repos/pdf-invoice/pkg/templatestore"
 )
 
 // Setup runs common initialization code for all servers. See SetupWith.
@@ -20,6 +25,26 @@ type LocalizeConfigProvider interface {
 	LocalizeServiceConfig() *localize.Config
 }
 
+type FontStoreConfigProvider interface {
+	FontStoreServiceConfig() *fontstore.Config
+}
+
+type TemplateStoreConfigProvider interface {
+	TemplateStoreServiceConfig() *templatestore.Config
+}
+
+type ProfileStoreConfigProvider interface {
+	ProfileStoreServiceConfig() *profilestore.Config
+}
+
+type AssetStoreConfigProvider interface {
+	AssetStoreServiceConfig() *assetstore.Config
+}
+
+type FetcherConfigProvider interface {
+	FetcherServiceConfig() *fetcher.Config
+}
+
 // SetupWith process the given configuration using envconfig. It is
 // responsible for establishing a database connection, and accessing app
 // configs. The provided interface must implement the various interfaces.
@@ -47,5 +72,80 @@ func SetupWith(ctx context.Context, config interface{}, l envconfig.Lookuper) (*
 		logger.Infow("localization", "config", serviceConfig)
 	}
 
+	if provider, ok := config.(FontStoreConfigProvider); ok {
+		logger.Info("loading fonts")
+
+		storeConfig := provider.FontStoreServiceConfig()
+		store, err := fontstore.NewFontStore(storeConfig)
+		if err != nil {
+			return nil, fmt.Errorf("error loading fonts: %w", err)
+		}
+
+		opt := serverenv.WithFontStore(store)
+		serverEnvOpts = append(serverEnvOpts, opt)
+
+		logger.Infow("fonts", "families", store.Families())
+	}
+
+	if provider, ok := config.(TemplateStoreConfigProvider); ok {
+		logger.Info("loading templates")
+
+		storeConfig := provider.TemplateStoreServiceConfig()
+		store, err := templatestore.NewTemplateStore(storeConfig)
+		if err != nil {
+			return nil, fmt.Errorf("error loading templates: %w", err)
+		}
+
+		opt := serverenv.WithTemplateStore(store)
+		serverEnvOpts = append(serverEnvOpts, opt)
+
+		logger.Infow("templates", "names", store.Names())
+	}
+
+	if provider, ok := config.(ProfileStoreConfigProvider); ok {
+		logger.Info("loading profiles")
+
+		storeConfig := provider.ProfileStoreServiceConfig()
+		store, err := profilestore.NewProfileStore(storeConfig)
+		if err != nil {
+			return nil, fmt.Errorf("error loading profiles: %w", err)
+		}
+
+		opt := serverenv.WithProfileStore(store)
+		serverEnvOpts = append(serverEnvOpts, opt)
+
+		logger.Infow("profiles", "count", len(store.IDs()))
+	}
+
+	if provider, ok := config.(AssetStoreConfigProvider); ok {
+		logger.Info("loading assets")
+
+		storeConfig := provider.AssetStoreServiceConfig()
+		store, err := assetstore.NewAssetStore(storeConfig)
+		if err != nil {
+			return nil, fmt.Errorf("error loading assets: %w", err)
+		}
+
+		opt := serverenv.WithAssetStore(store)
+		serverEnvOpts = append(serverEnvOpts, opt)
+
+		logger.Infow("assets", "config", storeConfig)
+	}
+
+	if provider, ok := config.(FetcherConfigProvider); ok {
+		logger.Info("creating image fetcher")
+
+		fetcherConfig := provider.FetcherServiceConfig()
+		imageFetcher, err := fetcher.NewFetcher(fetcherConfig)
+		if err != nil {
+			return nil, fmt.Errorf("error creating image fetcher: %w", err)
+		}
+
+		opt := serverenv.WithImageFetcher(imageFetcher)
+		serverEnvOpts = append(serverEnvOpts, opt)
+
+		logger.Infow("image fetcher", "config", fetcherConfig)
+	}
+
 	return serverenv.New(ctx, serverEnvOpts...), nil
 }
@@ -296,8 +296,8 @@ export default function () {
     bankPaymentData: {
       accountHolder: "Rotnkopf OG",
       bankName: "VOLKSBANK",
-      iban: "AT123456789023",
-      bic: "RZTIAT3364",
+      iban: "AT611904300234573201",
+      bic: "BKAUATWW",
     },
   });
 
@@ -0,0 +1,128 @@
+package assetstore
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+var ErrAssetNotFound = errors.New("the asset does not exist")
+
+// AssetStore holds uploaded files, e.g. the logos referenced by documents. It
+// implements document.AssetSource.
+type AssetStore struct {
+	mu     sync.RWMutex
+	dir    string
+	assets map[uuid.UUID][]byte
+}
+
+// NewAssetStore loads all assets of the configured directory, the files are
+// named by their id. The directory is created if it does not exist. Without
+// directory the assets are only kept in memory.
+func NewAssetStore(config *Config) (*AssetStore, error) {
+	store := &AssetStore{
+		dir:    config.AssetDir,
+		assets: make(map[uuid.UUID][]byte),
+	}
+
+	if len(store.dir) == 0 {
+		return store, nil
+	}
+
+	if err := os.MkdirAll(store.dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(store.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		//skips temporary files of interrupted uploads
+		id, err := uuid.Parse(entry.Name())
+		if entry.IsDir() || err != nil {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(store.dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		store.assets[id] = content
+	}
+
+	return store, nil
+}
+
+// Create stores a new asset and returns its id.
+func (s *AssetStore) Create(data []byte) (uuid.UUID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := uuid.New()
+
+	//the caller may reuse the buffer
+	data = append([]byte(nil), data...)
+
+	if len(s.dir) > 0 {
+		tmp, err := os.CreateTemp(s.dir, id.String()+".*.tmp")
+		if err != nil {
+			return uuid.Nil, err
+		}
+		defer os.Remove(tmp.Name())
+
+		if _, err := tmp.Write(data); err != nil {
+			tmp.Close()
+			return uuid.Nil, err
+		}
+		if err := tmp.Close(); err != nil {
+			return uuid.Nil, err
+		}
+
+		if err := os.Rename(tmp.Name(), filepath.Join(s.dir, id.String())); err != nil {
+			return uuid.Nil, err
+		}
+	}
+
+	s.assets[id] = data
+
+	return id, nil
+}
+
+// Get returns the content of the asset, a nil store has no assets.
+func (s *AssetStore) Get(id uuid.UUID) ([]byte, bool) {
+	if s == nil {
+		return nil, false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	data, ok := s.assets[id]
+	return data, ok
+}
+
+// Delete removes an existing asset.
+func (s *AssetStore) Delete(id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.assets[id]; !ok {
+		return ErrAssetNotFound
+	}
+
+	if len(s.dir) > 0 {
+		if err := os.Remove(filepath.Join(s.dir, id.String())); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	delete(s.assets, id)
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package assetstore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssetStore(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := NewAssetStore(&Config{AssetDir: dir})
+	assert.NoError(t, err)
+
+	id, err := store.Create([]byte("logo"))
+	assert.NoError(t, err)
+	assert.FileExists(t, filepath.Join(dir, id.String()))
+
+	data, ok := store.Get(id)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("logo"), data)
+
+	// the assets are loaded again after a restart, other files are skipped
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "readme.txt"), []byte("-"), 0o644))
+
+	store, err = NewAssetStore(&Config{AssetDir: dir})
+	assert.NoError(t, err)
+
+	data, ok = store.Get(id)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("logo"), data)
+
+	assert.NoError(t, store.Delete(id))
+	assert.ErrorIs(t, store.Delete(id), ErrAssetNotFound)
+	assert.NoFileExists(t, filepath.Join(dir, id.String()))
+
+	_, ok = store.Get(id)
+	assert.False(t, ok)
+}
+
+func TestAssetStoreInMemory(t *testing.T) {
+	store, err := NewAssetStore(&Config{})
+	assert.NoError(t, err)
+
+	id, err := store.Create([]byte("logo"))
+	assert.NoError(t, err)
+
+	_, ok := store.Get(id)
+	assert.True(t, ok)
+
+	// a missing store has no assets
+	var nilStore *AssetStore
+	_, ok = nilStore.Get(uuid.New())
+	assert.False(t, ok)
+}
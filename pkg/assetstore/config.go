@@ -0,0 +1,10 @@
+package assetstore
+
+type Config struct {
+	//directory with the uploaded images, kept in memory when not set
+	AssetDir string `env:"ASSET_DIR"`
+}
+
+func (c *Config) AssetStoreServiceConfig() *Config {
+	return c
+}
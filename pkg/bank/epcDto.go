@@ -1,7 +1,82 @@
 package bank
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 
+	"golang.org/x/text/currency"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// ErrCurrencyNotSupported is returned for amounts in other currencies than
+// EUR, the EPC QR code only supports SEPA credit transfers in EUR.
+var ErrCurrencyNotSupported = errors.New("the EPC QR code only supports amounts in EUR")
+
+var (
+	ErrEpcServiceTag       = errors.New("the service tag has to be BCD")
+	ErrEpcVersion          = errors.New("the version has to be 001 or 002")
+	ErrEpcCharacterSet     = errors.New("the character set has to be between 1 and 8")
+	ErrEpcEncoding         = errors.New("the text can not be encoded in the character set")
+	ErrEpcIdentification   = errors.New("the identification has to be SCT")
+	ErrEpcRequired         = errors.New("the field is required")
+	ErrEpcBICRequired      = errors.New("version 001 requires a BIC")
+	ErrEpcBIC              = errors.New("the BIC is not valid")
+	ErrEpcIBAN             = errors.New("the IBAN is not valid")
+	ErrEpcTooLong          = errors.New("the field exceeds its maximum length")
+	ErrEpcAmount           = errors.New("the amount has to be between EUR0.01 and EUR999999999.99")
+	ErrEpcPurpose          = errors.New("the purpose has to be a 4 letter ISO 20022 purpose code")
+	ErrEpcReferenceAndText = errors.New("either the structured reference or the unstructured text is allowed")
+	ErrEpcPayloadTooLong   = errors.New("the payload exceeds 331 bytes")
+)
+
+// EpcFieldError is returned for an invalid field of the EPC QR code, the
+// cause is one of the ErrEpc errors.
+type EpcFieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *EpcFieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Err.Error())
+}
+
+func (e *EpcFieldError) Unwrap() error {
+	return e.Err
+}
+
+const (
+	EpcServiceTag     = "BCD"
+	EpcVersion1       = "001"
+	EpcVersion2       = "002"
+	EpcIdentification = "SCT"
+
+	// the EPC QR code is limited to 331 bytes
+	epcMaxPayload = 331
+)
+
+// encodings of the character sets 1 (UTF-8) to 8, UTF-8 needs no encoding
+var epcCharacterSets = map[string]encoding.Encoding{
+	"1": encoding.Nop,
+	"2": charmap.ISO8859_1,
+	"3": charmap.ISO8859_2,
+	"4": charmap.ISO8859_4,
+	"5": charmap.ISO8859_5,
+	"6": charmap.ISO8859_7,
+	"7": charmap.ISO8859_10,
+	"8": charmap.ISO8859_15,
+}
+
+var (
+	epcAmountRegex  = regexp.MustCompile(`^EUR[0-9]{1,9}(\.[0-9]{1,2})?$`)
+	epcPurposeRegex = regexp.MustCompile(`^[A-Z]{4}$`)
+)
+
+// EpcDto is the payload of the EPC QR code (EPC069-12) to initiate a SEPA
+// credit transfer.
 type EpcDto struct {
 	ServiceTag       *string
 	Version          *string
@@ -11,71 +86,120 @@ type EpcDto struct {
 	Name             *string
 	IBAN             *string
 	Amount           *string
-	Reason           *string
+	Purpose          *string
 	InvoiceReference *string
 	Text             *string
 	Information      *string
 }
 
-func (dto *EpcDto) GenerateCode() string {
-	tmp := fmt.Sprintf("%s\n%s\n%s\n%s", *dto.ServiceTag, *dto.Version, *dto.CharacterSet, *dto.Identification)
+// GenerateCode validates the fields and creates the content of the EPC QR
+// code encoded in its character set. Empty elements at the end are omitted.
+func (dto *EpcDto) GenerateCode() (string, error) {
+	if err := dto.Validate(); err != nil {
+		return "", err
+	}
+
+	lines := []string{
+		value(dto.ServiceTag),
+		value(dto.Version),
+		value(dto.CharacterSet),
+		value(dto.Identification),
+		strings.ToUpper(compact(value(dto.BIC))),
+		value(dto.Name),
+		strings.ToUpper(compact(value(dto.IBAN))),
+		value(dto.Amount),
+		value(dto.Purpose),
+		value(dto.InvoiceReference),
+		value(dto.Text),
+		value(dto.Information),
+	}
+
+	for len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+
+	code, err := epcCharacterSets[*dto.CharacterSet].NewEncoder().String(strings.Join(lines, "\n"))
+	if err != nil {
+		return "", &EpcFieldError{Field: "CharacterSet", Err: ErrEpcEncoding}
+	}
+
+	if len(code) > epcMaxPayload {
+		return "", &EpcFieldError{Field: "Payload", Err: ErrEpcPayloadTooLong}
+	}
+
+	return code, nil
+}
+
+// Validate checks the fields against the rules of EPC069-12, the returned
+// error is an *EpcFieldError.
+func (dto *EpcDto) Validate() error {
+	if value(dto.ServiceTag) != EpcServiceTag {
+		return &EpcFieldError{Field: "ServiceTag", Err: ErrEpcServiceTag}
+	}
+
+	if v := value(dto.Version); v != EpcVersion1 && v != EpcVersion2 {
+		return &EpcFieldError{Field: "Version", Err: ErrEpcVersion}
+	}
+
+	if _, ok := epcCharacterSets[value(dto.CharacterSet)]; !ok {
+		return &EpcFieldError{Field: "CharacterSet", Err: ErrEpcCharacterSet}
+	}
 
-	if dto.BIC != nil {
-		tmp = fmt.Sprintf("%s\n%s", tmp, *dto.BIC)
-	} else {
-		tmp = tmp + "\n"
+	if value(dto.Identification) != EpcIdentification {
+		return &EpcFieldError{Field: "Identification", Err: ErrEpcIdentification}
 	}
 
-	if dto.Name != nil {
-		tmp = fmt.Sprintf("%s\n%s", tmp, *dto.Name)
-	} else {
-		tmp = tmp + "\n"
+	if bic := value(dto.BIC); len(bic) == 0 {
+		if *dto.Version == EpcVersion1 {
+			return &EpcFieldError{Field: "BIC", Err: ErrEpcBICRequired}
+		}
+	} else if !ValidBIC(bic) {
+		return &EpcFieldError{Field: "BIC", Err: ErrEpcBIC}
 	}
 
-	if dto.IBAN != nil {
-		tmp = fmt.Sprintf("%s\n%s", tmp, *dto.IBAN)
-	} else {
-		tmp = tmp + "\n"
+	if err := validateEpcText("Name", value(dto.Name), 70, true); err != nil {
+		return err
 	}
 
-	if dto.Amount != nil {
-		tmp = fmt.Sprintf("%s\n%s", tmp, *dto.Amount)
-	} else {
-		tmp = tmp + "\n"
+	if iban := compact(value(dto.IBAN)); len(iban) > 34 || !ValidIBAN(iban) {
+		return &EpcFieldError{Field: "IBAN", Err: ErrEpcIBAN}
+	}
+
+	if amount := value(dto.Amount); len(amount) > 0 {
+		if !epcAmountRegex.MatchString(amount) {
+			return &EpcFieldError{Field: "Amount", Err: ErrEpcAmount}
+		}
+
+		if v, _ := strconv.ParseFloat(amount[3:], 64); v < 0.01 {
+			return &EpcFieldError{Field: "Amount", Err: ErrEpcAmount}
+		}
 	}
 
-	if dto.Reason != nil {
-		tmp = fmt.Sprintf("%s\n%s", tmp, *dto.Reason)
-	} else {
-		tmp = tmp + "\n"
+	if purpose := value(dto.Purpose); len(purpose) > 0 && !epcPurposeRegex.MatchString(purpose) {
+		return &EpcFieldError{Field: "Purpose", Err: ErrEpcPurpose}
 	}
 
-	if dto.InvoiceReference != nil {
-		tmp = fmt.Sprintf("%s\n%s", tmp, *dto.InvoiceReference)
-	} else {
-		tmp = tmp + "\n"
+	if len(value(dto.InvoiceReference)) > 0 && len(value(dto.Text)) > 0 {
+		return &EpcFieldError{Field: "InvoiceReference", Err: ErrEpcReferenceAndText}
 	}
 
-	if dto.Text != nil {
-		tmp = fmt.Sprintf("%s\n%s", tmp, *dto.Text)
-	} else {
-		tmp = tmp + "\n"
+	if err := validateEpcText("InvoiceReference", value(dto.InvoiceReference), 35, false); err != nil {
+		return err
 	}
 
-	if dto.Information != nil {
-		tmp = fmt.Sprintf("%s\n%s", tmp, *dto.Information)
-	} else {
-		tmp = tmp + "\n"
+	if err := validateEpcText("Text", value(dto.Text), 140, false); err != nil {
+		return err
 	}
 
-	return tmp
+	return validateEpcText("Information", value(dto.Information), 70, false)
 }
 
+// SetDefaults sets version 002 (BIC optional inside the EEA) with UTF-8.
 func (dto *EpcDto) SetDefaults() {
-	sTag := "BCD"
-	version := "001"
+	sTag := EpcServiceTag
+	version := EpcVersion2
 	charSet := "1"
-	identify := "SCT"
+	identify := EpcIdentification
 
 	dto.ServiceTag = &sTag
 	dto.Version = &version
@@ -83,7 +207,67 @@ func (dto *EpcDto) SetDefaults() {
 	dto.Identification = &identify
 }
 
-func (dto *EpcDto) SetAmount(amount float64) {
+// SetAmount sets the amount in EUR, only amounts between 0.01 and
+// 999999999.99 are allowed.
+func (dto *EpcDto) SetAmount(cur currency.Unit, amount float64) error {
+	if cur != currency.EUR {
+		return ErrCurrencyNotSupported
+	}
+
+	if amount < 0.01 || amount > 999999999.99 {
+		return &EpcFieldError{Field: "Amount", Err: ErrEpcAmount}
+	}
+
 	tmp := fmt.Sprintf("EUR%.2f", amount)
 	dto.Amount = &tmp
+	return nil
+}
+
+// SetRemittance sets a valid ISO 11649 creditor reference as structured
+// reference, any other reference is added to the unstructured text, as only
+// one of both is allowed.
+func (dto *EpcDto) SetRemittance(reference, text *string) {
+	ref := strings.TrimSpace(value(reference))
+
+	switch {
+	case ValidCreditorReference(ref):
+		tmp := strings.ToUpper(compact(ref))
+		dto.InvoiceReference = &tmp
+		dto.Text = nil
+	case len(ref) > 0 && len(value(text)) > 0:
+		tmp := ref + " " + *text
+		dto.InvoiceReference = nil
+		dto.Text = &tmp
+	case len(ref) > 0:
+		dto.InvoiceReference = nil
+		dto.Text = &ref
+	default:
+		dto.InvoiceReference = nil
+		dto.Text = text
+	}
+}
+
+func validateEpcText(field, text string, max int, required bool) error {
+	if required && len(text) == 0 {
+		return &EpcFieldError{Field: field, Err: ErrEpcRequired}
+	}
+
+	if len([]rune(text)) > max {
+		return &EpcFieldError{Field: field, Err: ErrEpcTooLong}
+	}
+
+	//the elements are separated by line feeds
+	if strings.ContainsAny(text, "\r\n") {
+		return &EpcFieldError{Field: field, Err: ErrEpcEncoding}
+	}
+
+	return nil
+}
+
+func value(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
 }
@@ -0,0 +1,135 @@
+package bank
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/currency"
+)
+
+func testEpc() *EpcDto {
+	name := "Red Cross of Belgium"
+	iban := "BE72 0000 0000 1616"
+	bic := "BPOTBEB1"
+
+	dto := &EpcDto{Name: &name, IBAN: &iban, BIC: &bic}
+	dto.SetDefaults()
+
+	return dto
+}
+
+func TestValidIBANAndBIC(t *testing.T) {
+	assert.True(t, ValidIBAN("AT61 1904 3002 3457 3201"))
+	assert.True(t, ValidIBAN("de89370400440532013000"))
+	assert.False(t, ValidIBAN("AT611904300234573202"))
+	assert.False(t, ValidIBAN("AT123456789023"))
+
+	assert.True(t, ValidBIC("BKAUATWW"))
+	assert.True(t, ValidBIC("COBADEFFXXX"))
+	assert.False(t, ValidBIC("RZTIAT3364"))
+	assert.False(t, ValidBIC("1234ATWW"))
+}
+
+func TestEpcGenerateCode(t *testing.T) {
+	dto := testEpc()
+	purpose := "CHAR"
+
+	assert.NoError(t, dto.SetAmount(currency.EUR, 1))
+	dto.Purpose = &purpose
+	dto.SetRemittance(nil, &[]string{"Urgency fund"}[0])
+
+	code, err := dto.GenerateCode()
+	assert.NoError(t, err)
+	assert.Equal(t, "BCD\n002\n1\nSCT\nBPOTBEB1\nRed Cross of Belgium\nBE72000000001616\nEUR1.00\nCHAR\n\nUrgency fund", code)
+}
+
+func TestEpcRemittance(t *testing.T) {
+	dto := testEpc()
+	reference := "rf18 5390 0754 7034"
+	text := "Invoice 1"
+
+	dto.SetRemittance(&reference, &text)
+	assert.Equal(t, "RF18539007547034", *dto.InvoiceReference)
+	assert.Nil(t, dto.Text)
+
+	reference = "12-30392"
+	dto.SetRemittance(&reference, &text)
+	assert.Nil(t, dto.InvoiceReference)
+	assert.Equal(t, "12-30392 Invoice 1", *dto.Text)
+
+	dto.InvoiceReference = &reference
+	assert.ErrorIs(t, dto.Validate(), ErrEpcReferenceAndText)
+}
+
+func TestEpcValidation(t *testing.T) {
+	dto := testEpc()
+	assert.NoError(t, dto.Validate())
+
+	version := EpcVersion1
+	dto.Version = &version
+	dto.BIC = nil
+	assert.ErrorIs(t, dto.Validate(), ErrEpcBICRequired)
+
+	version = EpcVersion2
+	assert.NoError(t, dto.Validate())
+
+	iban := "BE72000000001617"
+	dto.IBAN = &iban
+	err := dto.Validate()
+	assert.ErrorIs(t, err, ErrEpcIBAN)
+
+	var fieldErr *EpcFieldError
+	assert.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, "IBAN", fieldErr.Field)
+
+	dto = testEpc()
+	name := strings.Repeat("x", 71)
+	dto.Name = &name
+	assert.ErrorIs(t, dto.Validate(), ErrEpcTooLong)
+
+	dto = testEpc()
+	purpose := "char"
+	dto.Purpose = &purpose
+	assert.ErrorIs(t, dto.Validate(), ErrEpcPurpose)
+
+	dto = testEpc()
+	assert.ErrorIs(t, dto.SetAmount(currency.EUR, 0), ErrEpcAmount)
+	assert.ErrorIs(t, dto.SetAmount(currency.USD, 10), ErrCurrencyNotSupported)
+}
+
+func TestEpcCharacterSet(t *testing.T) {
+	dto := testEpc()
+	name := "Müller GmbH"
+	dto.Name = &name
+
+	charSet := "2"
+	dto.CharacterSet = &charSet
+	code, err := dto.GenerateCode()
+	assert.NoError(t, err)
+	assert.Contains(t, code, "M\xfcller GmbH")
+
+	name = "Ελλάδα"
+	_, err = dto.GenerateCode()
+	assert.ErrorIs(t, err, ErrEpcEncoding)
+
+	charSet = "6"
+	_, err = dto.GenerateCode()
+	assert.NoError(t, err)
+
+	charSet = "9"
+	assert.ErrorIs(t, dto.Validate(), ErrEpcCharacterSet)
+}
+
+func TestEpcPayloadLength(t *testing.T) {
+	dto := testEpc()
+	text := strings.Repeat("ü", 140)
+	information := strings.Repeat("ü", 70)
+	name := strings.Repeat("ü", 70)
+	dto.Text = &text
+	dto.Information = &information
+	dto.Name = &name
+
+	_, err := dto.GenerateCode()
+	assert.ErrorIs(t, err, ErrEpcPayloadTooLong)
+}
@@ -0,0 +1,283 @@
+package bank
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/currency"
+)
+
+// SwissReferenceType is the type of the payment reference of a QR-bill.
+//
+// SwissReferenceQRR - 27 digit QR reference, only with a QR-IBAN.
+//
+// SwissReferenceSCOR - ISO 11649 creditor reference (RF...), only with a
+// normal IBAN.
+//
+// SwissReferenceNON - without reference, only with a normal IBAN.
+type SwissReferenceType string
+
+const (
+	SwissReferenceQRR  SwissReferenceType = "QRR"
+	SwissReferenceSCOR SwissReferenceType = "SCOR"
+	SwissReferenceNON  SwissReferenceType = "NON"
+)
+
+var (
+	ErrSwissIBAN            = errors.New("the QR-bill requires a valid swiss or liechtenstein IBAN")
+	ErrSwissCurrency        = errors.New("the QR-bill only supports amounts in CHF and EUR")
+	ErrSwissAmount          = errors.New("the QR-bill amount has to be between 0.01 and 999999999.99")
+	ErrSwissReference       = errors.New("the QR-bill reference has to be a QR reference or a creditor reference (RF)")
+	ErrSwissQrIBANReference = errors.New("a QR-IBAN requires a QR reference, a normal IBAN must not use a QR reference")
+	ErrSwissCreditorAddress = errors.New("the QR-bill creditor requires a name, postal code, town and country code")
+	ErrSwissDebtorAddress   = errors.New("the QR-bill debtor requires a name, postal code, town and country code")
+	ErrSwissFieldTooLong    = errors.New("a QR-bill field exceeds its maximum length")
+	ErrSwissMessageTooLong  = errors.New("the QR-bill message exceeds 140 characters")
+	ErrSwissPayloadTooLong  = errors.New("the QR-bill payload exceeds 997 characters")
+)
+
+// carry table of the modulo 10 recursive check digit of QR references
+var qrReferenceCheckDigitMap = [10]int{0, 9, 4, 6, 8, 2, 7, 1, 3, 5}
+
+// SwissAddress is a structured address (address type S) of a QR-bill.
+type SwissAddress struct {
+	Name           string
+	Street         string
+	BuildingNumber string
+	PostalCode     string
+	Town           string
+	// Country has to be an ISO 3166-1 alpha-2 code
+	Country string
+}
+
+// SwissQrBillDto is the payload of the payment part of a swiss QR-bill
+// following the swiss implementation guidelines for the QR-bill (SPC 0200).
+type SwissQrBillDto struct {
+	IBAN     string
+	Creditor SwissAddress
+
+	// Amount is formatted with 2 decimals, empty for bills without amount
+	Amount   *string
+	Currency string
+
+	// Debtor is empty when the debtor is filled in by hand
+	Debtor *SwissAddress
+
+	ReferenceType SwissReferenceType
+	Reference     string
+
+	// Message is the unstructured message (max. 140 characters)
+	Message string
+}
+
+// SetIBAN sets the IBAN without spaces.
+func (dto *SwissQrBillDto) SetIBAN(iban string) error {
+	iban = compact(iban)
+
+	if !ValidSwissIBAN(iban) {
+		return ErrSwissIBAN
+	}
+
+	dto.IBAN = iban
+	return nil
+}
+
+// SetAmount sets the amount and the currency of the bill, only CHF and EUR are
+// allowed.
+func (dto *SwissQrBillDto) SetAmount(cur currency.Unit, amount float64) error {
+	if cur != currency.CHF && cur != currency.EUR {
+		return ErrSwissCurrency
+	}
+
+	if amount < 0.01 || amount > 999999999.99 {
+		return ErrSwissAmount
+	}
+
+	tmp := fmt.Sprintf("%.2f", amount)
+	dto.Amount = &tmp
+	dto.Currency = cur.String()
+	return nil
+}
+
+// SetReference detects the type of the reference, an empty reference is of
+// type NON. The IBAN has to be set before.
+func (dto *SwissQrBillDto) SetReference(reference string) error {
+	reference = compact(reference)
+
+	switch {
+	case len(reference) == 0:
+		dto.ReferenceType = SwissReferenceNON
+	case ValidQrReference(reference):
+		dto.ReferenceType = SwissReferenceQRR
+	case ValidCreditorReference(reference):
+		dto.ReferenceType = SwissReferenceSCOR
+	default:
+		return ErrSwissReference
+	}
+
+	if IsQrIBAN(dto.IBAN) != (dto.ReferenceType == SwissReferenceQRR) {
+		return ErrSwissQrIBANReference
+	}
+
+	dto.Reference = reference
+	return nil
+}
+
+// Validate checks the mandatory fields and the field lengths of the bill.
+func (dto *SwissQrBillDto) Validate() error {
+	if !ValidSwissIBAN(dto.IBAN) {
+		return ErrSwissIBAN
+	}
+
+	if dto.Currency != currency.CHF.String() && dto.Currency != currency.EUR.String() {
+		return ErrSwissCurrency
+	}
+
+	if IsQrIBAN(dto.IBAN) != (dto.ReferenceType == SwissReferenceQRR) {
+		return ErrSwissQrIBANReference
+	}
+
+	if !dto.Creditor.complete() {
+		return ErrSwissCreditorAddress
+	}
+	if err := dto.Creditor.validateLengths(); err != nil {
+		return err
+	}
+
+	if dto.Debtor != nil {
+		if !dto.Debtor.complete() {
+			return ErrSwissDebtorAddress
+		}
+		if err := dto.Debtor.validateLengths(); err != nil {
+			return err
+		}
+	}
+
+	if len([]rune(dto.Message)) > 140 {
+		return ErrSwissMessageTooLong
+	}
+
+	if len([]rune(dto.GenerateCode())) > 997 {
+		return ErrSwissPayloadTooLong
+	}
+
+	return nil
+}
+
+// GenerateCode creates the content of the swiss QR code.
+func (dto *SwissQrBillDto) GenerateCode() string {
+	lines := []string{"SPC", "0200", "1", dto.IBAN}
+
+	lines = append(lines, dto.Creditor.lines()...)
+
+	//ultimate creditor, reserved for future use
+	lines = append(lines, "", "", "", "", "", "", "")
+
+	amount := ""
+	if dto.Amount != nil {
+		amount = *dto.Amount
+	}
+	lines = append(lines, amount, dto.Currency)
+
+	if dto.Debtor != nil {
+		lines = append(lines, dto.Debtor.lines()...)
+	} else {
+		lines = append(lines, "", "", "", "", "", "", "")
+	}
+
+	referenceType := dto.ReferenceType
+	if len(referenceType) == 0 {
+		referenceType = SwissReferenceNON
+	}
+
+	lines = append(lines, string(referenceType), dto.Reference, dto.Message, "EPD")
+
+	return strings.Join(lines, "\n")
+}
+
+func (a *SwissAddress) lines() []string {
+	return []string{"S", a.Name, a.Street, a.BuildingNumber, a.PostalCode, a.Town, a.Country}
+}
+
+func (a *SwissAddress) complete() bool {
+	return len(a.Name) > 0 && len(a.PostalCode) > 0 && len(a.Town) > 0 && len(a.Country) == 2
+}
+
+func (a *SwissAddress) validateLengths() error {
+	limits := []struct {
+		value string
+		max   int
+	}{
+		{a.Name, 70},
+		{a.Street, 70},
+		{a.BuildingNumber, 16},
+		{a.PostalCode, 16},
+		{a.Town, 35},
+	}
+
+	for _, limit := range limits {
+		if len([]rune(limit.value)) > limit.max {
+			return ErrSwissFieldTooLong
+		}
+	}
+
+	return nil
+}
+
+// ValidSwissIBAN checks an IBAN (without spaces) of switzerland or
+// liechtenstein including its check digits.
+func ValidSwissIBAN(iban string) bool {
+	if len(iban) != 21 || (!strings.HasPrefix(iban, "CH") && !strings.HasPrefix(iban, "LI")) {
+		return false
+	}
+
+	return ValidIBAN(iban)
+}
+
+// IsQrIBAN returns true for a QR-IBAN, its institution identification is in
+// the range 30000 to 31999.
+func IsQrIBAN(iban string) bool {
+	iban = compact(iban)
+
+	if len(iban) != 21 {
+		return false
+	}
+
+	iid := iban[4:9]
+	return iid >= "30000" && iid <= "31999"
+}
+
+// ValidQrReference checks a 27 digit QR reference including its modulo 10
+// recursive check digit.
+func ValidQrReference(reference string) bool {
+	reference = compact(reference)
+
+	if len(reference) != 27 {
+		return false
+	}
+
+	carry := 0
+	for _, r := range reference[:26] {
+		if r < '0' || r > '9' {
+			return false
+		}
+
+		carry = qrReferenceCheckDigitMap[(carry+int(r-'0'))%10]
+	}
+
+	return int(reference[26]-'0') == (10-carry)%10
+}
+
+// FormatSwissReference groups a QR reference in blocks of 5 digits from the
+// right, a creditor reference and an IBAN in blocks of 4 characters.
+func FormatSwissReference(reference string) string {
+	reference = compact(reference)
+
+	if ValidQrReference(reference) {
+		//the first block contains the remaining 2 digits
+		return reference[:2] + " " + group(reference[2:], 5)
+	}
+
+	return group(reference, 4)
+}
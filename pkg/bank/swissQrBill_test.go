@@ -0,0 +1,107 @@
+package bank
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/currency"
+)
+
+func testSwissQrBill() *SwissQrBillDto {
+	return &SwissQrBillDto{
+		Creditor: SwissAddress{
+			Name:           "Robert Schneider AG",
+			Street:         "Rue du Lac",
+			BuildingNumber: "1268",
+			PostalCode:     "2501",
+			Town:           "Biel",
+			Country:        "CH",
+		},
+		Debtor: &SwissAddress{
+			Name:       "Pia-Maria Rutschmann-Schnyder",
+			Street:     "Grosse Marktgasse 28",
+			PostalCode: "9400",
+			Town:       "Rorschach",
+			Country:    "CH",
+		},
+		Message: "Instruction of 03.04.2019",
+	}
+}
+
+func TestValidSwissIBAN(t *testing.T) {
+	assert.True(t, ValidSwissIBAN("CH4431999123000889012"))
+	assert.True(t, ValidSwissIBAN("CH9300762011623852957"))
+	assert.False(t, ValidSwissIBAN("CH4431999123000889013"))
+	assert.False(t, ValidSwissIBAN("AT611904300234573201"))
+
+	assert.True(t, IsQrIBAN("CH44 3199 9123 0008 8901 2"))
+	assert.False(t, IsQrIBAN("CH9300762011623852957"))
+}
+
+func TestValidReferences(t *testing.T) {
+	assert.True(t, ValidQrReference("21 00000 00003 13947 14300 09017"))
+	assert.False(t, ValidQrReference("210000000003139471430009016"))
+	assert.False(t, ValidQrReference("21000000000313947143000901"))
+
+	assert.True(t, ValidCreditorReference("RF18 5390 0754 7034"))
+	assert.False(t, ValidCreditorReference("RF19539007547034"))
+	assert.False(t, ValidCreditorReference("XX18539007547034"))
+
+	assert.Equal(t, "21 00000 00003 13947 14300 09017", FormatSwissReference("210000000003139471430009017"))
+	assert.Equal(t, "RF18 5390 0754 7034", FormatSwissReference("RF18539007547034"))
+	assert.Equal(t, "CH44 3199 9123 0008 8901 2", FormatIBAN("CH4431999123000889012"))
+}
+
+func TestSwissQrBillQRR(t *testing.T) {
+	bill := testSwissQrBill()
+
+	assert.NoError(t, bill.SetIBAN("CH44 3199 9123 0008 8901 2"))
+	assert.NoError(t, bill.SetAmount(currency.CHF, 1949.75))
+	assert.NoError(t, bill.SetReference("21 00000 00003 13947 14300 09017"))
+	assert.NoError(t, bill.Validate())
+
+	lines := strings.Split(bill.GenerateCode(), "\n")
+	assert.Len(t, lines, 31)
+	assert.Equal(t, []string{"SPC", "0200", "1", "CH4431999123000889012", "S", "Robert Schneider AG"}, lines[:6])
+	assert.Equal(t, "1949.75", lines[18])
+	assert.Equal(t, "CHF", lines[19])
+	assert.Equal(t, "Pia-Maria Rutschmann-Schnyder", lines[21])
+	assert.Equal(t, "QRR", lines[27])
+	assert.Equal(t, "210000000003139471430009017", lines[28])
+	assert.Equal(t, "EPD", lines[30])
+}
+
+func TestSwissQrBillReferenceRules(t *testing.T) {
+	bill := testSwissQrBill()
+	assert.NoError(t, bill.SetIBAN("CH9300762011623852957"))
+
+	assert.NoError(t, bill.SetReference("RF18539007547034"))
+	assert.Equal(t, SwissReferenceSCOR, bill.ReferenceType)
+
+	assert.NoError(t, bill.SetReference(""))
+	assert.Equal(t, SwissReferenceNON, bill.ReferenceType)
+
+	assert.ErrorIs(t, bill.SetReference("210000000003139471430009017"), ErrSwissQrIBANReference)
+	assert.ErrorIs(t, bill.SetReference("Invoice 123"), ErrSwissReference)
+
+	assert.NoError(t, bill.SetIBAN("CH4431999123000889012"))
+	assert.ErrorIs(t, bill.SetReference(""), ErrSwissQrIBANReference)
+}
+
+func TestSwissQrBillValidation(t *testing.T) {
+	bill := testSwissQrBill()
+	assert.NoError(t, bill.SetIBAN("CH9300762011623852957"))
+	assert.NoError(t, bill.SetReference(""))
+
+	assert.ErrorIs(t, bill.SetAmount(currency.USD, 10), ErrSwissCurrency)
+	assert.ErrorIs(t, bill.SetAmount(currency.CHF, 0), ErrSwissAmount)
+	assert.NoError(t, bill.SetAmount(currency.EUR, 10))
+
+	bill.Creditor.Town = ""
+	assert.ErrorIs(t, bill.Validate(), ErrSwissCreditorAddress)
+
+	bill.Creditor.Town = "Biel"
+	bill.Message = strings.Repeat("x", 141)
+	assert.ErrorIs(t, bill.Validate(), ErrSwissMessageTooLong)
+}
@@ -0,0 +1,14 @@
+package bank
+
+// QrCodeType selects the payment qr code of a document.
+//
+// QrCodeTypeEPC - EPC QR code (SEPA credit transfer) beside the bank details.
+//
+// QrCodeTypeSwissQrBill - swiss QR-bill with receipt and payment part at the
+// bottom of the last page.
+type QrCodeType string
+
+const (
+	QrCodeTypeEPC         QrCodeType = "EPC"
+	QrCodeTypeSwissQrBill QrCodeType = "SWISS_QR_BILL"
+)
@@ -0,0 +1,87 @@
+package bank
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+var (
+	ibanRegex = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]{11,30}$`)
+	bicRegex  = regexp.MustCompile(`^[A-Z]{4}[A-Z]{2}[A-Z0-9]{2}([A-Z0-9]{3})?$`)
+)
+
+// ValidIBAN checks the format and the check digits (ISO 13616) of an IBAN,
+// spaces are ignored.
+func ValidIBAN(iban string) bool {
+	iban = strings.ToUpper(compact(iban))
+
+	if !ibanRegex.MatchString(iban) {
+		return false
+	}
+
+	return mod97(iban[4:]+iban[:4]) == 1
+}
+
+// ValidBIC checks the format of a BIC (ISO 9362) with 8 or 11 characters.
+func ValidBIC(bic string) bool {
+	return bicRegex.MatchString(strings.ToUpper(compact(bic)))
+}
+
+// ValidCreditorReference checks an ISO 11649 creditor reference (RF...).
+func ValidCreditorReference(reference string) bool {
+	reference = strings.ToUpper(compact(reference))
+
+	if len(reference) < 5 || len(reference) > 25 || !strings.HasPrefix(reference, "RF") {
+		return false
+	}
+
+	return mod97(reference[4:]+reference[:4]) == 1
+}
+
+// FormatIBAN groups the IBAN in blocks of 4 characters.
+func FormatIBAN(iban string) string {
+	return group(compact(iban), 4)
+}
+
+func group(value string, size int) string {
+	var sb strings.Builder
+
+	for i, r := range value {
+		if i > 0 && i%size == 0 {
+			sb.WriteString(" ")
+		}
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
+
+// mod97 calculates the ISO 7064 mod 97-10 of the value, letters are replaced
+// by numbers (A = 10, ..., Z = 35). Returns -1 for invalid characters.
+func mod97(value string) int {
+	var digits strings.Builder
+
+	for _, r := range strings.ToUpper(value) {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r >= 'A' && r <= 'Z':
+			digits.WriteString(fmt.Sprint(r - 'A' + 10))
+		default:
+			return -1
+		}
+	}
+
+	n, ok := new(big.Int).SetString(digits.String(), 10)
+	if !ok {
+		return -1
+	}
+
+	return int(new(big.Int).Mod(n, big.NewInt(97)).Int64())
+}
+
+func compact(value string) string {
+	return strings.ReplaceAll(strings.TrimSpace(value), " ", "")
+}
@@ -0,0 +1,291 @@
+package calculation
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// RoundingMode determines where tax amounts are rounded to the currency
+// precision.
+//
+// RoundingPerLine - the tax of every line is rounded, the tax of a rate is
+// the sum of its lines.
+//
+// RoundingPerDocument - the tax of a rate is calculated and rounded from the
+// sum of its line nets (as required by EN 16931), the rounding difference is
+// distributed to the lines so they still add up.
+type RoundingMode string
+
+const (
+	RoundingPerLine     RoundingMode = "LINE"
+	RoundingPerDocument RoundingMode = "DOCUMENT"
+)
+
+// defaultPlaces is the currency precision amounts are rounded to, when not
+// set by WithPlaces.
+const defaultPlaces = 2
+
+type config struct {
+	places int32
+}
+
+// Option configures a calculation.
+type Option func(*config) *config
+
+// WithPlaces sets the currency precision (ISO 4217 minor units) amounts are
+// rounded to, e.g. 0 for JPY.
+func WithPlaces(places int32) Option {
+	return func(c *config) *config {
+		c.places = places
+		return c
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{places: defaultPlaces}
+
+	for _, opt := range opts {
+		c = opt(c)
+	}
+
+	return c
+}
+
+var hundred = decimal.NewFromInt(100)
+
+// Line is the input of a single invoice line. Quantity defaults to 1 when
+// zero, a percentage discount is applied before a fixed discount.
+type Line struct {
+	Quantity  decimal.Decimal
+	UnitPrice decimal.Decimal
+	// TaxRate in percent, e.g. 20 for 20%
+	TaxRate decimal.Decimal
+
+	DiscountPercentage decimal.Decimal
+	DiscountFixed      decimal.Decimal
+}
+
+// LineResult holds the calculated amounts of a line, all rounded to the
+// currency precision.
+type LineResult struct {
+	// Total is quantity * unit price before discounts
+	Total    decimal.Decimal
+	Discount decimal.Decimal
+	Net      decimal.Decimal
+	Tax      decimal.Decimal
+	Gross    decimal.Decimal
+}
+
+// TaxGroup is the sum of all lines with the same tax rate.
+type TaxGroup struct {
+	Rate  decimal.Decimal
+	Net   decimal.Decimal
+	Tax   decimal.Decimal
+	Gross decimal.Decimal
+}
+
+// Result is the calculated invoice. Net, Tax and Gross always equal the sums
+// of the tax groups and of the lines.
+type Result struct {
+	Lines []LineResult
+	// Taxes are sorted by rate
+	Taxes []TaxGroup
+
+	Net   decimal.Decimal
+	Tax   decimal.Decimal
+	Gross decimal.Decimal
+}
+
+// Calculate computes net, tax and gross of all lines and the invoice with
+// decimal arithmetic. Amounts are rounded half away from zero.
+func Calculate(lines []Line, mode RoundingMode, opts ...Option) (*Result, error) {
+	places := newConfig(opts).places
+
+	if mode != RoundingPerLine && mode != RoundingPerDocument {
+		return nil, fmt.Errorf("calculation: unknown rounding mode %q", mode)
+	}
+
+	res := &Result{
+		Lines: make([]LineResult, len(lines)),
+	}
+
+	// exact (unrounded) tax of every line
+	exactTaxes := make([]decimal.Decimal, len(lines))
+
+	groups := make(map[string]*TaxGroup)
+	groupLines := make(map[string][]int)
+
+	for i, line := range lines {
+		quantity := line.Quantity
+		if quantity.IsZero() {
+			quantity = decimal.NewFromInt(1)
+		}
+
+		total := quantity.Mul(line.UnitPrice).Round(places)
+		discount := total.Mul(line.DiscountPercentage).Div(hundred).Round(places).Add(line.DiscountFixed.Round(places))
+		net := total.Sub(discount)
+
+		exactTaxes[i] = net.Mul(line.TaxRate).Div(hundred)
+
+		res.Lines[i] = LineResult{
+			Total:    total,
+			Discount: discount,
+			Net:      net,
+			Tax:      exactTaxes[i].Round(places),
+		}
+
+		// decimals are grouped by their normalized string, 20 and 20.0 are
+		// the same rate
+		key := line.TaxRate.String()
+		group, ok := groups[key]
+		if !ok {
+			group = &TaxGroup{Rate: line.TaxRate}
+			groups[key] = group
+		}
+		group.Net = group.Net.Add(net)
+		groupLines[key] = append(groupLines[key], i)
+	}
+
+	for key, group := range groups {
+		if mode == RoundingPerDocument {
+			group.Tax = group.Net.Mul(group.Rate).Div(hundred).Round(places)
+			distributeRoundingDifference(res.Lines, exactTaxes, groupLines[key], group.Tax, places)
+		} else {
+			for _, i := range groupLines[key] {
+				group.Tax = group.Tax.Add(res.Lines[i].Tax)
+			}
+		}
+
+		group.Gross = group.Net.Add(group.Tax)
+
+		res.Taxes = append(res.Taxes, *group)
+		res.Net = res.Net.Add(group.Net)
+		res.Tax = res.Tax.Add(group.Tax)
+	}
+
+	sort.Slice(res.Taxes, func(i, j int) bool { return res.Taxes[i].Rate.LessThan(res.Taxes[j].Rate) })
+
+	res.Gross = res.Net.Add(res.Tax)
+
+	for i := range res.Lines {
+		res.Lines[i].Gross = res.Lines[i].Net.Add(res.Lines[i].Tax)
+	}
+
+	return res, nil
+}
+
+// distributeRoundingDifference corrects the rounded line taxes of one tax group
+// so they add up to the group tax. The difference is distributed in steps of
+// the smallest currency unit to the lines with the largest rounding error.
+func distributeRoundingDifference(lines []LineResult, exactTaxes []decimal.Decimal, indices []int, groupTax decimal.Decimal, places int32) {
+	sum := decimal.Zero
+	for _, i := range indices {
+		sum = sum.Add(lines[i].Tax)
+	}
+
+	diff := groupTax.Sub(sum)
+	if diff.IsZero() {
+		return
+	}
+
+	step := decimal.New(1, -places)
+	if diff.IsNegative() {
+		step = step.Neg()
+	}
+
+	// lines which lost the most by rounding get the difference first
+	sorted := append([]int(nil), indices...)
+	sort.SliceStable(sorted, func(a, b int) bool {
+		errA := exactTaxes[sorted[a]].Sub(lines[sorted[a]].Tax).Mul(step)
+		errB := exactTaxes[sorted[b]].Sub(lines[sorted[b]].Tax).Mul(step)
+		return errA.GreaterThan(errB)
+	})
+
+	for n := 0; !diff.IsZero(); n++ {
+		i := sorted[n%len(sorted)]
+		lines[i].Tax = lines[i].Tax.Add(step)
+		diff = diff.Sub(step)
+	}
+}
+
+// Discount calculates a document level discount of the net total. The
+// percentage is applied before the fixed amount, the discount must not exceed
+// a positive net total.
+func Discount(net, percentage, fixed decimal.Decimal, opts ...Option) (decimal.Decimal, error) {
+	places := newConfig(opts).places
+
+	if percentage.IsNegative() || fixed.IsNegative() {
+		return decimal.Zero, fmt.Errorf("calculation: discount must not be negative")
+	}
+
+	//e.g. a credit note without discount has a negative net total
+	if percentage.IsZero() && fixed.IsZero() {
+		return decimal.Zero, nil
+	}
+
+	discount := net.Mul(percentage).Div(hundred).Round(places).Add(fixed.Round(places))
+	if net.IsPositive() && discount.GreaterThan(net) {
+		return decimal.Zero, fmt.Errorf("calculation: discount %s exceeds the net total %s", discount, net)
+	}
+
+	return discount, nil
+}
+
+// Distribute splits an amount proportionally to the given weights (e.g. the
+// nets of the tax groups). The shares are rounded toward zero and always add up
+// to the amount, the remainder is given to the shares with the largest
+// rounding error, which works for weights of mixed signs as well.
+func Distribute(amount decimal.Decimal, weights []decimal.Decimal, opts ...Option) []decimal.Decimal {
+	places := newConfig(opts).places
+	shares := make([]decimal.Decimal, len(weights))
+
+	total := decimal.Zero
+	for _, weight := range weights {
+		total = total.Add(weight)
+	}
+
+	if total.IsZero() {
+		return shares
+	}
+
+	exact := make([]decimal.Decimal, len(weights))
+	sum := decimal.Zero
+	for i, weight := range weights {
+		exact[i] = amount.Mul(weight).Div(total)
+		shares[i] = exact[i].RoundDown(places)
+		sum = sum.Add(shares[i])
+	}
+
+	//one minor unit in the direction of the missing remainder
+	step := decimal.New(1, -places)
+	if amount.LessThan(sum) {
+		step = step.Neg()
+	}
+
+	//the remainders in the direction of the step, largest first
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return exact[order[a]].Sub(shares[order[a]]).Mul(step).GreaterThan(exact[order[b]].Sub(shares[order[b]]).Mul(step))
+	})
+
+	//the truncated remainders are less than one unit per share
+	for n := 0; n < len(order) && !sum.Equal(amount); n++ {
+		i := order[n]
+		shares[i] = shares[i].Add(step)
+		sum = sum.Add(step)
+	}
+
+	return shares
+}
+
+// Tax returns the tax of a net amount rounded to the currency precision.
+func Tax(net, rate decimal.Decimal, opts ...Option) decimal.Decimal {
+	places := newConfig(opts).places
+
+	return net.Mul(rate).Div(hundred).Round(places)
+}
@@ -0,0 +1,161 @@
+package calculation
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func d(value string) decimal.Decimal {
+	return decimal.RequireFromString(value)
+}
+
+func assertDecimal(t *testing.T, expected string, actual decimal.Decimal) {
+	t.Helper()
+	assert.True(t, d(expected).Equal(actual), "expected %s, got %s", expected, actual)
+}
+
+// three lines of 0.33 with 19% have a tax of 0.0627 each
+func roundingLines() []Line {
+	line := Line{Quantity: d("1"), UnitPrice: d("0.33"), TaxRate: d("19")}
+	return []Line{line, line, line}
+}
+
+func TestCalculatePerLine(t *testing.T) {
+	res, err := Calculate(roundingLines(), RoundingPerLine)
+	assert.NoError(t, err)
+
+	for _, line := range res.Lines {
+		assertDecimal(t, "0.06", line.Tax)
+		assertDecimal(t, "0.39", line.Gross)
+	}
+
+	assertDecimal(t, "0.99", res.Net)
+	assertDecimal(t, "0.18", res.Tax)
+	assertDecimal(t, "1.17", res.Gross)
+}
+
+func TestCalculatePerDocument(t *testing.T) {
+	res, err := Calculate(roundingLines(), RoundingPerDocument)
+	assert.NoError(t, err)
+
+	// 0.99 * 19% = 0.1881
+	assertDecimal(t, "0.99", res.Net)
+	assertDecimal(t, "0.19", res.Tax)
+	assertDecimal(t, "1.18", res.Gross)
+
+	// the rounding difference is added to one line
+	lineTax := decimal.Zero
+	for _, line := range res.Lines {
+		lineTax = lineTax.Add(line.Tax)
+	}
+	assertDecimal(t, "0.19", lineTax)
+	assertDecimal(t, "0.07", res.Lines[0].Tax)
+	assertDecimal(t, "0.06", res.Lines[1].Tax)
+}
+
+func TestCalculateDiscountsAndRates(t *testing.T) {
+	res, err := Calculate([]Line{
+		{Quantity: d("3"), UnitPrice: d("19.99"), TaxRate: d("20"), DiscountPercentage: d("10")},
+		{Quantity: d("2"), UnitPrice: d("10"), TaxRate: d("10"), DiscountFixed: d("5")},
+		{UnitPrice: d("100"), TaxRate: d("20.0")},
+	}, RoundingPerDocument)
+	assert.NoError(t, err)
+
+	// 59.97 - 6.00 (10%)
+	assertDecimal(t, "59.97", res.Lines[0].Total)
+	assertDecimal(t, "6.00", res.Lines[0].Discount)
+	assertDecimal(t, "53.97", res.Lines[0].Net)
+
+	assertDecimal(t, "15", res.Lines[1].Net)
+	assertDecimal(t, "1.50", res.Lines[1].Tax)
+
+	// quantity defaults to 1
+	assertDecimal(t, "100", res.Lines[2].Net)
+
+	assert.Len(t, res.Taxes, 2)
+	assertDecimal(t, "10", res.Taxes[0].Rate)
+	assertDecimal(t, "15", res.Taxes[0].Net)
+	assertDecimal(t, "20", res.Taxes[1].Rate)
+	assertDecimal(t, "153.97", res.Taxes[1].Net)
+	assertDecimal(t, "30.79", res.Taxes[1].Tax)
+
+	assertDecimal(t, "168.97", res.Net)
+	assertDecimal(t, "32.29", res.Tax)
+	assertDecimal(t, "201.26", res.Gross)
+}
+
+func TestCalculateUnknownMode(t *testing.T) {
+	_, err := Calculate(roundingLines(), "WHATEVER")
+	assert.Error(t, err)
+}
+
+func TestDiscount(t *testing.T) {
+	discount, err := Discount(d("200"), d("10"), d("5.5"))
+	assert.NoError(t, err)
+	assertDecimal(t, "25.5", discount)
+
+	_, err = Discount(d("20"), d("0"), d("20.01"))
+	assert.Error(t, err)
+
+	_, err = Discount(d("20"), d("-1"), d("0"))
+	assert.Error(t, err)
+
+	// no discount of a negative net total
+	discount, err = Discount(d("-100"), d("0"), d("0"))
+	assert.NoError(t, err)
+	assertDecimal(t, "0", discount)
+}
+
+func TestDistribute(t *testing.T) {
+	shares := Distribute(d("10"), []decimal.Decimal{d("1"), d("1"), d("1")})
+	assertDecimal(t, "3.34", shares[0])
+	assertDecimal(t, "3.33", shares[1])
+	assertDecimal(t, "3.33", shares[2])
+
+	// 25.5 of 153.97 and 15
+	shares = Distribute(d("25.5"), []decimal.Decimal{d("153.97"), d("15")})
+	assertDecimal(t, "23.24", shares[0])
+	assertDecimal(t, "2.26", shares[1])
+
+	shares = Distribute(d("10"), []decimal.Decimal{d("0")})
+	assertDecimal(t, "0", shares[0])
+}
+
+func TestDistributeMixedSigns(t *testing.T) {
+	// 0.02, -0.005 and -0.005 are truncated to 0.02, 0 and 0
+	shares := Distribute(d("0.01"), []decimal.Decimal{d("200"), d("-50"), d("-50")})
+	assertDecimal(t, "0.02", shares[0])
+	assertDecimal(t, "-0.01", shares[1])
+	assertDecimal(t, "0", shares[2])
+
+	shares = Distribute(d("-10"), []decimal.Decimal{d("3"), d("-1"), d("1")})
+	assertDecimal(t, "-10", shares[0].Add(shares[1]).Add(shares[2]))
+	assertDecimal(t, "-10", shares[0])
+	assertDecimal(t, "3.33", shares[1])
+	assertDecimal(t, "-3.33", shares[2])
+
+	shares = Distribute(d("1"), []decimal.Decimal{d("1"), d("-1"), d("1"), d("-1"), d("1")})
+	assertDecimal(t, "1", shares[0].Add(shares[1]).Add(shares[2]).Add(shares[3]).Add(shares[4]))
+}
+
+func TestCalculateWithPlaces(t *testing.T) {
+	res, err := Calculate([]Line{
+		{Quantity: d("3"), UnitPrice: d("333"), TaxRate: d("10")},
+		{UnitPrice: d("15"), TaxRate: d("10")},
+	}, RoundingPerDocument, WithPlaces(0))
+	assert.NoError(t, err)
+
+	// 1014 * 10% = 101.4
+	assertDecimal(t, "1014", res.Net)
+	assertDecimal(t, "101", res.Tax)
+	assertDecimal(t, "100", res.Lines[0].Tax)
+	assertDecimal(t, "1", res.Lines[1].Tax)
+
+	assertDecimal(t, "34", Tax(d("335"), d("10"), WithPlaces(0)))
+
+	shares := Distribute(d("10"), []decimal.Decimal{d("1"), d("1"), d("1")}, WithPlaces(0))
+	assertDecimal(t, "4", shares[0])
+	assertDecimal(t, "3", shares[2])
+}
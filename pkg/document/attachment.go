@@ -0,0 +1,78 @@
+package document
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+)
+
+// AFRelationship describes how an associated file relates to the pdf (see
+// ISO 32000-2 14.13).
+type AFRelationship string
+
+const (
+	AFRelationshipSource      AFRelationship = "Source"
+	AFRelationshipData        AFRelationship = "Data"
+	AFRelationshipAlternative AFRelationship = "Alternative"
+	AFRelationshipSupplement  AFRelationship = "Supplement"
+	AFRelationshipUnspecified AFRelationship = "Unspecified"
+)
+
+// Attachment is a file embedded into the pdf. Other than gofpdf attachments
+// it is written as associated file of the document (catalog /AF), which is
+// required for hybrid invoices like Factur-X and ZUGFeRD.
+type Attachment struct {
+	Content     []byte
+	Filename    string
+	Description string
+	MimeType    string
+
+	Relationship AFRelationship
+}
+
+// AddAttachment embeds the attachment as associated file of the document.
+func (d *Doc) AddAttachment(a Attachment) {
+	d.attachments = append(d.attachments, a)
+}
+
+// AddXmpDescription adds a rdf:Description block to the XMP metadata of the
+// document, e.g. the metadata of an embedded invoice.
+func (d *Doc) AddXmpDescription(description string) {
+	d.xmpDescriptions = append(d.xmpDescriptions, description)
+}
+
+// writeAttachments adds the embedded file streams and file specifications to
+// the update and returns the object numbers of the file specifications.
+func (d *Doc) writeAttachments(u *pdfUpdate, modDate time.Time) []int {
+	fileSpecs := make([]int, 0, len(d.attachments))
+
+	for _, a := range d.attachments {
+		dict := fmt.Sprintf("/Type /EmbeddedFile /Params << /ModDate %s /Size %d >>",
+			pdfTextString(pdfDate(modDate)), len(a.Content))
+		if len(a.MimeType) > 0 {
+			dict = fmt.Sprintf("%s /Subtype %s", dict, pdfName(a.MimeType))
+		}
+		stream := u.add(pdfStream(dict, a.Content, true))
+
+		relationship := a.Relationship
+		if len(relationship) == 0 {
+			relationship = AFRelationshipUnspecified
+		}
+
+		var spec bytes.Buffer
+		fmt.Fprintf(&spec, "<< /Type /Filespec /F %s /UF %s", pdfTextString(a.Filename), pdfTextString(a.Filename))
+		if len(a.Description) > 0 {
+			fmt.Fprintf(&spec, " /Desc %s", pdfTextString(a.Description))
+		}
+		fmt.Fprintf(&spec, " /AFRelationship %s /EF << /F %d 0 R /UF %d 0 R >> >>", pdfName(string(relationship)), stream, stream)
+
+		fileSpecs = append(fileSpecs, u.add(spec.Bytes()))
+	}
+
+	return fileSpecs
+}
+
+// pdfDate formats the time as pdf date string.
+func pdfDate(tm time.Time) string {
+	return "D:" + tm.UTC().Format("20060102150405") + "+00'00'"
+}
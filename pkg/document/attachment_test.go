@@ -0,0 +1,53 @@
+package document
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutputWithAttachment(t *testing.T) {
+	doc := NewA4()
+	doc.SetTitle("Invoice 1", true)
+	doc.MCell(0, 5, "attachment test", "", "L", false)
+
+	doc.AddAttachment(Attachment{
+		Content:      []byte("<xml/>"),
+		Filename:     "factur-x.xml",
+		MimeType:     "text/xml",
+		Relationship: AFRelationshipAlternative,
+	})
+	doc.AddXmpDescription("<rdf:Description rdf:about=\"\" xmlns:fx=\"urn:test\"><fx:Test>1</fx:Test></rdf:Description>\n")
+
+	var buf bytes.Buffer
+	err := doc.Output(&buf)
+	assert.NoError(t, err)
+
+	out := buf.Bytes()
+
+	// incremental update has its own trailer pointing to the previous one
+	assert.Equal(t, 2, bytes.Count(out, []byte("%%EOF")))
+	assert.Contains(t, string(out), "/Prev ")
+
+	u, err := newPdfUpdate(out)
+	assert.NoError(t, err)
+
+	// the update section replaces the catalog
+	catalog := out[bytes.LastIndex(out, []byte("/Type /Catalog")):]
+	assert.Contains(t, string(catalog), "/Metadata ")
+	assert.Contains(t, string(catalog), "/AF [")
+	assert.Contains(t, string(catalog), "/EmbeddedFiles << /Names [ (factur-x.xml)")
+	assert.Contains(t, string(out), "/AFRelationship /Alternative")
+	assert.Contains(t, string(out), "/Subtype /text#2Fxml")
+	assert.Contains(t, string(out), "<dc:title><rdf:Alt><rdf:li xml:lang=\"x-default\">Invoice 1</rdf:li></rdf:Alt></dc:title>")
+	assert.Contains(t, string(out), "<fx:Test>1</fx:Test>")
+	assert.Greater(t, u.size, 0)
+
+}
+
+func TestPdfTextString(t *testing.T) {
+	assert.Equal(t, "(a\\(b\\)c)", pdfTextString("a(b)c"))
+	assert.Equal(t, "<FEFF00FC>", pdfTextString("ü"))
+	assert.Equal(t, "/text#2Fxml", pdfName("text/xml"))
+}
@@ -0,0 +1,446 @@
+package document
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/bidi"
+)
+
+// Direction is the base direction of a document. Right-to-left documents
+// mirror the alignment of texts and the order of table columns, texts without
+// strong characters (e.g. amounts) are written right-to-left.
+type Direction string
+
+const (
+	DirectionLTR Direction = "LTR"
+	DirectionRTL Direction = "RTL"
+)
+
+// WithDirection sets the base direction of the document.
+func WithDirection(dir Direction) Option {
+	return func(d *Doc) *Doc {
+		d.direction = dir
+		return d
+	}
+}
+
+// SetDirection sets the base direction of the following texts and tables.
+func (d *Doc) SetDirection(dir Direction) {
+	d.direction = dir
+}
+
+// GetDirection returns the base direction, LTR if unset.
+func (d *Doc) GetDirection() Direction {
+	if d.direction == DirectionRTL {
+		return DirectionRTL
+	}
+
+	return DirectionLTR
+}
+
+// IsRTL reports whether the base direction is right-to-left.
+func (d *Doc) IsRTL() bool {
+	return d.direction == DirectionRTL
+}
+
+// mirrorAlign swaps left and right of a gofpdf alignment string for
+// right-to-left documents, a missing horizontal alignment (left) becomes right.
+func (d *Doc) mirrorAlign(alignStr string) string {
+	if !d.IsRTL() {
+		return alignStr
+	}
+
+	if !strings.ContainsAny(alignStr, "LCRJ") {
+		return "R" + alignStr
+	}
+
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case 'L':
+			return 'R'
+		case 'R':
+			return 'L'
+		}
+		return r
+	}, alignStr)
+}
+
+// needsBidi reports whether the text has to be reordered: it contains
+// right-to-left characters or the document is right-to-left. Core fonts can't
+// display right-to-left scripts.
+func (d *Doc) needsBidi(txt string) bool {
+	if !d.unicode {
+		return false
+	}
+
+	return d.IsRTL() || containsRTL(txt)
+}
+
+// visualLine reorders one line of text for display, see reorderLine.
+func (d *Doc) visualLine(line string) string {
+	if !d.needsBidi(line) {
+		return line
+	}
+
+	return reorderLine(line, isRTLParagraph(line, d.IsRTL()))
+}
+
+// bidiMultiCell is MultiCell for texts which need to be reordered: the text is
+// split into lines in logical order, afterwards every line is reordered.
+// Borders are drawn around the whole cell, justified lines are aligned by the
+// direction of their paragraph.
+func (d *Doc) bidiMultiCell(w, h float64, txtStr, borderStr, alignStr string, fill bool) {
+	if w == 0 {
+		pw, _ := d.GetPageSize()
+		_, _, r, _ := d.GetMargins()
+		w = pw - r - d.GetX()
+	}
+
+	x, y := d.GetXY()
+	wMax := w - 2*d.GetCellMargin()
+
+	for _, paragraph := range strings.Split(txtStr, "\n") {
+		rtl := isRTLParagraph(paragraph, d.IsRTL())
+
+		align := alignStr
+		if strings.Contains(align, "J") {
+			align = strings.Replace(align, "J", "L", 1)
+			if rtl {
+				align = strings.Replace(align, "L", "R", 1)
+			}
+		}
+
+		lines := d.splitText(paragraph, wMax)
+		if len(lines) == 0 {
+			lines = []string{""}
+		}
+
+		for _, line := range lines {
+			d.SetX(x)
+			d.CellFormat(w, h, reorderLine(line, rtl), "", 2, align, fill, 0, "")
+		}
+	}
+
+	if len(borderStr) > 0 {
+		d.Rect(x, y, w, d.GetY()-y, "D")
+	}
+
+	l, _, _, _ := d.GetMargins()
+	d.SetX(l)
+}
+
+// multiCell renders texts which are already prepared by prepareText.
+func (d *Doc) multiCell(w, h float64, txtStr, borderStr, alignStr string, fill bool) {
+	if d.needsBidi(txtStr) {
+		d.bidiMultiCell(w, h, txtStr, borderStr, alignStr, fill)
+		return
+	}
+
+	d.MultiCell(w, h, txtStr, borderStr, alignStr, fill)
+}
+
+// prepareText translates a text for gofpdf, Arabic letters are replaced by
+// their contextual forms.
+func (d *Doc) prepareText(txt string) string {
+	if d.unicode {
+		return shapeArabic(txt)
+	}
+
+	return d.trUTF8(txt)
+}
+
+func containsRTL(txt string) bool {
+	for _, r := range txt {
+		switch bidiClass(r) {
+		case bidi.R, bidi.AL, bidi.AN:
+			return true
+		}
+	}
+
+	return false
+}
+
+// isRTLParagraph determines the direction of a paragraph by its first strong
+// character (rules P2 and P3), def is used without strong characters.
+func isRTLParagraph(txt string, def bool) bool {
+	for _, r := range txt {
+		switch bidiClass(r) {
+		case bidi.L:
+			return false
+		case bidi.R, bidi.AL:
+			return true
+		}
+	}
+
+	return def
+}
+
+func bidiClass(r rune) bidi.Class {
+	p, _ := bidi.LookupRune(r)
+	return p.Class()
+}
+
+// mirrored characters are replaced in right-to-left runs (rule L4)
+var mirroredRunes = map[rune]rune{
+	'(': ')', ')': '(',
+	'[': ']', ']': '[',
+	'{': '}', '}': '{',
+	'<': '>', '>': '<',
+	'«': '»', '»': '«',
+	'‹': '›', '›': '‹',
+	'≤': '≥', '≥': '≤',
+}
+
+// reorderLine applies the unicode bidirectional algorithm (UAX #9) to a single
+// line and returns the characters in visual order from left to right.
+// Explicit embeddings, overrides and isolates are not supported and treated as
+// neutrals, bracket pairs are resolved like other neutrals.
+func reorderLine(line string, rtl bool) string {
+	runes := []rune(line)
+	if len(runes) == 0 {
+		return line
+	}
+
+	levels := resolveLevels(runes, rtl)
+
+	// combining marks stay behind their base character
+	type cluster struct {
+		runes []rune
+		level int
+	}
+
+	clusters := make([]cluster, 0, len(runes))
+	for i, r := range runes {
+		if i > 0 && bidiClass(r) == bidi.NSM {
+			clusters[len(clusters)-1].runes = append(clusters[len(clusters)-1].runes, r)
+			continue
+		}
+
+		if levels[i]%2 == 1 {
+			if m, ok := mirroredRunes[r]; ok {
+				r = m
+			}
+		}
+
+		clusters = append(clusters, cluster{runes: []rune{r}, level: levels[i]})
+	}
+
+	// L2: reverse every sequence at the level or higher, from the highest
+	// level down to the lowest odd level
+	highest, lowestOdd := 0, 2
+	for _, c := range clusters {
+		if c.level > highest {
+			highest = c.level
+		}
+		if c.level%2 == 1 && c.level < lowestOdd {
+			lowestOdd = c.level
+		}
+	}
+
+	for level := highest; level >= lowestOdd; level-- {
+		for i := 0; i < len(clusters); {
+			if clusters[i].level < level {
+				i++
+				continue
+			}
+
+			j := i
+			for j < len(clusters) && clusters[j].level >= level {
+				j++
+			}
+
+			for a, b := i, j-1; a < b; a, b = a+1, b-1 {
+				clusters[a], clusters[b] = clusters[b], clusters[a]
+			}
+
+			i = j
+		}
+	}
+
+	var sb strings.Builder
+	for _, c := range clusters {
+		sb.WriteString(string(c.runes))
+	}
+
+	return sb.String()
+}
+
+// resolveLevels resolves the embedding levels of the characters of a line
+// (rules W1-W7, N1-N2, I1-I2 and L1).
+func resolveLevels(runes []rune, rtl bool) []int {
+	n := len(runes)
+	base, sos := 0, bidi.L
+	if rtl {
+		base, sos = 1, bidi.R
+	}
+
+	original := make([]bidi.Class, n)
+	cls := make([]bidi.Class, n)
+	for i, r := range runes {
+		original[i] = bidiClass(r)
+
+		switch original[i] {
+		case bidi.L, bidi.R, bidi.AL, bidi.EN, bidi.ES, bidi.ET, bidi.AN, bidi.CS, bidi.NSM, bidi.B, bidi.S, bidi.WS:
+			cls[i] = original[i]
+		default:
+			cls[i] = bidi.ON
+		}
+	}
+
+	// W1: non-spacing marks get the class of the previous character
+	for i := range cls {
+		if cls[i] == bidi.NSM {
+			if i == 0 {
+				cls[i] = sos
+			} else {
+				cls[i] = cls[i-1]
+			}
+		}
+	}
+
+	// W2: european numbers after arabic letters are arabic numbers, W3
+	last := sos
+	for i := range cls {
+		switch cls[i] {
+		case bidi.L, bidi.R, bidi.AL:
+			last = cls[i]
+		case bidi.EN:
+			if last == bidi.AL {
+				cls[i] = bidi.AN
+			}
+		}
+	}
+	for i := range cls {
+		if cls[i] == bidi.AL {
+			cls[i] = bidi.R
+		}
+	}
+
+	// W4: single separators between numbers
+	for i := 1; i < n-1; i++ {
+		prev, next := cls[i-1], cls[i+1]
+		switch {
+		case cls[i] == bidi.ES && prev == bidi.EN && next == bidi.EN:
+			cls[i] = bidi.EN
+		case cls[i] == bidi.CS && prev == bidi.EN && next == bidi.EN:
+			cls[i] = bidi.EN
+		case cls[i] == bidi.CS && prev == bidi.AN && next == bidi.AN:
+			cls[i] = bidi.AN
+		}
+	}
+
+	// W5: terminators (e.g. currency symbols) adjacent to european numbers
+	for i := 0; i < n; {
+		if cls[i] != bidi.ET {
+			i++
+			continue
+		}
+
+		j := i
+		for j < n && cls[j] == bidi.ET {
+			j++
+		}
+
+		if (i > 0 && cls[i-1] == bidi.EN) || (j < n && cls[j] == bidi.EN) {
+			for k := i; k < j; k++ {
+				cls[k] = bidi.EN
+			}
+		}
+
+		i = j
+	}
+
+	// W6: remaining separators and terminators are neutrals, W7
+	last = sos
+	for i := range cls {
+		switch cls[i] {
+		case bidi.ES, bidi.ET, bidi.CS:
+			cls[i] = bidi.ON
+		case bidi.L, bidi.R:
+			last = cls[i]
+		case bidi.EN:
+			if last == bidi.L {
+				cls[i] = bidi.L
+			}
+		}
+	}
+
+	// N1, N2: neutrals take the direction of the surrounding text if both
+	// sides agree, numbers count as right-to-left
+	strong := func(c bidi.Class) (bidi.Class, bool) {
+		switch c {
+		case bidi.L:
+			return bidi.L, true
+		case bidi.R, bidi.EN, bidi.AN:
+			return bidi.R, true
+		}
+		return 0, false
+	}
+
+	for i := 0; i < n; {
+		if _, ok := strong(cls[i]); ok {
+			i++
+			continue
+		}
+
+		j := i
+		for j < n {
+			if _, ok := strong(cls[j]); ok {
+				break
+			}
+			j++
+		}
+
+		before, after := sos, sos
+		if i > 0 {
+			before, _ = strong(cls[i-1])
+		}
+		if j < n {
+			after, _ = strong(cls[j])
+		}
+
+		resolved := sos
+		if before == after {
+			resolved = before
+		}
+
+		for k := i; k < j; k++ {
+			cls[k] = resolved
+		}
+
+		i = j
+	}
+
+	// I1, I2
+	levels := make([]int, n)
+	for i, c := range cls {
+		levels[i] = base
+
+		switch {
+		case base == 0 && c == bidi.R:
+			levels[i] = 1
+		case base == 0 && (c == bidi.EN || c == bidi.AN):
+			levels[i] = 2
+		case base == 1 && c != bidi.R:
+			levels[i] = 2
+		}
+	}
+
+	// L1: separators and trailing whitespace are reset to the base level
+	trailing := true
+	for i := n - 1; i >= 0; i-- {
+		switch original[i] {
+		case bidi.S, bidi.B:
+			levels[i] = base
+			trailing = true
+		case bidi.WS:
+			if trailing {
+				levels[i] = base
+			}
+		default:
+			trailing = false
+		}
+	}
+
+	return levels
+}
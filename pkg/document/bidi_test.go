@@ -0,0 +1,79 @@
+package document
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReorderLine(t *testing.T) {
+	// latin text is not changed
+	assert.Equal(t, "Rechnung 123", reorderLine("Rechnung 123", false))
+
+	// hebrew words are reversed, the order of the words too
+	assert.Equal(t, "Kunde םלוע םולש", reorderLine("Kunde שלום עולם", false))
+
+	// numbers keep their order inside right-to-left text
+	assert.Equal(t, "123 םולש", reorderLine("שלום 123", true))
+	assert.Equal(t, "€ 1.234,50", reorderLine("1.234,50 €", true))
+
+	// brackets are mirrored
+	assert.Equal(t, "(abc) םולש", reorderLine("שלום (abc)", true))
+	assert.Equal(t, "(םולש)", reorderLine("(שלום)", false))
+
+	// combining marks stay behind their base character
+	assert.Equal(t, "בּא", reorderLine("אבּ", true))
+}
+
+func TestIsRTLParagraph(t *testing.T) {
+	assert.True(t, isRTLParagraph("שלום abc", false))
+	assert.False(t, isRTLParagraph("abc שלום", true))
+	assert.True(t, isRTLParagraph("123", true))
+	assert.False(t, isRTLParagraph("123", false))
+}
+
+func TestShapeArabic(t *testing.T) {
+	// beh, alef: initial beh, final alef
+	assert.Equal(t, "ﺑﺎ", shapeArabic("با"))
+
+	// seen, lam, alef, meem: initial, ligature (final), isolated
+	assert.Equal(t, "ﺳﻼﻡ", shapeArabic("سلام"))
+
+	// alef does not join the following letter
+	assert.Equal(t, "ﺍﺑﺮ", shapeArabic("ابر"))
+
+	// harakat are skipped
+	assert.Equal(t, "ﺑَﺮ", shapeArabic("بَر"))
+
+	assert.Equal(t, "abc", shapeArabic("abc"))
+}
+
+func TestMirrorAlign(t *testing.T) {
+	doc := NewA4()
+	assert.Equal(t, "LT", doc.mirrorAlign("LT"))
+
+	doc.SetDirection(DirectionRTL)
+	assert.Equal(t, DirectionRTL, doc.GetDirection())
+	assert.Equal(t, "RT", doc.mirrorAlign("LT"))
+	assert.Equal(t, "L", doc.mirrorAlign("R"))
+	assert.Equal(t, "C", doc.mirrorAlign("C"))
+	assert.Equal(t, "RM", doc.mirrorAlign("M"))
+}
+
+func TestRTLDocument(t *testing.T) {
+	doc := NewA4(WithDirection(DirectionRTL))
+	doc.SetFont(FontFamilyDejaVu, "", 10)
+
+	doc.MCell(0, 5, "שלום עולם, מספר חשבונית 2024-001\nمرحبا بالعالم", "", "L", false)
+
+	table, _ := NewDocTable(doc, [][]string{{"תיאור", "כמות", "סכום"}, {"מוצר א", "2", "₪ 100.00"}})
+	table.SetHeadType(HeadFirstRow)
+	table.SetColTypes([]ColumnType{ColDyn, ColFixed, ColFixed})
+	table.SetAllColFixedWidths(25)
+	table.SetAllCellTypes(CellMulti)
+	assert.NoError(t, table.Generate())
+
+	var buf bytes.Buffer
+	assert.NoError(t, doc.Output(&buf))
+}
@@ -0,0 +1,82 @@
+package document
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrColorFormat = errors.New("colors have to be given as #RRGGBB or #RGB")
+
+// Color is a RGB color with components from 0 to 255.
+type Color struct {
+	R int
+	G int
+	B int
+}
+
+var (
+	ColorBlack     = Color{0, 0, 0}
+	ColorWhite     = Color{255, 255, 255}
+	ColorLightGray = Color{220, 220, 220}
+)
+
+// ParseHexColor parses a color in the form #RRGGBB or #RGB, an alpha channel
+// (#RRGGBBAA or #RGBA) is ignored as PDF colors are opaque.
+func ParseHexColor(s string) (Color, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == len(s) {
+		return Color{}, ErrColorFormat
+	}
+
+	switch len(hex) {
+	case 3, 4:
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	case 8:
+		hex = hex[:6]
+	}
+
+	if len(hex) != 6 {
+		return Color{}, ErrColorFormat
+	}
+
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return Color{}, ErrColorFormat
+	}
+
+	return Color{R: int(v >> 16 & 0xff), G: int(v >> 8 & 0xff), B: int(v & 0xff)}, nil
+}
+
+// Hex returns the color as #RRGGBB.
+func (c Color) Hex() string {
+	return fmt.Sprintf("#%02X%02X%02X", c.R, c.G, c.B)
+}
+
+// SetTextColorSpec sets the color of texts.
+func (d *Doc) SetTextColorSpec(c Color) {
+	d.SetTextColor(c.R, c.G, c.B)
+}
+
+// GetTextColorSpec returns the color of texts.
+func (d *Doc) GetTextColorSpec() Color {
+	r, g, b := d.GetTextColor()
+	return Color{R: r, G: g, B: b}
+}
+
+// SetFillColorSpec sets the color of filled areas.
+func (d *Doc) SetFillColorSpec(c Color) {
+	d.SetFillColor(c.R, c.G, c.B)
+}
+
+// SetDrawColorSpec sets the color of lines and borders.
+func (d *Doc) SetDrawColorSpec(c Color) {
+	d.SetDrawColor(c.R, c.G, c.B)
+}
+
+// GetDrawColorSpec returns the color of lines and borders.
+func (d *Doc) GetDrawColorSpec() Color {
+	r, g, b := d.GetDrawColor()
+	return Color{R: r, G: g, B: b}
+}
@@ -0,0 +1,41 @@
+package document
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHexColor(t *testing.T) {
+	c, err := ParseHexColor("#1E6FD9")
+	assert.NoError(t, err)
+	assert.Equal(t, Color{30, 111, 217}, c)
+	assert.Equal(t, "#1E6FD9", c.Hex())
+
+	c, err = ParseHexColor("#fa0")
+	assert.NoError(t, err)
+	assert.Equal(t, Color{255, 170, 0}, c)
+
+	c, err = ParseHexColor("#1E6FD980")
+	assert.NoError(t, err)
+	assert.Equal(t, Color{30, 111, 217}, c)
+
+	for _, s := range []string{"", "1E6FD9", "#1E6FD", "#GGGGGG", "blue"} {
+		_, err = ParseHexColor(s)
+		assert.ErrorIs(t, err, ErrColorFormat, s)
+	}
+}
+
+func TestCellTextColors(t *testing.T) {
+	doc := NewA4()
+	doc.SetTextColorSpec(Color{51, 51, 51})
+
+	table, _ := NewDocTable(doc, [][]string{{"Head", "Value"}, {"Body", "1"}})
+	table.SetCellTextColorsRow(0, &Color{255, 0, 0})
+	assert.Error(t, table.SetCellTextColorsPerColumn([]*Color{nil}))
+	table.Generate()
+
+	// the text color of the document is restored
+	assert.Equal(t, Color{51, 51, 51}, doc.GetTextColorSpec())
+	assert.NoError(t, doc.Error())
+}
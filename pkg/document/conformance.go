@@ -0,0 +1,95 @@
+package document
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+)
+
+// Conformance is the standard a generated pdf conforms to. The zero value
+// creates a plain pdf.
+type Conformance string
+
+const (
+	// ConformancePdfA3b creates an archivable PDF/A-3b (ISO 19005-3 level B)
+	// document: all fonts are embedded, an sRGB output intent is added and the
+	// XMP metadata identifies the document as PDF/A. PDF/A-3 allows associated
+	// files, e.g. Factur-X/ZUGFeRD invoices.
+	ConformancePdfA3b Conformance = "PDFA_3B"
+)
+
+// IsPdfA reports whether the conformance is a PDF/A level.
+func (c Conformance) IsPdfA() bool {
+	return c == ConformancePdfA3b
+}
+
+// coreFontRegex matches the font dictionaries gofpdf writes for core fonts,
+// which are never embedded.
+var coreFontRegex = regexp.MustCompile(`/BaseFont /(\S+)\n/Subtype /Type1\n`)
+
+// pdfaIdentification is the XMP description marking the document as PDF/A-3b.
+const pdfaIdentification = `<rdf:Description rdf:about="" xmlns:pdfaid="http://www.aiim.org/pdfa/ns/id/">
+<pdfaid:part>3</pdfaid:part>
+<pdfaid:conformance>B</pdfaid:conformance>
+</rdf:Description>
+`
+
+// Option configures a document when it gets created.
+type Option func(*Doc) *Doc
+
+// WithConformance creates the document with the given conformance. PDF/A
+// always uses the embedded UTF-8 fonts, even WithCoreFonts, and Doc.Output
+// writes the additional PDF/A structures.
+func WithConformance(c Conformance) Option {
+	return func(d *Doc) *Doc {
+		d.conformance = c
+
+		return d
+	}
+}
+
+// GetConformance returns the conformance of the document.
+func (d *Doc) GetConformance() Conformance {
+	return d.conformance
+}
+
+// checkPdfA checks the output of gofpdf for structures which are not allowed
+// in PDF/A documents.
+func checkPdfA(src []byte) error {
+	if match := coreFontRegex.FindSubmatch(src); match != nil {
+		return fmt.Errorf("pdf/a: font %s is not embedded", match[1])
+	}
+	if bytes.Contains(src, []byte("/JavaScript")) {
+		return fmt.Errorf("pdf/a: javascript is not allowed")
+	}
+	return nil
+}
+
+// linkAnnotation is the start of the link annotations gofpdf writes inline
+// into the page objects.
+var linkAnnotation = []byte("/Subtype /Link ")
+
+// printLinkAnnotations replaces the page objects with link annotations, which
+// are written without flags by gofpdf. PDF/A requires the print flag.
+func printLinkAnnotations(u *pdfUpdate) error {
+	for n := 1; n < u.size; n++ {
+		offset, err := u.objectOffset(n)
+		if err != nil {
+			return err
+		}
+		if offset == 0 {
+			continue
+		}
+
+		body, err := u.objectBody(n)
+		if err != nil {
+			return err
+		}
+
+		if bytes.HasPrefix(body, []byte("<</Type /Page\n")) && bytes.Contains(body, linkAnnotation) {
+			u.set(n, bytes.ReplaceAll(body, linkAnnotation, []byte("/Subtype /Link /F 4 ")))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package document
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutputPdfA3b(t *testing.T) {
+	doc := NewA4(WithConformance(ConformancePdfA3b))
+	doc.SetTitle("Rechnung 1", true)
+	doc.MCell(0, 5, "Größe: 10 €", "", "L", false)
+
+	var buf bytes.Buffer
+	err := doc.Output(&buf)
+	assert.NoError(t, err)
+
+	out := buf.Bytes()
+
+	assert.True(t, bytes.HasPrefix(out, []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")))
+	assert.Contains(t, string(out), "/OutputIntents [")
+	assert.Contains(t, string(out), "/S /GTS_PDFA1")
+	assert.Contains(t, string(out), "<pdfaid:part>3</pdfaid:part>")
+	assert.Contains(t, string(out), "<pdfaid:conformance>B</pdfaid:conformance>")
+	assert.Contains(t, string(out), "/FontFile2")
+	assert.NotContains(t, string(out), "/Subtype /Type1")
+
+	// offsets of the original cross-reference table have to be moved by the
+	// new header
+	xref := bytes.Index(out, []byte("xref\n0 "))
+	lines := bytes.Split(out[xref:], []byte("\n"))
+	count, _ := strconv.Atoi(string(bytes.Fields(lines[1])[1]))
+	for n := 1; n < count; n++ {
+		offset, err := strconv.Atoi(string(lines[2+n][:10]))
+		assert.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(out[offset:], []byte(fmt.Sprintf("%d 0 obj", n))), "object %d", n)
+	}
+}
+
+func TestOutputPdfA3bCoreFont(t *testing.T) {
+	doc := NewA4(WithConformance(ConformancePdfA3b))
+	doc.SetFont("Arial", "", 8)
+	doc.MCell(0, 5, "not embedded", "", "L", false)
+
+	var buf bytes.Buffer
+	err := doc.Output(&buf)
+	assert.EqualError(t, err, "pdf/a: font Helvetica is not embedded")
+}
+
+func TestSrgbProfile(t *testing.T) {
+	profile := srgbProfile()
+
+	assert.Equal(t, len(profile), int(binary.BigEndian.Uint32(profile)))
+	assert.Equal(t, 0, len(profile)%4)
+	assert.Equal(t, "mntr", string(profile[12:16]))
+	assert.Equal(t, "RGB ", string(profile[16:20]))
+	assert.Equal(t, "acsp", string(profile[36:40]))
+	assert.Equal(t, uint32(9), binary.BigEndian.Uint32(profile[128:]))
+}
@@ -1,6 +1,13 @@
 package document
 
-import "github.com/jung-kurt/gofpdf"
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+)
 
 type Doc struct {
 	*gofpdf.Fpdf
@@ -10,6 +17,30 @@ type Doc struct {
 	// of text is calculated by fontSize (in units) * lineHeight. Default is 1.2.
 	lineHeight float64
 	trUTF8     func(string) string
+	// fontFamily is the default font family set on creation.
+	fontFamily string
+	// font is the current font, only tracked when set by Doc.SetFont
+	font FontSpec
+	// unicode is true when UTF-8 fonts are used, coreFonts requests the core
+	// fonts with cp1252 instead.
+	unicode     bool
+	coreFonts   bool
+	conformance Conformance
+	// direction is the base direction of texts and tables, see Direction
+	direction Direction
+
+	meta            metadata
+	attachments     []Attachment
+	xmpDescriptions []string
+
+	// assets provides the images referenced by their asset id
+	assets AssetSource
+	// fetcher downloads the images referenced by their url
+	fetcher ImageFetcher
+	// imageNormalization prepares the raster images for embedding
+	imageNormalization ImageNormalization
+	// footerless are the page numbers without footer, see SkipFooter
+	footerless map[int]bool
 }
 
 // NewA4 creates a new pdf in DIN A4 format with one page added.
@@ -22,7 +53,7 @@ type Doc struct {
 //
 // size: A4
 //
-// font: Arial
+// font: DejaVu (Arial WithCoreFonts)
 //
 // fontSize: 8
 //
@@ -31,12 +62,8 @@ type Doc struct {
 // document margins: left: 10, top: 10, right: 10
 //
 // line width: 0.2
-func NewA4() *Doc {
-	doc := &Doc{}
-	doc.Fpdf = newA4(nil)
-	doc.lineHeight = 1.2
-	doc.trUTF8 = doc.UnicodeTranslatorFromDescriptor("")
-	return doc
+func NewA4(opts ...Option) *Doc {
+	return newDoc(PageSizeA4, nil, opts)
 }
 
 // NewA4 creates a new pdf in DIN A4 format with one page added.
@@ -49,7 +76,7 @@ func NewA4() *Doc {
 //
 // size: A4
 //
-// font: Arial
+// font: DejaVu (Arial WithCoreFonts)
 //
 // fontSize: 8
 //
@@ -58,12 +85,14 @@ func NewA4() *Doc {
 // document margins: left: 10, top: 10, right: 10
 //
 // line width: 0.2
-func NewA4WithDefaults(setDetaultsFunc *func(*gofpdf.Fpdf)) *Doc {
-	doc := &Doc{}
-	doc.Fpdf = newA4(setDetaultsFunc)
-	doc.lineHeight = 1.2
-	doc.trUTF8 = doc.UnicodeTranslatorFromDescriptor("")
-	return doc
+func NewA4WithDefaults(setDetaultsFunc *func(*gofpdf.Fpdf), opts ...Option) *Doc {
+	return newDoc(PageSizeA4, setDetaultsFunc, opts)
+}
+
+// NewWithDefaults creates a new pdf like NewA4WithDefaults with pages of the
+// given size, e.g. the page size of a Layout.
+func NewWithDefaults(size PageSize, setDetaultsFunc *func(*gofpdf.Fpdf), opts ...Option) *Doc {
+	return newDoc(size, setDetaultsFunc, opts)
 }
 
 // SetLineHeight sets the line height. Values 0 and lower will be disgarded.
@@ -105,16 +134,42 @@ func (d *Doc) GetPrintHeight() float64 {
 	return pageHeight - marginT - marginB
 }
 
-func newA4(setDetaultsFunc *func(*gofpdf.Fpdf)) *gofpdf.Fpdf {
+// SkipFooter marks the current page as a page without footer, the footer
+// function checks it with HasFooter.
+func (d *Doc) SkipFooter() {
+	if d.footerless == nil {
+		d.footerless = make(map[int]bool)
+	}
+	d.footerless[d.Fpdf.PageNo()] = true
+}
+
+// HasFooter reports whether the footer is printed on the current page.
+func (d *Doc) HasFooter() bool {
+	return !d.footerless[d.Fpdf.PageNo()]
+}
+
+func newDoc(size PageSize, setDetaultsFunc *func(*gofpdf.Fpdf), opts []Option) *Doc {
 	pdf := gofpdf.NewCustom(&gofpdf.InitType{
 		OrientationStr: "P",
 		UnitStr:        "mm",
-		SizeStr:        gofpdf.PageSizeA4,
+		Size:           gofpdf.SizeType{Wd: size.Width, Ht: size.Height},
 		FontDirStr:     "",
 	})
 
+	doc := &Doc{}
+	doc.Fpdf = pdf
+	doc.lineHeight = 1.2
+	doc.imageNormalization = DefaultImageNormalization
+
+	// options have to be applied before the defaults, e.g. to register fonts
+	for _, opt := range opts {
+		doc = opt(doc)
+	}
+
+	doc.setupFonts()
+
 	if setDetaultsFunc == nil {
-		pdf.SetFont("Arial", "", 8)
+		doc.SetFont(doc.fontFamily, "", 8)
 		pdf.SetMargins(10, 10, 10)
 		pdf.SetCellMargin(0)
 		pdf.SetLineWidth(0.2)
@@ -126,5 +181,93 @@ func newA4(setDetaultsFunc *func(*gofpdf.Fpdf)) *gofpdf.Fpdf {
 
 	pdf.AddPage()
 
-	return pdf
+	return doc
+}
+
+// Output wraps Fpdf.Output. When attachments or XMP metadata are added to the
+// document or it has to conform to PDF/A, they are written with an incremental
+// update after gofpdf has finished the document, as gofpdf neither references
+// the XMP metadata in the catalog nor supports associated files or output
+// intents.
+func (d *Doc) Output(w io.Writer) error {
+	if len(d.attachments) == 0 && len(d.xmpDescriptions) == 0 && !d.conformance.IsPdfA() {
+		return d.Fpdf.Output(w)
+	}
+
+	tm := time.Now().UTC().Truncate(time.Second)
+
+	var buf bytes.Buffer
+	if err := d.Fpdf.Output(&buf); err != nil {
+		return err
+	}
+
+	if d.conformance.IsPdfA() {
+		if err := checkPdfA(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	// associated files require pdf 1.7, the binary comment marks the file as
+	// binary for transfer programs and is required by PDF/A
+	src, err := rewriteHeader(buf.Bytes(), "%PDF-1.7\n%\xe2\xe3\xcf\xd3")
+	if err != nil {
+		return err
+	}
+
+	u, err := newPdfUpdate(src)
+	if err != nil {
+		return err
+	}
+
+	if d.conformance.IsPdfA() {
+		if err := printLinkAnnotations(u); err != nil {
+			return err
+		}
+	}
+
+	catalog, err := u.objectBody(u.root)
+	if err != nil {
+		return err
+	}
+
+	// the name dictionary of gofpdf is always the last entry of the catalog and
+	// only holds the (here unused) embedded files and javascript
+	if idx := bytes.Index(catalog, []byte("/Names <<")); idx >= 0 {
+		catalog = catalog[:idx]
+	} else {
+		catalog = bytes.TrimSuffix(catalog, []byte(">>"))
+	}
+
+	var newCatalog bytes.Buffer
+	newCatalog.Write(bytes.TrimSpace(catalog))
+	newCatalog.WriteString("\n")
+
+	metadata := u.add(pdfStream("/Type /Metadata /Subtype /XML", d.xmpPacket(tm), false))
+	fmt.Fprintf(&newCatalog, "/Metadata %d 0 R\n", metadata)
+
+	if d.conformance.IsPdfA() {
+		profile := u.add(pdfStream("/N 3", srgbProfile(), true))
+		outputIntent := u.add([]byte(fmt.Sprintf("<< /Type /OutputIntent /S /GTS_PDFA1 /OutputConditionIdentifier %s /Info %s /DestOutputProfile %d 0 R >>",
+			pdfTextString(srgbOutputCondition), pdfTextString(srgbOutputCondition), profile)))
+		fmt.Fprintf(&newCatalog, "/OutputIntents [ %d 0 R ]\n", outputIntent)
+	}
+
+	if fileSpecs := d.writeAttachments(u, tm); len(fileSpecs) > 0 {
+		newCatalog.WriteString("/Names << /EmbeddedFiles << /Names [")
+		for i, spec := range fileSpecs {
+			fmt.Fprintf(&newCatalog, " %s %d 0 R", pdfTextString(d.attachments[i].Filename), spec)
+		}
+		newCatalog.WriteString(" ] >> >>\n/AF [")
+		for _, spec := range fileSpecs {
+			fmt.Fprintf(&newCatalog, " %d 0 R", spec)
+		}
+		newCatalog.WriteString(" ]\n")
+	}
+	newCatalog.WriteString(">>")
+
+	u.set(u.root, newCatalog.Bytes())
+	u.set(u.info, d.infoDictionary(tm))
+
+	_, err = u.WriteTo(w)
+	return err
 }
@@ -1,16 +1,35 @@
 package document
 
 // CFormat wraps CellFormat and transforms the given txtStr in a UTF-8
-// UnicodeTranslator to render special characters such as german Umlaute
+// UnicodeTranslator to render special characters such as german Umlaute.
+// Right-to-left texts are reordered and the alignment is mirrored in
+// right-to-left documents.
 func (d *Doc) CFormat(w, h float64, txtStr, borderStr string, ln int,
 	alignStr string, fill bool, link int, linkStr string) {
-	d.CellFormat(w, h, d.trUTF8(txtStr), borderStr, ln, alignStr, fill, link, linkStr)
+	d.CellFormat(w, h, d.visualLine(d.prepareText(txtStr)), borderStr, ln, d.mirrorAlign(alignStr), fill, link, linkStr)
 }
 
 // CFormat wraps MultiCell and transforms the given txtStr in a UTF-8
-// UnicodeTranslator to render special characters such as german Umlaute
+// UnicodeTranslator to render special characters such as german Umlaute.
+// Right-to-left texts are reordered and the alignment is mirrored in
+// right-to-left documents.
 func (d *Doc) MCell(w, h float64, txtStr, borderStr, alignStr string, fill bool) {
-	d.MultiCell(w, h, d.trUTF8(txtStr), borderStr, alignStr, fill)
+	d.multiCell(w, h, d.prepareText(txtStr), borderStr, d.mirrorAlign(alignStr), fill)
+}
+
+// splitText wraps SplitText for texts which are already translated by trUTF8.
+// Core fonts measure every byte of the code page, SplitText expects runes.
+func (d *Doc) splitText(txt string, w float64) []string {
+	if d.unicode {
+		return d.SplitText(txt, w)
+	}
+
+	runes := make([]rune, len(txt))
+	for i := 0; i < len(txt); i++ {
+		runes[i] = rune(txt[i])
+	}
+
+	return d.SplitText(string(runes), w)
 }
 
 // Ellipsis are three dots (...) representing that a string is longer than it is
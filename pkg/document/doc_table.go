@@ -38,6 +38,7 @@ type CellType int
 const (
 	CellSingle CellType = iota
 	CellMulti
+	CellRich
 )
 
 // DocTable
@@ -82,6 +83,7 @@ const (
 //   - cellAligns: AlignLeft
 //   - cellPaddings: Padding{0,0,0,0}
 //   - cellBorder: true
+//   - cellTextColors: nil (text color of the document)
 type DocTable struct {
 	doc *Doc
 
@@ -93,6 +95,9 @@ type DocTable struct {
 	// over a page break, the head gets rerendered before continuing with
 	// rendering data rows.
 	headType HeadType
+	// headFont is the font of the head, the other rows use the current font of
+	// the document. Only used with HeadFirstRow.
+	headFont *FontSpec
 
 	// colTypes determine how a column width will be calculated.
 	//
@@ -155,6 +160,9 @@ type DocTable struct {
 	// CellMulti will render the cell content over multiple lines contraint by the
 	// column width.
 	//
+	// CellRich will render the cell content like CellMulti with markup, see
+	// RichText.
+	//
 	// Default is CellSingle.
 	cellTypes [][]CellType
 	// cellLineHeightFactors determine the line height of every cell calculated by
@@ -187,6 +195,9 @@ type DocTable struct {
 	cellBorders [][]bool
 	// cellStyleFuncs determine the style for every cell.
 	cellStyleFuncs [][]*func(gofpdf.Fpdf)
+	// cellTextColors determine the text color for every cell, nil uses the
+	// current text color of the document.
+	cellTextColors [][]*Color
 
 	// tableRows will be calculated by the number of rows of cells.
 	tableRows int
@@ -210,7 +221,7 @@ func NewDocTable(doc *Doc, cells [][]string, args ...interface{}) (*DocTable, er
 
 	for _, r := range cells {
 		for i := range r {
-			r[i] = doc.trUTF8(r[i])
+			r[i] = doc.prepareText(r[i])
 		}
 	}
 
@@ -279,6 +290,9 @@ func (t *DocTable) SetDefaults() {
 	if len(t.cellStyleFuncs) == 0 {
 		t.cellStyleFuncs = matrix[*func(gofpdf.Fpdf)](rows, cols, nil)
 	}
+	if len(t.cellTextColors) == 0 {
+		t.cellTextColors = matrix[*Color](rows, cols, nil)
+	}
 }
 
 func (t *DocTable) Generate() error {
@@ -306,17 +320,11 @@ func (t *DocTable) Generate() error {
 			t.doc.AddPage()
 
 			if t.headType == HeadFirstRow {
-				for j := 0; j < t.tableCols; j++ {
-					t.addColGap(0, j)
-					t.renderCell(0, j)
-				}
+				t.renderRow(0)
 			}
 		}
 
-		for j := 0; j < t.tableCols; j++ {
-			t.addColGap(i, j)
-			t.renderCell(i, j)
-		}
+		t.renderRow(i)
 	}
 	// TODO: add restore saved style function to doc and run it here.
 
@@ -391,11 +399,13 @@ func (t *DocTable) maxCellWidthOfColumn(j int) float64 {
 	maxCellWidth := 0.
 	for i := 0; i < t.tableRows; i++ {
 		p := t.cellPaddings[i][j]
-		// filter all CellMulti cells
-		if t.cellTypes[i][j] == CellMulti {
+		// filter all CellMulti and CellRich cells
+		if t.cellTypes[i][j] != CellSingle {
 			continue
 		}
+		restore := t.useRowFont(i)
 		w := t.doc.GetStringWidth(t.cells[i][j]) + p[paddingLeft] + p[paddingRight]
+		restore()
 		if w > maxCellWidth {
 			maxCellWidth = w
 		}
@@ -428,6 +438,8 @@ func (t *DocTable) calcRowHeights() {
 }
 
 func (t *DocTable) getRowHeight(i int) float64 {
+	defer t.useRowFont(i)()
+
 	rowHt := 0.
 	for j := 0; j < t.tableCols; j++ {
 		h := t.getCellHeight(i, j)
@@ -447,13 +459,32 @@ func (t *DocTable) getCellHeight(i, j int) float64 {
 	case CellSingle:
 		return lineHt + cellPadding
 	case CellMulti:
-		lines := t.doc.SplitText(t.cells[i][j], t.colWidths[j])
+		lines := t.doc.splitText(t.cells[i][j], t.colWidths[j])
+		return float64(len(lines))*lineHt + cellPadding
+	case CellRich:
+		w := t.colWidths[j] - p[paddingLeft] - p[paddingRight]
+		lines := t.doc.layoutRichText(parseRichText(t.cells[i][j]), w)
 		return float64(len(lines))*lineHt + cellPadding
 	default:
 		panic("unsupported CellType: " + fmt.Sprint(t.cellTypes[i][j]))
 	}
 }
 
+// useRowFont sets the head font for the head row, the returned function
+// restores the previous font.
+func (t *DocTable) useRowFont(i int) func() {
+	if i != 0 || t.headType != HeadFirstRow || t.headFont == nil {
+		return func() {}
+	}
+
+	font := t.doc.GetFont()
+	t.doc.SetFontSpec(*t.headFont)
+
+	return func() {
+		t.doc.SetFontSpec(font)
+	}
+}
+
 func (t *DocTable) getLineHeight(i, j int) float64 {
 	_, fontHt := t.doc.GetFontSize()
 	return fontHt * t.cellLineHeightFactors[i][j]
@@ -465,13 +496,40 @@ func (t *DocTable) addRowGap(i int) {
 	}
 }
 
-func (t *DocTable) addColGap(i, j int) {
-	if j > 0 && t.colGaps[j-1] != 0. {
-		t.doc.SetX(t.doc.GetX() + t.colGaps[j-1])
+// addColGap adds the gap between the columns g and g+1.
+func (t *DocTable) addColGap(g int) {
+	if t.colGaps[g] != 0. {
+		t.doc.SetX(t.doc.GetX() + t.colGaps[g])
+	}
+}
+
+// renderRow renders the cells of a row from left to right. Right-to-left
+// documents start with the last column at the right side of the print width.
+func (t *DocTable) renderRow(i int) {
+	if !t.doc.IsRTL() {
+		for j := 0; j < t.tableCols; j++ {
+			if j > 0 {
+				t.addColGap(j - 1)
+			}
+			t.renderCell(i, j)
+		}
+	} else {
+		t.doc.SetX(t.doc.GetX() + t.doc.GetPrintWidth() - t.tableWidth)
+
+		for j := t.tableCols - 1; j >= 0; j-- {
+			if j < t.tableCols-1 {
+				t.addColGap(j)
+			}
+			t.renderCell(i, j)
+		}
 	}
+
+	t.doc.Ln(t.rowHeights[i])
 }
 
 func (t *DocTable) renderCell(i, j int) {
+	defer t.useRowFont(i)()
+
 	//check for page break
 
 	t.doc.SetFillColor(255, 255, 255)
@@ -483,6 +541,13 @@ func (t *DocTable) renderCell(i, j int) {
 		tmpFunc(*t.doc.Fpdf)
 	}
 
+	//the style funcs work on a copy, the text color is set separately
+	if c := t.cellTextColors[i][j]; c != nil {
+		old := t.doc.GetTextColorSpec()
+		t.doc.SetTextColorSpec(*c)
+		defer t.doc.SetTextColorSpec(old)
+	}
+
 	//draw
 	doc := t.doc
 	x, y := doc.GetXY()
@@ -496,7 +561,7 @@ func (t *DocTable) renderCell(i, j int) {
 		doc.Rect(x, y, t.colWidths[j], t.rowHeights[i], "F")
 	}
 
-	alignStr := alignToFpdf(t.cellAligns[i][j])
+	alignStr := doc.mirrorAlign(alignToFpdf(t.cellAligns[i][j]))
 
 	doc.SetXY(x+p[paddingLeft], y+p[paddingTop])
 	switch t.cellTypes[i][j] {
@@ -520,18 +585,20 @@ func (t *DocTable) renderCell(i, j int) {
 		}
 		cellHt := t.rowHeights[i] - p[paddingTop] - p[paddingBottom]
 		doc.SetFillColor(oldColX, oldColY, oldColZ)
-		doc.CFormat(w, cellHt, cellStr, "", ln, alignStr, false, 0, "")
+		// cells are translated on creation of the table
+		doc.CellFormat(w, cellHt, doc.visualLine(cellStr), "", ln, alignStr, false, 0, "")
 		doc.SetXY(doc.GetX()+p[paddingRight], y)
 	case CellMulti:
 		doc.SetFillColor(oldColX, oldColY, oldColZ)
-		doc.MCell(w, t.getLineHeight(i, j), t.cells[i][j], "", alignStr, false)
+		doc.multiCell(w, t.getLineHeight(i, j), t.cells[i][j], "", alignStr, false)
+		doc.SetXY(x+w+p[paddingLeft]+p[paddingRight], y)
+	case CellRich:
+		doc.SetFillColor(oldColX, oldColY, oldColZ)
+		doc.richText(w, t.getLineHeight(i, j), t.cells[i][j], alignStr)
 		doc.SetXY(x+w+p[paddingLeft]+p[paddingRight], y)
 	default:
 		panic("unsupported CellType: " + fmt.Sprint(t.cellTypes[i][j]))
 	}
-	if j == t.tableCols-1 {
-		doc.Ln(t.rowHeights[i])
-	}
 }
 
 // TABLE PARAMETER SETTERS
@@ -541,6 +608,11 @@ func (t *DocTable) SetHeadType(ht HeadType) {
 	}
 }
 
+// SetHeadFont sets the font of the head row (HeadFirstRow).
+func (t *DocTable) SetHeadFont(font FontSpec) {
+	t.headFont = &font
+}
+
 //  COL SETTERS ----------------------------------------------------------------
 
 func (t *DocTable) SetAllColTypes(ct ColumnType) {
@@ -745,6 +817,31 @@ func (t *DocTable) SetCellStyleFuncsPerColumn(fs []*func(gofpdf.Fpdf)) error {
 
 	return nil
 }
+func (t *DocTable) SetAllCellTextColors(c *Color) {
+	t.cellTextColors = matrix(t.tableRows, t.tableCols, c)
+}
+func (t *DocTable) SetCellTextColorsRow(i int, c *Color) error {
+	if err := t.checkRowIndex(i); err != nil {
+		return err
+	}
+
+	t.cellTextColors[i] = array(t.tableCols, c)
+
+	return nil
+}
+func (t *DocTable) SetCellTextColorsPerColumn(cs []*Color) error {
+	if len(cs) != t.tableCols {
+		return fmt.Errorf("column count mismatch: got: %v should: %v", len(cs), t.tableCols)
+	}
+
+	for j := 0; j < t.tableCols; j++ {
+		for i := 0; i < t.tableRows; i++ {
+			t.cellTextColors[i][j] = cs[j]
+		}
+	}
+
+	return nil
+}
 
 // HELPER ----------------------------------------------------------------------
 
@@ -0,0 +1,26 @@
+package document
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSkipFooter(t *testing.T) {
+	doc := NewA4()
+
+	footers := []int{}
+	doc.SetFooterFunc(func() {
+		if doc.HasFooter() {
+			footers = append(footers, doc.PageNo())
+		}
+	})
+
+	doc.AddPage()
+	doc.SkipFooter()
+	doc.AddPage()
+	doc.Close()
+
+	// only the second page has no footer
+	assert.Equal(t, []int{1, 3}, footers)
+}
@@ -0,0 +1,242 @@
+package document
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"os"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// FontFamilyDejaVu is the font family of the bundled DejaVu Sans Condensed
+// font, which is the default font of all documents.
+const FontFamilyDejaVu = "DejaVu"
+
+// FontFamilyArial is the font family of the core font Arial (Helvetica), which
+// is not embedded into the document and only supports cp1252. It is only used
+// WithCoreFonts.
+const FontFamilyArial = "Arial"
+
+var (
+	ErrFontFormat         = errors.New("only TrueType fonts (.ttf) are supported")
+	ErrFontRegularMissing = errors.New("the regular style of the font is missing")
+	ErrFontFamilyMissing  = errors.New("the font requires a family name")
+	ErrFontData           = errors.New("the font data is neither base64 nor a base64 data URI")
+	ErrFontSize           = errors.New("the font exceeds 2MB")
+)
+
+// MaxFontDataBytes is the max size of a TTF file given by NewFontFromData.
+const MaxFontDataBytes = 2_000_000
+
+// FontStyles are the styles of a font family as used by SetFont: regular,
+// bold, italic and bold italic.
+var FontStyles = []string{"", "B", "I", "BI"}
+
+// FontSpec selects a font: family, style (see FontStyles) and size in pt.
+type FontSpec struct {
+	Family string
+	Style  string
+	Size   float64
+}
+
+//go:embed fonts/*.ttf
+var fontFiles embed.FS
+
+// embeddedFonts maps the font styles to the bundled font files.
+var embeddedFonts = map[string]string{
+	"":   "fonts/DejaVuSansCondensed.ttf",
+	"B":  "fonts/DejaVuSansCondensed-Bold.ttf",
+	"I":  "fonts/DejaVuSansCondensed-Oblique.ttf",
+	"BI": "fonts/DejaVuSansCondensed-BoldOblique.ttf",
+}
+
+// Font is a UTF-8 TrueType font family. Styles maps the styles (see
+// FontStyles) to the content of the TTF files. Only the used glyphs are
+// embedded into the document (subset).
+type Font struct {
+	Family string
+	Styles map[string][]byte
+}
+
+// NewFont checks the TTF files of a font family, styles without a file use
+// the regular style.
+func NewFont(family string, styles map[string][]byte) (*Font, error) {
+	if len(family) == 0 {
+		return nil, ErrFontFamilyMissing
+	}
+
+	if _, ok := styles[""]; !ok {
+		return nil, ErrFontRegularMissing
+	}
+
+	font := &Font{Family: family, Styles: make(map[string][]byte)}
+
+	for _, style := range FontStyles {
+		content, ok := styles[style]
+		if !ok {
+			content = styles[""]
+		}
+
+		if err := checkTrueType(content); err != nil {
+			return nil, err
+		}
+
+		font.Styles[style] = content
+	}
+
+	return font, nil
+}
+
+// LoadFont reads the TTF files of a font family, files maps the styles to the
+// paths of the files.
+func LoadFont(family string, files map[string]string) (*Font, error) {
+	styles := make(map[string][]byte)
+
+	for style, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		styles[style] = content
+	}
+
+	return NewFont(family, styles)
+}
+
+// NewFontFromData creates a font family of a TTF file given as base64 or a base64
+// data URI, e.g. by the request. The file is used for all styles.
+func NewFontFromData(family, data string) (*Font, error) {
+	content, err := decodeImageData(data)
+	if err != nil {
+		return nil, ErrFontData
+	}
+
+	if len(content) > MaxFontDataBytes {
+		return nil, ErrFontSize
+	}
+
+	return NewFont(family, map[string][]byte{"": content})
+}
+
+// checkTrueType parses the font with gofpdf, which only supports TrueType
+// outlines (no OpenType/CFF or collections).
+func checkTrueType(content []byte) error {
+	if len(content) < 4 || (!bytes.Equal(content[:4], []byte{0, 1, 0, 0}) && !bytes.Equal(content[:4], []byte("true"))) {
+		return ErrFontFormat
+	}
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes("check", "", content)
+
+	if pdf.Err() {
+		return ErrFontFormat
+	}
+
+	return nil
+}
+
+// WithFonts registers additional UTF-8 font families, e.g. supplied by the
+// configuration or the request.
+func WithFonts(fonts ...*Font) Option {
+	return func(d *Doc) *Doc {
+		for _, font := range fonts {
+			d.addFont(font)
+		}
+
+		return d
+	}
+}
+
+// WithFontFamily sets the default font family of the document, the family has
+// to be registered WithFonts or be bundled.
+func WithFontFamily(family string) Option {
+	return func(d *Doc) *Doc {
+		d.fontFamily = family
+		return d
+	}
+}
+
+// WithCoreFonts creates a document with the core font Arial, texts are
+// translated to cp1252. It has no effect with UTF-8 fonts or PDF/A.
+func WithCoreFonts() Option {
+	return func(d *Doc) *Doc {
+		d.coreFonts = true
+		return d
+	}
+}
+
+// GetFontFamily returns the default font family of the document.
+func (d *Doc) GetFontFamily() string {
+	return d.fontFamily
+}
+
+// SetFont wraps Fpdf.SetFont and remembers the font for GetFont. An empty
+// family keeps the current family, a size of 0 keeps the current size.
+func (d *Doc) SetFont(familyStr, styleStr string, size float64) {
+	d.Fpdf.SetFont(familyStr, styleStr, size)
+
+	if len(familyStr) > 0 {
+		d.font.Family = familyStr
+	}
+	d.font.Style = styleStr
+	d.font.Size, _ = d.GetFontSize()
+}
+
+// SetFontSpec sets the font given by the spec, see SetFont.
+func (d *Doc) SetFontSpec(font FontSpec) {
+	d.SetFont(font.Family, font.Style, font.Size)
+}
+
+// GetFont returns the font last set by SetFont.
+func (d *Doc) GetFont() FontSpec {
+	return d.font
+}
+
+// IsUnicode reports whether texts are passed as UTF-8 to gofpdf, which is the
+// case for all documents except WithCoreFonts.
+func (d *Doc) IsUnicode() bool {
+	return d.unicode
+}
+
+func (d *Doc) addFont(font *Font) {
+	//in a fixed order, the objects of the pdf do not change between runs
+	for _, style := range FontStyles {
+		if content, ok := font.Styles[style]; ok {
+			d.AddUTF8FontFromBytes(font.Family, style, content)
+		}
+	}
+
+	d.unicode = true
+}
+
+// setupFonts registers the bundled font family, unless the core fonts are
+// requested. Texts are passed to gofpdf as UTF-8 afterwards.
+func (d *Doc) setupFonts() {
+	if d.coreFonts && !d.unicode && !d.conformance.IsPdfA() {
+		d.trUTF8 = d.UnicodeTranslatorFromDescriptor("")
+
+		if len(d.fontFamily) == 0 {
+			d.fontFamily = FontFamilyArial
+		}
+		return
+	}
+
+	font := &Font{Family: FontFamilyDejaVu, Styles: make(map[string][]byte)}
+	for style, file := range embeddedFonts {
+		content, err := fontFiles.ReadFile(file)
+		if err != nil {
+			d.SetError(err)
+			return
+		}
+		font.Styles[style] = content
+	}
+	d.addFont(font)
+
+	d.trUTF8 = func(s string) string { return s }
+
+	if len(d.fontFamily) == 0 {
+		d.fontFamily = FontFamilyDejaVu
+	}
+}
@@ -0,0 +1,112 @@
+package document
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultFontFamily(t *testing.T) {
+	doc := NewA4()
+	assert.Equal(t, FontFamilyDejaVu, doc.GetFontFamily())
+	assert.True(t, doc.IsUnicode())
+
+	doc = NewA4(WithCoreFonts())
+	assert.Equal(t, FontFamilyArial, doc.GetFontFamily())
+	assert.False(t, doc.IsUnicode())
+
+	doc = NewA4(WithCoreFonts(), WithConformance(ConformancePdfA3b))
+	assert.Equal(t, FontFamilyDejaVu, doc.GetFontFamily())
+	assert.True(t, doc.IsUnicode())
+}
+
+func TestUnicodeText(t *testing.T) {
+	text := "Zażółć gęślą jaźń, Příliš žluťoučký kůň, Ελληνικά, Кириллица, Türkçe İş"
+
+	doc := NewA4()
+	doc.MCell(0, 5, text, "", "L", false)
+
+	table, _ := NewDocTable(doc, [][]string{{text, "1.234,50 €"}, {"Łódź", "Ğ"}})
+	table.SetAllCellTypes(CellMulti)
+	table.Generate()
+
+	var buf bytes.Buffer
+	assert.NoError(t, doc.Output(&buf))
+
+	out := buf.Bytes()
+	assert.Contains(t, string(out), "/FontFile2")
+	assert.NotContains(t, string(out), "/Subtype /Type1")
+
+	// only the used glyphs are embedded
+	assert.Less(t, len(out), 100000)
+}
+
+func TestNewFont(t *testing.T) {
+	regular, err := fontFiles.ReadFile(embeddedFonts[""])
+	assert.NoError(t, err)
+
+	font, err := NewFont("Custom", map[string][]byte{"": regular})
+	assert.NoError(t, err)
+	assert.Len(t, font.Styles, len(FontStyles))
+
+	doc := NewA4(WithFonts(font), WithFontFamily("Custom"))
+	assert.Equal(t, "Custom", doc.GetFontFamily())
+
+	doc.SetFont("Custom", "B", 10)
+	doc.MCell(0, 5, "Ελληνικά", "", "L", false)
+	assert.NoError(t, doc.Error())
+
+	_, err = NewFont("Custom", map[string][]byte{"B": regular})
+	assert.ErrorIs(t, err, ErrFontRegularMissing)
+
+	_, err = NewFont("Custom", map[string][]byte{"": []byte("OTTO font")})
+	assert.ErrorIs(t, err, ErrFontFormat)
+
+	_, err = NewFont("", map[string][]byte{"": regular})
+	assert.ErrorIs(t, err, ErrFontFamilyMissing)
+}
+
+func TestNewFontFromData(t *testing.T) {
+	regular, err := fontFiles.ReadFile(embeddedFonts[""])
+	assert.NoError(t, err)
+
+	data := base64.StdEncoding.EncodeToString(regular)
+
+	font, err := NewFontFromData("Custom", data)
+	assert.NoError(t, err)
+	assert.Equal(t, regular, font.Styles["BI"])
+
+	_, err = NewFontFromData("Custom", "data:font/ttf;base64,"+data)
+	assert.NoError(t, err)
+
+	_, err = NewFontFromData("Custom", "no base64!")
+	assert.ErrorIs(t, err, ErrFontData)
+
+	_, err = NewFontFromData("Custom", base64.StdEncoding.EncodeToString([]byte("OTTO font")))
+	assert.ErrorIs(t, err, ErrFontFormat)
+
+	_, err = NewFontFromData("Custom", base64.StdEncoding.EncodeToString(make([]byte, MaxFontDataBytes+1)))
+	assert.ErrorIs(t, err, ErrFontSize)
+}
+
+func TestFontSpec(t *testing.T) {
+	doc := NewA4()
+
+	doc.SetFont(FontFamilyDejaVu, "B", 12)
+	assert.Equal(t, FontSpec{Family: FontFamilyDejaVu, Style: "B", Size: 12}, doc.GetFont())
+
+	// empty family and size keep the current ones
+	doc.SetFont("", "I", 0)
+	assert.Equal(t, FontSpec{Family: FontFamilyDejaVu, Style: "I", Size: 12}, doc.GetFont())
+
+	table, _ := NewDocTable(doc, [][]string{{"Head"}, {"Body"}})
+	table.SetHeadType(HeadFirstRow)
+	table.SetHeadFont(FontSpec{Family: FontFamilyDejaVu, Style: "B", Size: 14})
+	table.Generate()
+
+	// the font of the document is restored after the head
+	assert.Equal(t, FontSpec{Family: FontFamilyDejaVu, Style: "I", Size: 12}, doc.GetFont())
+	assert.NoError(t, doc.Error())
+}
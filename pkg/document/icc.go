@@ -0,0 +1,138 @@
+package document
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"sync"
+)
+
+// srgbOutputCondition identifies the sRGB color space in the output intent.
+const srgbOutputCondition = "sRGB IEC61966-2.1"
+
+var (
+	srgbProfileOnce sync.Once
+	srgbProfileData []byte
+)
+
+// srgbProfile returns an ICC (v2.1) display profile of the sRGB color space,
+// which is used as output intent for PDF/A documents. The profile is created
+// once from the sRGB primaries adapted to D50 and the sRGB transfer curve.
+func srgbProfile() []byte {
+	srgbProfileOnce.Do(func() {
+		srgbProfileData = newSrgbProfile()
+	})
+	return srgbProfileData
+}
+
+func newSrgbProfile() []byte {
+	curve := make([]uint16, 1024)
+	for i := range curve {
+		v := float64(i) / float64(len(curve)-1)
+		if v <= 0.04045 {
+			v = v / 12.92
+		} else {
+			v = math.Pow((v+0.055)/1.055, 2.4)
+		}
+		curve[i] = uint16(math.Round(v * 65535))
+	}
+
+	tags := []struct {
+		signature string
+		data      []byte
+	}{
+		{"desc", iccTextDescription(srgbOutputCondition)},
+		{"cprt", iccText("No copyright, use freely")},
+		{"wtpt", iccXYZ(0.9642, 1.0, 0.8249)},
+		{"rXYZ", iccXYZ(0.4360747, 0.2225045, 0.0139322)},
+		{"gXYZ", iccXYZ(0.3850649, 0.7168786, 0.0971045)},
+		{"bXYZ", iccXYZ(0.1430804, 0.0606169, 0.7141733)},
+		{"rTRC", iccCurve(curve)},
+		{"gTRC", iccCurve(curve)},
+		{"bTRC", iccCurve(curve)},
+	}
+
+	// header (128 bytes) and tag table (count + 12 bytes per tag)
+	offset := 128 + 4 + 12*len(tags)
+
+	var table, data bytes.Buffer
+	binary.Write(&table, binary.BigEndian, uint32(len(tags)))
+	for _, tag := range tags {
+		table.WriteString(tag.signature)
+		binary.Write(&table, binary.BigEndian, uint32(offset+data.Len()))
+		binary.Write(&table, binary.BigEndian, uint32(len(tag.data)))
+
+		data.Write(tag.data)
+		// tag data is aligned to 4 bytes
+		for data.Len()%4 != 0 {
+			data.WriteByte(0)
+		}
+	}
+
+	size := offset + data.Len()
+
+	header := make([]byte, 128)
+	binary.BigEndian.PutUint32(header[0:], uint32(size))
+	binary.BigEndian.PutUint32(header[8:], 0x02100000)
+	copy(header[12:], "mntr")
+	copy(header[16:], "RGB ")
+	copy(header[20:], "XYZ ")
+	for i, v := range []uint16{2023, 1, 1, 0, 0, 0} {
+		binary.BigEndian.PutUint16(header[24+2*i:], v)
+	}
+	copy(header[36:], "acsp")
+	// rendering intent perceptual (0) and the D50 illuminant of the PCS
+	copy(header[68:], iccXYZ(0.9642, 1.0, 0.8249)[8:])
+
+	var buf bytes.Buffer
+	buf.Write(header)
+	buf.Write(table.Bytes())
+	buf.Write(data.Bytes())
+	return buf.Bytes()
+}
+
+// iccS15Fixed16 encodes v as signed 15.16 fixed point number.
+func iccS15Fixed16(v float64) uint32 {
+	return uint32(int32(math.Round(v * 65536)))
+}
+
+func iccXYZ(x, y, z float64) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("XYZ ")
+	buf.Write(make([]byte, 4))
+	for _, v := range []float64{x, y, z} {
+		binary.Write(&buf, binary.BigEndian, iccS15Fixed16(v))
+	}
+	return buf.Bytes()
+}
+
+func iccCurve(curve []uint16) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("curv")
+	buf.Write(make([]byte, 4))
+	binary.Write(&buf, binary.BigEndian, uint32(len(curve)))
+	binary.Write(&buf, binary.BigEndian, curve)
+	return buf.Bytes()
+}
+
+func iccText(s string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("text")
+	buf.Write(make([]byte, 4))
+	buf.WriteString(s)
+	buf.WriteByte(0)
+	return buf.Bytes()
+}
+
+func iccTextDescription(s string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("desc")
+	buf.Write(make([]byte, 4))
+	binary.Write(&buf, binary.BigEndian, uint32(len(s)+1))
+	buf.WriteString(s)
+	buf.WriteByte(0)
+	// empty unicode (language code + count) and scriptcode (code, count and
+	// 67 bytes filler) descriptions
+	buf.Write(make([]byte, 4+4+2+1+67))
+	return buf.Bytes()
+}
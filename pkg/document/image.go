@@ -2,69 +2,254 @@ package document
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
-	"image/jpeg"
-	"image/png"
-	"io/ioutil"
-	"net/http"
+	"fmt"
+	"image"
+	"strings"
+	"sync"
 
+	"github.com/google/uuid"
+	"github.com/hodl-repos/pdf-invoice/pkg/fetcher"
 	"github.com/jung-kurt/gofpdf"
 )
 
+var (
+	ErrImageFormat   = errors.New("cannot load image - wrong format?")
+	ErrImageData     = errors.New("the image data is neither base64 nor a base64 data URI")
+	ErrImageNotFound = errors.New("the image asset does not exist")
+)
+
+// Image is a raster image, see ImageNormalization, or an SVG image given by
+// exactly one of the url, the inline data or the id of an uploaded asset.
 type Image struct {
-	ImageUrl *string `json:"imageUrl" validate:"required"`
+	// downloaded by the image fetcher, see WithImageFetcher
+	ImageUrl *string `json:"imageUrl" validate:"required_without_all=Data AssetID,excluded_with=Data AssetID"`
+
+	// base64 or a base64 data URI, e.g. data:image/png;base64,iVBORw0KGgo...
+	Data *string `json:"data" validate:"excluded_with=AssetID"`
+
+	// uploaded image, see WithAssets
+	AssetID *uuid.UUID `json:"assetId"`
+
+	// drawn instead of an SVG image using features which cannot be drawn as
+	// vector paths, see SvgError
+	Fallback *Image `json:"fallback"`
 }
 
-func (doc *Doc) AddImage(dto *Image) (*gofpdf.ImageInfoType, error) {
-	rawImg, err := getImageFromUrl(dto.ImageUrl)
+// AssetSource provides the uploaded images referenced by Image.AssetID.
+type AssetSource interface {
+	Get(id uuid.UUID) ([]byte, bool)
+}
 
-	if err != nil {
-		return nil, err
+// WithAssets sets the source of the images referenced by their asset id.
+func WithAssets(assets AssetSource) Option {
+	return func(d *Doc) *Doc {
+		d.assets = assets
+		return d
 	}
+}
+
+// ImageFetcher downloads the images referenced by Image.ImageUrl.
+type ImageFetcher interface {
+	Fetch(url string) ([]byte, error)
+}
 
-	//TEST IF PNG OR JPG
-	if _, err = png.Decode(bytes.NewReader(*rawImg)); err == nil {
-		return doc.Fpdf.RegisterImageOptionsReader(*dto.ImageUrl,
-			gofpdf.ImageOptions{ImageType: "PNG", ReadDpi: true},
-			bytes.NewReader(*rawImg)), nil
+// WithImageFetcher sets the fetcher of the images referenced by their url,
+// documents without fetcher use a fetcher with the defaults of
+// fetcher.DefaultConfig.
+func WithImageFetcher(f ImageFetcher) Option {
+	return func(d *Doc) *Doc {
+		d.fetcher = f
+		return d
 	}
+}
+
+var (
+	defaultFetcher     *fetcher.Fetcher
+	defaultFetcherErr  error
+	defaultFetcherOnce sync.Once
+)
+
+func getDefaultFetcher() (ImageFetcher, error) {
+	defaultFetcherOnce.Do(func() {
+		defaultFetcher, defaultFetcherErr = fetcher.NewFetcher(fetcher.DefaultConfig())
+	})
 
-	if _, err = jpeg.Decode(bytes.NewReader(*rawImg)); err == nil {
-		return doc.Fpdf.RegisterImageOptionsReader(*dto.ImageUrl,
-			gofpdf.ImageOptions{ImageType: "JPG", ReadDpi: true},
-			bytes.NewReader(*rawImg)), nil
+	return defaultFetcher, defaultFetcherErr
+}
+
+// AddImage loads, normalizes and registers a raster image in its full
+// resolution and returns the name to draw it with Fpdf.ImageOptions. The image
+// is named by the hash of its normalized content, so the same image is only
+// embedded once.
+func (doc *Doc) AddImage(dto *Image) (string, *gofpdf.ImageInfoType, error) {
+	rawImg, err := doc.loadImage(dto)
+	if err != nil {
+		return "", nil, err
 	}
 
-	return nil, errors.New("cannot load image - wrong format?")
+	return doc.registerImage(rawImg, 0, 0)
 }
 
-func getImageFromUrl(url *string) (*[]byte, error) {
-	// Create a new request using http
-	req, err := http.NewRequest("GET", *url, nil)
+// DrawImage draws the image centered into the area keeping its aspect ratio.
+// Raster images are downsampled to the resolution of the ImageNormalization.
+// SVG images are drawn as vector paths, the fallback is drawn instead when
+// the SVG uses features which cannot be drawn.
+func (doc *Doc) DrawImage(dto *Image, x, y, width, height float64) error {
+	rawImg, err := doc.loadImage(dto)
+	if err != nil {
+		return err
+	}
+
+	if isSvg(rawImg) {
+		img, err := parseSvg(rawImg)
+
+		var svgErr *SvgError
+		if errors.As(err, &svgErr) && dto.Fallback != nil {
+			return doc.DrawImage(dto.Fallback, x, y, width, height)
+		}
+		if err != nil {
+			return err
+		}
 
+		x, y, width, height = fitImage(img.ratio(), x, y, width, height)
+		doc.drawSvg(img, x, y, width, height)
+
+		return nil
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(rawImg))
+	if err != nil || config.Width <= 0 || config.Height <= 0 {
+		return ErrImageFormat
+	}
+
+	x, y, width, height = fitImage(float64(config.Width)/float64(config.Height), x, y, width, height)
+
+	name, _, err := doc.registerImage(rawImg, width, height)
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	doc.Fpdf.ImageOptions(name, x, y, width, height, false, gofpdf.ImageOptions{ReadDpi: true}, 0, "")
+
+	return nil
+}
+
+// fitImage returns the largest area of the ratio centered in the area
+func fitImage(ratio, x, y, width, height float64) (float64, float64, float64, float64) {
+	if width/height >= ratio {
+		//the image gets a margin left and right
+		drawWidth := height * ratio
+		return x + (width-drawWidth)/2, y, drawWidth, height
 	}
 
-	// Send req using http Client
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	//the image gets a margin top and bottom
+	drawHeight := width / ratio
+	return x, y + (height-drawHeight)/2, width, drawHeight
+}
+
+// registerImage normalizes the image for the drawn size, see normalizeImage
+func (doc *Doc) registerImage(rawImg []byte, width, height float64) (string, *gofpdf.ImageInfoType, error) {
+	if isSvg(rawImg) {
+		return "", nil, fmt.Errorf("%w: svg images can only be drawn by DrawImage", ErrImageFormat)
+	}
 
+	data, imageType, err := doc.normalizeImage(rawImg, width, height)
 	if err != nil {
-		return nil, err
+		return "", nil, err
+	}
+
+	hash := sha256.Sum256(data)
+	name := hex.EncodeToString(hash[:])
+
+	info := doc.Fpdf.RegisterImageOptionsReader(name,
+		gofpdf.ImageOptions{ImageType: imageType, ReadDpi: true},
+		bytes.NewReader(data))
+
+	if doc.Fpdf.Err() {
+		return "", nil, doc.Fpdf.Error()
+	}
+
+	return name, info, nil
+}
+
+// DetectImageType returns the type of raster images, PNG, JPG, GIF, WEBP,
+// TIFF or BMP, and SVG for SVG images.
+func DetectImageType(data []byte) (string, error) {
+	if _, format, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+		if imageType, ok := imageTypes[format]; ok {
+			return imageType, nil
+		}
+	}
+
+	if isSvg(data) {
+		return "SVG", nil
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, errors.New("wront status @ get request: " + resp.Status)
+	return "", ErrImageFormat
+}
+
+func (doc *Doc) loadImage(dto *Image) ([]byte, error) {
+	switch {
+	case dto.Data != nil:
+		return decodeImageData(*dto.Data)
+
+	case dto.AssetID != nil:
+		if doc.assets == nil {
+			return nil, ErrImageNotFound
+		}
+
+		data, ok := doc.assets.Get(*dto.AssetID)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrImageNotFound, dto.AssetID)
+		}
+
+		return data, nil
+
+	case dto.ImageUrl != nil:
+		f := doc.fetcher
+		if f == nil {
+			var err error
+			if f, err = getDefaultFetcher(); err != nil {
+				return nil, err
+			}
+		}
+
+		return f.Fetch(*dto.ImageUrl)
+
+	default:
+		return nil, ErrImageFormat
 	}
+}
+
+// decodeImageData decodes base64 with or without the data URI prefix, line
+// breaks of wrapped base64 are ignored
+func decodeImageData(data string) ([]byte, error) {
+	if strings.HasPrefix(data, "data:") {
+		header, payload, ok := strings.Cut(data, ",")
+		if !ok || !strings.HasSuffix(header, ";base64") {
+			return nil, ErrImageData
+		}
 
-	defer resp.Body.Close()
+		data = payload
+	}
 
-	data, err := ioutil.ReadAll(resp.Body)
+	data = strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\r' || r == ' ' || r == '\t' {
+			return -1
+		}
+		return r
+	}, data)
 
+	raw, err := base64.StdEncoding.DecodeString(data)
 	if err != nil {
-		return nil, err
+		if raw, err = base64.RawStdEncoding.DecodeString(data); err != nil {
+			return nil, ErrImageData
+		}
 	}
 
-	return &data, nil
+	return raw, nil
 }
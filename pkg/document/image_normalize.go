@@ -0,0 +1,195 @@
+package document
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"math"
+
+	_ "golang.org/x/image/bmp"
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/tiff"
+	_ "golang.org/x/image/webp"
+)
+
+var ErrImageSize = errors.New("the image has too many pixels")
+
+// maxImagePixels limits the memory used to decode an image, 40 megapixels
+// take 160MB
+const maxImagePixels = 40_000_000
+
+// imageTypes are the image types of DetectImageType by the format names of
+// the image package
+var imageTypes = map[string]string{
+	"png":  "PNG",
+	"jpeg": "JPG",
+	"gif":  "GIF",
+	"webp": "WEBP",
+	"tiff": "TIFF",
+	"bmp":  "BMP",
+}
+
+// ImageNormalization configures how raster images are prepared for
+// embedding. Images which gofpdf cannot embed, e.g. GIF, WebP, TIFF, BMP, 16
+// bit or interlaced PNGs and CMYK JPEGs, are decoded and encoded as PNG, or
+// as JPEG when they come from a lossy format.
+type ImageNormalization struct {
+	// DPI is the maximum resolution of drawn images, images with more pixels
+	// are downsampled. 0 keeps the resolution.
+	DPI float64
+	// JPEGQuality of encoded JPEGs from 1 to 100, 0 uses jpeg.DefaultQuality
+	JPEGQuality int
+	// FlattenAlpha draws transparent images onto white, e.g. for printers
+	// without support of transparency
+	FlattenAlpha bool
+}
+
+// DefaultImageNormalization is used by documents without
+// WithImageNormalization.
+var DefaultImageNormalization = ImageNormalization{
+	DPI:         300,
+	JPEGQuality: 90,
+}
+
+// WithImageNormalization sets how raster images are prepared for embedding.
+func WithImageNormalization(n ImageNormalization) Option {
+	return func(d *Doc) *Doc {
+		d.imageNormalization = n
+		return d
+	}
+}
+
+// normalizeImage returns the image as PNG or JPEG, which gofpdf can embed,
+// and its gofpdf image type. Embeddable images are kept as they are unless
+// they have more pixels than needed for the drawn size, a size of 0 keeps the
+// resolution.
+func (doc *Doc) normalizeImage(data []byte, width, height float64) ([]byte, string, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width <= 0 || config.Height <= 0 {
+		return nil, "", ErrImageFormat
+	}
+
+	if config.Width*config.Height > maxImagePixels {
+		return nil, "", fmt.Errorf("%w: %dx%d", ErrImageSize, config.Width, config.Height)
+	}
+
+	n := doc.imageNormalization
+
+	targetWidth, targetHeight := config.Width, config.Height
+	if n.DPI > 0 && width > 0 && height > 0 {
+		//the drawn size in inches times the resolution
+		inches := doc.GetConversionRatio() / 72
+		scale := math.Min(width*inches*n.DPI/float64(config.Width), height*inches*n.DPI/float64(config.Height))
+		if scale < 1 {
+			targetWidth = int(math.Max(1, math.Round(float64(config.Width)*scale)))
+			targetHeight = int(math.Max(1, math.Round(float64(config.Height)*scale)))
+		}
+	}
+
+	downsample := targetWidth < config.Width
+	if !downsample && !n.FlattenAlpha && isEmbeddable(data, format, config) {
+		return data, imageTypes[format], nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrImageFormat, err)
+	}
+
+	//JPEG and lossy WebP decode into YCbCr, they are encoded as JPEG again
+	photo := false
+	switch img.(type) {
+	case *image.YCbCr, *image.CMYK:
+		photo = true
+	}
+
+	if downsample {
+		scaled := image.NewNRGBA(image.Rect(0, 0, targetWidth, targetHeight))
+		xdraw.CatmullRom.Scale(scaled, scaled.Bounds(), img, img.Bounds(), xdraw.Src, nil)
+		img = scaled
+	}
+
+	if n.FlattenAlpha && !isOpaque(img) {
+		img = flattenAlpha(img)
+	}
+
+	var buf bytes.Buffer
+
+	if photo && isOpaque(img) {
+		quality := n.JPEGQuality
+		if quality <= 0 {
+			quality = jpeg.DefaultQuality
+		}
+
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+			return nil, "", err
+		}
+
+		return buf.Bytes(), "JPG", nil
+	}
+
+	if err := png.Encode(&buf, to8Bit(img)); err != nil {
+		return nil, "", err
+	}
+
+	return buf.Bytes(), "PNG", nil
+}
+
+// isEmbeddable reports whether gofpdf can embed the image as it is: JPEGs in
+// RGB or gray and non-interlaced PNGs with up to 8 bits per channel
+func isEmbeddable(data []byte, format string, config image.Config) bool {
+	switch format {
+	case "jpeg":
+		return config.ColorModel != color.CMYKModel
+	case "png":
+		//bit depth and interlace method of the IHDR chunk
+		return len(data) > 28 && data[24] <= 8 && data[28] == 0
+	}
+
+	return false
+}
+
+func isOpaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+
+	return false
+}
+
+// flattenAlpha draws the image onto white
+func flattenAlpha(img image.Image) image.Image {
+	flat := image.NewRGBA(img.Bounds())
+	draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), img, img.Bounds().Min, draw.Over)
+
+	return flat
+}
+
+// to8Bit converts the images which png.Encode writes with 16 bits per
+// channel, it writes 8 bits only for paletted, gray, RGBA and NRGBA images
+func to8Bit(img image.Image) image.Image {
+	if _, ok := img.(image.PalettedImage); ok {
+		return img
+	}
+
+	switch img.ColorModel() {
+	case color.GrayModel, color.RGBAModel, color.NRGBAModel:
+		return img
+
+	case color.Gray16Model:
+		gray := image.NewGray(img.Bounds())
+		draw.Draw(gray, gray.Bounds(), img, img.Bounds().Min, draw.Src)
+		return gray
+	}
+
+	nrgba := image.NewNRGBA(img.Bounds())
+	draw.Draw(nrgba, nrgba.Bounds(), img, img.Bounds().Min, draw.Src)
+	return nrgba
+}
@@ -0,0 +1,216 @@
+package document
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/image/bmp"
+	"golang.org/x/image/tiff"
+)
+
+// testWebp is the 1bpp lossless gopher of golang.org/x/image
+const testWebp = `UklGRrIBAABXRUJQVlA4TKUBAAAvSsAYAA8w//M///MfeJAkbXvaSG7m8Q3GfYSBJekwQztm/IcZ
+lgwnmWImn2BK7aFmBtnVir6q//8VOkFE/xm4baTIu8c48ArEo6+B3zFKYln3pqClSCKX0begFTAX
+FOLXHSyF8cCNcZEG4OywuA4KVVfJCiArU7GAgJI8+lJP/OKMT/fBAjevg1cYB7YVkFuWga2lyPi5
+I0HFy5YTpWIHg0RZpkniRVW9odHAKOwosWuOGdxIyn2OvaCDvhg/we6TwadPBPbqBV58MsLmMJ8y
+ZnOWk8SRz4N+QoyPL+MnamzMvcE1rHNEr91F9GKZPVUcS9w7PhhH36suB9qPeYb/oLk6cuTiJ0wO
+K3m5h1cKjW6EVZCYMK7dxcKCBdgP9HkKr9gkAO2P8GKZGWVdIAatQa+1IDpt6qyorVwdy01xdW8J
+kfk6xjEXmVQQ+HQdFr6OKhIN34dXWq0+0qr6EJSCeeVLH9+gvGTLyqM65PQ44ihzlTXxQKjKbAvs
+hXgir7Lil9w4L2bvMycmjQcqXaMCO6BlY28i+FOLzbfI1vEqxAhotocAAA==`
+
+func encodeTestImage(t *testing.T, img image.Image, encode func(*bytes.Buffer, image.Image) error) []byte {
+	var buf bytes.Buffer
+	assert.NoError(t, encode(&buf, img))
+
+	return buf.Bytes()
+}
+
+func gradient(width, height int, alpha uint8) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 100, A: alpha})
+		}
+	}
+
+	return img
+}
+
+func decodeConfig(t *testing.T, data []byte) (image.Config, string) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	assert.NoError(t, err)
+
+	return config, format
+}
+
+func TestNormalizeImageFormats(t *testing.T) {
+	webp, err := base64.StdEncoding.DecodeString(testWebp)
+	assert.NoError(t, err)
+
+	paletted := image.NewPaletted(image.Rect(0, 0, 8, 4), []color.Color{color.Transparent, color.Black})
+	paletted.SetColorIndex(1, 1, 1)
+
+	gray16 := image.NewGray16(image.Rect(0, 0, 8, 4))
+	gray16.SetGray16(1, 1, color.Gray16{Y: 0x1234})
+
+	for name, test := range map[string]struct {
+		data      []byte
+		imageType string
+	}{
+		"gif": {encodeTestImage(t, paletted, func(buf *bytes.Buffer, img image.Image) error {
+			return gif.Encode(buf, img, nil)
+		}), "PNG"},
+		"bmp": {encodeTestImage(t, gradient(8, 4, 255), func(buf *bytes.Buffer, img image.Image) error {
+			return bmp.Encode(buf, img)
+		}), "PNG"},
+		"tiff": {encodeTestImage(t, gradient(8, 4, 128), func(buf *bytes.Buffer, img image.Image) error {
+			return tiff.Encode(buf, img, nil)
+		}), "PNG"},
+		"16 bit png": {encodeTestImage(t, gray16, func(buf *bytes.Buffer, img image.Image) error {
+			return png.Encode(buf, img)
+		}), "PNG"},
+		"webp": {webp, "PNG"},
+	} {
+		doc := NewA4()
+
+		data, imageType, err := doc.normalizeImage(test.data, 0, 0)
+		assert.NoError(t, err, name)
+		assert.Equal(t, test.imageType, imageType, name)
+
+		// gofpdf embeds the normalized image
+		encoded := base64.StdEncoding.EncodeToString(data)
+		_, _, err = doc.AddImage(&Image{Data: &encoded})
+		assert.NoError(t, err, name)
+
+		encoded = base64.StdEncoding.EncodeToString(test.data)
+		assert.NoError(t, doc.DrawImage(&Image{Data: &encoded}, 10, 10, 40, 20), name)
+	}
+}
+
+func TestNormalizeImageKeep(t *testing.T) {
+	doc := NewA4()
+
+	// embeddable images within the resolution are kept
+	raw := testPng(t)
+	data, imageType, err := doc.normalizeImage(raw, 10, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, "PNG", imageType)
+	assert.Equal(t, raw, data)
+
+	raw = encodeTestImage(t, gradient(20, 10, 255), func(buf *bytes.Buffer, img image.Image) error {
+		return jpeg.Encode(buf, img, nil)
+	})
+	data, imageType, err = doc.normalizeImage(raw, 0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "JPG", imageType)
+	assert.Equal(t, raw, data)
+}
+
+func TestNormalizeImageDownsample(t *testing.T) {
+	doc := NewA4()
+
+	// 25.4mm are 300 pixels with the default of 300 DPI
+	raw := encodeTestImage(t, gradient(1200, 600, 255), func(buf *bytes.Buffer, img image.Image) error {
+		return png.Encode(buf, img)
+	})
+	data, imageType, err := doc.normalizeImage(raw, 25.4, 12.7)
+	assert.NoError(t, err)
+	assert.Equal(t, "PNG", imageType)
+
+	config, _ := decodeConfig(t, data)
+	assert.Equal(t, []int{300, 150}, []int{config.Width, config.Height})
+	assert.Less(t, len(data), len(raw))
+
+	// photos stay JPEGs
+	raw = encodeTestImage(t, gradient(1200, 600, 255), func(buf *bytes.Buffer, img image.Image) error {
+		return jpeg.Encode(buf, img, nil)
+	})
+	data, imageType, err = doc.normalizeImage(raw, 25.4, 12.7)
+	assert.NoError(t, err)
+	assert.Equal(t, "JPG", imageType)
+
+	config, format := decodeConfig(t, data)
+	assert.Equal(t, "jpeg", format)
+	assert.Equal(t, 300, config.Width)
+
+	// without resolution the pixels are kept
+	doc = NewA4(WithImageNormalization(ImageNormalization{}))
+	data, _, err = doc.normalizeImage(raw, 25.4, 12.7)
+	assert.NoError(t, err)
+	assert.Equal(t, raw, data)
+}
+
+func TestNormalizeImageFlattenAlpha(t *testing.T) {
+	raw := encodeTestImage(t, gradient(8, 4, 0), func(buf *bytes.Buffer, img image.Image) error {
+		return png.Encode(buf, img)
+	})
+
+	// the alpha channel is kept by default
+	data, _, err := NewA4().normalizeImage(raw, 0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, raw, data)
+
+	doc := NewA4(WithImageNormalization(ImageNormalization{DPI: 300, FlattenAlpha: true}))
+	data, imageType, err := doc.normalizeImage(raw, 0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "PNG", imageType)
+
+	img, err := png.Decode(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.True(t, isOpaque(img))
+	assert.Equal(t, color.RGBA{255, 255, 255, 255}, color.RGBAModel.Convert(img.At(1, 1)))
+}
+
+func TestTo8Bit(t *testing.T) {
+	// lossy WebP with alpha decodes into NYCbCrA
+	ycbcr := image.NewNYCbCrA(image.Rect(0, 0, 4, 2), image.YCbCrSubsampleRatio420)
+
+	for _, img := range []image.Image{ycbcr, image.NewCMYK(image.Rect(0, 0, 4, 2)), image.NewGray16(image.Rect(0, 0, 4, 2)),
+		image.NewRGBA64(image.Rect(0, 0, 4, 2)), image.NewPaletted(image.Rect(0, 0, 4, 2), color.Palette{color.Black})} {
+		data := encodeTestImage(t, to8Bit(img), func(buf *bytes.Buffer, img image.Image) error {
+			return png.Encode(buf, img)
+		})
+
+		// the bit depth of the IHDR chunk
+		assert.LessOrEqual(t, data[24], uint8(8))
+	}
+}
+
+func TestNormalizeImageSize(t *testing.T) {
+	raw := testPng(t)
+
+	// the IHDR chunk claims 10000x10000 pixels
+	huge := append([]byte{}, raw...)
+	binary.BigEndian.PutUint32(huge[16:], 10000)
+	binary.BigEndian.PutUint32(huge[20:], 10000)
+	binary.BigEndian.PutUint32(huge[29:], crc32.ChecksumIEEE(huge[12:29]))
+
+	_, _, err := NewA4().normalizeImage(huge, 0, 0)
+	assert.ErrorIs(t, err, ErrImageSize)
+
+	_, _, err = NewA4().normalizeImage([]byte("no image"), 0, 0)
+	assert.ErrorIs(t, err, ErrImageFormat)
+}
+
+func TestDetectImageTypeFormats(t *testing.T) {
+	webp, _ := base64.StdEncoding.DecodeString(testWebp)
+
+	imageType, err := DetectImageType(webp)
+	assert.NoError(t, err)
+	assert.Equal(t, "WEBP", imageType)
+
+	imageType, err = DetectImageType(encodeTestImage(t, gradient(2, 2, 255), func(buf *bytes.Buffer, img image.Image) error {
+		return gif.Encode(buf, img, nil)
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, "GIF", imageType)
+}
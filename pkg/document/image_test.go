@@ -0,0 +1,104 @@
+package document
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func testImage(t *testing.T, encode func(*bytes.Buffer, image.Image) error) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	img.Set(1, 1, color.RGBA{R: 255, A: 255})
+
+	var buf bytes.Buffer
+	assert.NoError(t, encode(&buf, img))
+
+	return buf.Bytes()
+}
+
+func testPng(t *testing.T) []byte {
+	return testImage(t, func(buf *bytes.Buffer, img image.Image) error {
+		return png.Encode(buf, img)
+	})
+}
+
+type testAssets map[uuid.UUID][]byte
+
+func (a testAssets) Get(id uuid.UUID) ([]byte, bool) {
+	data, ok := a[id]
+	return data, ok
+}
+
+func TestDecodeImageData(t *testing.T) {
+	raw := testPng(t)
+	encoded := base64.StdEncoding.EncodeToString(raw)
+
+	data, err := decodeImageData(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, raw, data)
+
+	data, err = decodeImageData("data:image/png;base64," + encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, raw, data)
+
+	// wrapped and unpadded base64
+	data, err = decodeImageData(encoded[:20] + "\n" + base64.RawStdEncoding.EncodeToString(raw)[20:])
+	assert.NoError(t, err)
+	assert.Equal(t, raw, data)
+
+	_, err = decodeImageData("data:image/svg+xml,<svg/>")
+	assert.ErrorIs(t, err, ErrImageData)
+	_, err = decodeImageData("not base64!")
+	assert.ErrorIs(t, err, ErrImageData)
+}
+
+func TestDetectImageType(t *testing.T) {
+	imageType, err := DetectImageType(testPng(t))
+	assert.NoError(t, err)
+	assert.Equal(t, "PNG", imageType)
+
+	imageType, err = DetectImageType(testImage(t, func(buf *bytes.Buffer, img image.Image) error {
+		return jpeg.Encode(buf, img, nil)
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, "JPG", imageType)
+
+	_, err = DetectImageType([]byte("GIF89a"))
+	assert.ErrorIs(t, err, ErrImageFormat)
+}
+
+func TestAddImage(t *testing.T) {
+	raw := testPng(t)
+	encoded := base64.StdEncoding.EncodeToString(raw)
+	id := uuid.New()
+
+	doc := NewA4(WithAssets(testAssets{id: raw}))
+
+	name, info, err := doc.AddImage(&Image{Data: &encoded})
+	assert.NoError(t, err)
+	assert.Equal(t, 4., info.Width()/info.Height()*2)
+
+	// the same content has the same name
+	assetName, _, err := doc.AddImage(&Image{AssetID: &id})
+	assert.NoError(t, err)
+	assert.Equal(t, name, assetName)
+
+	missing := uuid.New()
+	_, _, err = doc.AddImage(&Image{AssetID: &missing})
+	assert.ErrorIs(t, err, ErrImageNotFound)
+
+	invalid := base64.StdEncoding.EncodeToString([]byte("no image"))
+	_, _, err = doc.AddImage(&Image{Data: &invalid})
+	assert.ErrorIs(t, err, ErrImageFormat)
+
+	// without asset source
+	_, _, err = NewA4().AddImage(&Image{AssetID: &id})
+	assert.ErrorIs(t, err, ErrImageNotFound)
+}
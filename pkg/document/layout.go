@@ -0,0 +1,163 @@
+package document
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+const (
+	LayoutTypeSN010130   LayoutType = "SN_010130"
+	LayoutTypeUSLetter10 LayoutType = "US_LETTER_10"
+	LayoutTypeWindowless LayoutType = "WINDOWLESS"
+)
+
+var ErrLayoutAlreadyRegistered = errors.New("the layout is already registered")
+
+// Area is a rectangle on the first page in mm, measured from the top left
+// corner of the page.
+type Area struct {
+	X      float64
+	Y      float64
+	Width  float64
+	Height float64
+}
+
+// PageSize is the width and height of the pages in mm.
+type PageSize struct {
+	Width  float64
+	Height float64
+}
+
+var (
+	PageSizeA4     = PageSize{Width: 210, Height: 297}
+	PageSizeLetter = PageSize{Width: 215.9, Height: 279.4}
+)
+
+// Layout describes the page size and where the header elements of the first
+// page are placed, e.g. to fit the window of an envelope.
+type Layout interface {
+	// PageSize is the size of the pages the positions are given for.
+	PageSize() PageSize
+	// AddressWindow is the area of the recipient address.
+	AddressWindow() Area
+	// InfoBlock is the area of the information block (invoice number, dates,
+	// ...).
+	InfoBlock() Area
+	// LogoArea is the area the logo is fitted into.
+	LogoArea() Area
+	// ContentStartY is the position of the content below the header.
+	ContentStartY() float64
+	// FoldMarks are the vertical positions of the fold marks, empty for
+	// layouts without folding.
+	FoldMarks() []float64
+}
+
+// StaticLayout is a Layout with fixed positions, the pages are A4 unless
+// the page size is given.
+type StaticLayout struct {
+	Page    PageSize
+	Address Area
+	Info    Area
+	Logo    Area
+	Content float64
+	Folds   []float64
+}
+
+func (l *StaticLayout) PageSize() PageSize {
+	if l.Page.Width <= 0 || l.Page.Height <= 0 {
+		return PageSizeA4
+	}
+	return l.Page
+}
+
+func (l *StaticLayout) AddressWindow() Area    { return l.Address }
+func (l *StaticLayout) InfoBlock() Area        { return l.Info }
+func (l *StaticLayout) LogoArea() Area         { return l.Logo }
+func (l *StaticLayout) ContentStartY() float64 { return l.Content }
+func (l *StaticLayout) FoldMarks() []float64   { return l.Folds }
+
+var (
+	layoutsMu sync.RWMutex
+	layouts   = map[LayoutType]Layout{
+		// DIN 5008 form A, address window of DL/C6 envelopes with 17.7 mm for
+		// the return address and remarks
+		LayoutTypeDIN5008A: &StaticLayout{
+			Address: Area{X: 25, Y: 27 + 17.57, Width: 80, Height: 27.3},
+			Info:    Area{X: 125, Y: 32, Width: 75, Height: 55},
+			Logo:    Area{X: 125, Y: 10, Width: 75, Height: 17},
+			Content: 98.5,
+			Folds:   []float64{87, 192},
+		},
+		// DIN 5008 form B, address window of DL/C6 envelopes
+		LayoutTypeDIN5008B: &StaticLayout{
+			Address: Area{X: 25, Y: 45 + 17.7, Width: 80, Height: 27.3},
+			Info:    Area{X: 125, Y: 50, Width: 75, Height: 45},
+			Logo:    Area{X: 125, Y: 10, Width: 75, Height: 35},
+			Content: 98.5,
+			Folds:   []float64{105, 210},
+		},
+		// SN 010130, swiss C5/C6 envelopes with the window on the right
+		LayoutTypeSN010130: &StaticLayout{
+			Address: Area{X: 118, Y: 60, Width: 80, Height: 27},
+			Info:    Area{X: 25, Y: 55, Width: 80, Height: 40},
+			Logo:    Area{X: 25, Y: 10, Width: 80, Height: 35},
+			Content: 105,
+			Folds:   []float64{99, 198},
+		},
+		// US #10 window envelope, the US letter is folded in thirds
+		LayoutTypeUSLetter10: &StaticLayout{
+			Page:    PageSizeLetter,
+			Address: Area{X: 22.2, Y: 50.8, Width: 101.6, Height: 25.4},
+			Info:    Area{X: 135, Y: 45, Width: 65, Height: 40},
+			Logo:    Area{X: 22.2, Y: 10, Width: 101.6, Height: 30},
+			Content: 95,
+			Folds:   []float64{93.1, 186.3},
+		},
+		// letters without envelope window, e.g. sent by email
+		LayoutTypeWindowless: &StaticLayout{
+			Address: Area{X: 25, Y: 40, Width: 80, Height: 30},
+			Info:    Area{X: 125, Y: 40, Width: 75, Height: 40},
+			Logo:    Area{X: 125, Y: 10, Width: 75, Height: 25},
+			Content: 85,
+		},
+	}
+)
+
+// RegisterLayout adds a layout to the registry, the name can be used as layout
+// of documents afterwards. Registering an existing name fails.
+func RegisterLayout(name LayoutType, layout Layout) error {
+	layoutsMu.Lock()
+	defer layoutsMu.Unlock()
+
+	if _, ok := layouts[name]; ok {
+		return ErrLayoutAlreadyRegistered
+	}
+
+	layouts[name] = layout
+	return nil
+}
+
+// GetLayout returns the registered layout with the given name.
+func GetLayout(name LayoutType) (Layout, bool) {
+	layoutsMu.RLock()
+	defer layoutsMu.RUnlock()
+
+	layout, ok := layouts[name]
+	return layout, ok
+}
+
+// LayoutTypes returns the names of all registered layouts sorted by name.
+func LayoutTypes() []LayoutType {
+	layoutsMu.RLock()
+	defer layoutsMu.RUnlock()
+
+	names := make([]LayoutType, 0, len(layouts))
+	for name := range layouts {
+		names = append(names, name)
+	}
+
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+
+	return names
+}
@@ -0,0 +1,41 @@
+package document
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayoutRegistry(t *testing.T) {
+	for _, name := range []LayoutType{LayoutTypeDIN5008A, LayoutTypeDIN5008B, LayoutTypeSN010130, LayoutTypeUSLetter10, LayoutTypeWindowless} {
+		layout, ok := GetLayout(name)
+		assert.True(t, ok, name)
+		assert.Greater(t, layout.ContentStartY(), layout.AddressWindow().Y, name)
+	}
+
+	custom := &StaticLayout{
+		Address: Area{X: 20, Y: 50, Width: 85, Height: 40},
+		Content: 100,
+		Folds:   []float64{99},
+	}
+
+	assert.NoError(t, RegisterLayout("TEST_LAYOUT", custom))
+	assert.ErrorIs(t, RegisterLayout("TEST_LAYOUT", custom), ErrLayoutAlreadyRegistered)
+	assert.ErrorIs(t, RegisterLayout(LayoutTypeDIN5008A, custom), ErrLayoutAlreadyRegistered)
+
+	layout, ok := GetLayout("TEST_LAYOUT")
+	assert.True(t, ok)
+	assert.Equal(t, []float64{99}, layout.FoldMarks())
+	assert.Contains(t, LayoutTypes(), LayoutType("TEST_LAYOUT"))
+
+	_, ok = GetLayout("UNKNOWN")
+	assert.False(t, ok)
+
+	// the layouts are given for A4 unless they have a page size
+	assert.Equal(t, PageSizeA4, layout.PageSize())
+	layout, _ = GetLayout(LayoutTypeUSLetter10)
+	assert.Equal(t, PageSizeLetter, layout.PageSize())
+
+	w, h := NewWithDefaults(layout.PageSize(), nil).GetPageSize()
+	assert.Equal(t, []float64{215.9, 279.4}, []float64{w, h})
+}
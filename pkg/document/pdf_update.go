@@ -0,0 +1,307 @@
+package document
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/md5"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"unicode/utf16"
+)
+
+var (
+	trailerSizeRegex = regexp.MustCompile(`/Size (\d+)`)
+	trailerRootRegex = regexp.MustCompile(`/Root (\d+) 0 R`)
+	trailerInfoRegex = regexp.MustCompile(`/Info (\d+) 0 R`)
+)
+
+// pdfUpdate appends an incremental update to a pdf written by gofpdf. This is
+// used for everything gofpdf cannot write itself (catalog entries like
+// /Metadata or /AF, associated files, output intents).
+//
+// Objects added or replaced are written after the original file followed by
+// their own cross-reference section and trailer pointing to the previous one.
+type pdfUpdate struct {
+	src []byte
+
+	size int
+	root int
+	info int
+	prev int
+
+	objects map[int][]byte
+}
+
+// newPdfUpdate parses the trailer of the given pdf.
+func newPdfUpdate(src []byte) (*pdfUpdate, error) {
+	startIdx := bytes.LastIndex(src, []byte("startxref"))
+	trailerIdx := bytes.LastIndex(src, []byte("trailer"))
+	if startIdx < 0 || trailerIdx < 0 || trailerIdx > startIdx {
+		return nil, fmt.Errorf("pdf update: no trailer found")
+	}
+
+	prevStr := bytes.Fields(src[startIdx+len("startxref"):])
+	if len(prevStr) == 0 {
+		return nil, fmt.Errorf("pdf update: no startxref found")
+	}
+
+	prev, err := strconv.Atoi(string(prevStr[0]))
+	if err != nil {
+		return nil, fmt.Errorf("pdf update: invalid startxref: %w", err)
+	}
+
+	trailer := src[trailerIdx:startIdx]
+
+	u := &pdfUpdate{
+		src:     src,
+		prev:    prev,
+		objects: make(map[int][]byte),
+	}
+
+	for _, v := range []struct {
+		regex *regexp.Regexp
+		trg   *int
+	}{
+		{trailerSizeRegex, &u.size},
+		{trailerRootRegex, &u.root},
+		{trailerInfoRegex, &u.info},
+	} {
+		match := v.regex.FindSubmatch(trailer)
+		if match == nil {
+			return nil, fmt.Errorf("pdf update: trailer does not match %v", v.regex)
+		}
+		*v.trg, _ = strconv.Atoi(string(match[1]))
+	}
+
+	return u, nil
+}
+
+// objectOffset reads the offset of object n from the cross-reference table of
+// the original file. gofpdf always writes one subsection starting at 0.
+func (u *pdfUpdate) objectOffset(n int) (int, error) {
+	xref := u.src[u.prev:]
+
+	// skip "xref" and the subsection header
+	lines := bytes.SplitN(xref, []byte("\n"), 3)
+	if len(lines) < 3 || !bytes.Equal(bytes.TrimSpace(lines[0]), []byte("xref")) {
+		return 0, fmt.Errorf("pdf update: no cross-reference table at %v", u.prev)
+	}
+
+	entries := lines[2]
+	// every entry has a fixed length of 20 bytes
+	if len(entries) < (n+1)*20 {
+		return 0, fmt.Errorf("pdf update: object %v not in cross-reference table", n)
+	}
+
+	return strconv.Atoi(string(entries[n*20 : n*20+10]))
+}
+
+// objectBody returns the content of object n between "obj" and "endobj".
+func (u *pdfUpdate) objectBody(n int) ([]byte, error) {
+	offset, err := u.objectOffset(n)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := u.src[offset:]
+
+	start := bytes.Index(obj, []byte("obj"))
+	end := bytes.Index(obj, []byte("endobj"))
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("pdf update: object %v is malformed", n)
+	}
+
+	return bytes.TrimSpace(obj[start+len("obj") : end]), nil
+}
+
+// add appends a new object and returns its object number.
+func (u *pdfUpdate) add(body []byte) int {
+	n := u.size
+	u.size++
+	u.objects[n] = body
+	return n
+}
+
+// set replaces the object n of the original file.
+func (u *pdfUpdate) set(n int, body []byte) {
+	u.objects[n] = body
+}
+
+// WriteTo writes the original file followed by the incremental update.
+func (u *pdfUpdate) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	buf.Write(u.src)
+	if !bytes.HasSuffix(u.src, []byte("\n")) {
+		buf.WriteString("\n")
+	}
+
+	numbers := make([]int, 0, len(u.objects))
+	for n := range u.objects {
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+
+	offsets := make(map[int]int)
+	for _, n := range numbers {
+		offsets[n] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n", n)
+		buf.Write(u.objects[n])
+		buf.WriteString("\nendobj\n")
+	}
+
+	xref := buf.Len()
+	buf.WriteString("xref\n")
+	for _, n := range numbers {
+		fmt.Fprintf(&buf, "%d 1\n%010d 00000 n \n", n, offsets[n])
+	}
+
+	id := md5.Sum(u.src)
+
+	buf.WriteString("trailer\n<<\n")
+	fmt.Fprintf(&buf, "/Size %d\n", u.size)
+	fmt.Fprintf(&buf, "/Root %d 0 R\n", u.root)
+	fmt.Fprintf(&buf, "/Info %d 0 R\n", u.info)
+	fmt.Fprintf(&buf, "/Prev %d\n", u.prev)
+	fmt.Fprintf(&buf, "/ID [<%x><%x>]\n", id, id)
+	buf.WriteString(">>\n")
+	fmt.Fprintf(&buf, "startxref\n%d\n%%%%EOF\n", xref)
+
+	return buf.WriteTo(w)
+}
+
+// rewriteHeader replaces the header line of a pdf written by gofpdf, e.g. to
+// raise the version or to add the binary comment required by PDF/A. All
+// offsets of the cross-reference table are moved accordingly.
+func rewriteHeader(src []byte, header string) ([]byte, error) {
+	eol := bytes.IndexByte(src, '\n')
+	if !bytes.HasPrefix(src, []byte("%PDF-")) || eol < 0 {
+		return nil, fmt.Errorf("pdf update: no header found")
+	}
+	delta := len(header) - eol
+
+	startIdx := bytes.LastIndex(src, []byte("startxref"))
+	if startIdx < 0 {
+		return nil, fmt.Errorf("pdf update: no startxref found")
+	}
+	fields := bytes.Fields(src[startIdx+len("startxref"):])
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("pdf update: no startxref found")
+	}
+	xref, err := strconv.Atoi(string(fields[0]))
+	if err != nil {
+		return nil, fmt.Errorf("pdf update: invalid startxref: %w", err)
+	}
+
+	// gofpdf always writes one subsection starting at 0
+	lines := bytes.SplitN(src[xref:], []byte("\n"), 3)
+	if len(lines) < 3 || !bytes.Equal(bytes.TrimSpace(lines[0]), []byte("xref")) {
+		return nil, fmt.Errorf("pdf update: no cross-reference table at %v", xref)
+	}
+	subsection := bytes.Fields(lines[1])
+	if len(subsection) != 2 {
+		return nil, fmt.Errorf("pdf update: invalid cross-reference subsection")
+	}
+	count, err := strconv.Atoi(string(subsection[1]))
+	if err != nil {
+		return nil, fmt.Errorf("pdf update: invalid cross-reference subsection: %w", err)
+	}
+	entries := xref + len(lines[0]) + len(lines[1]) + 2
+	if len(src) < entries+count*20 {
+		return nil, fmt.Errorf("pdf update: cross-reference table is truncated")
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(header)
+	buf.Write(src[eol:startIdx])
+	fmt.Fprintf(&buf, "startxref\n%d\n%%%%EOF\n", xref+delta)
+
+	out := buf.Bytes()
+	for i := 0; i < count; i++ {
+		// every entry has a fixed length of 20 bytes, only objects in use
+		// have an offset
+		entry := out[entries+delta+i*20:]
+		if entry[17] != 'n' {
+			continue
+		}
+
+		offset, err := strconv.Atoi(string(entry[:10]))
+		if err != nil {
+			return nil, fmt.Errorf("pdf update: invalid cross-reference entry %v: %w", i, err)
+		}
+		copy(entry, fmt.Sprintf("%010d", offset+delta))
+	}
+
+	return out, nil
+}
+
+// pdfStream creates a stream object from the given dictionary entries and
+// data. When compress is set the data will be flate encoded.
+func pdfStream(dict string, data []byte, compress bool) []byte {
+	if compress {
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		zw.Write(data)
+		zw.Close()
+
+		data = compressed.Bytes()
+		dict += " /Filter /FlateDecode"
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<< %s /Length %d >>\nstream\n", dict, len(data))
+	buf.Write(data)
+	buf.WriteString("\nendstream")
+	return buf.Bytes()
+}
+
+// pdfTextString encodes s as pdf text string. Strings with characters outside
+// of ASCII are written as UTF-16BE hex string with byte order mark.
+func pdfTextString(s string) string {
+	ascii := true
+	for _, r := range s {
+		if r > 126 || r < 32 {
+			ascii = false
+			break
+		}
+	}
+
+	if ascii {
+		var buf bytes.Buffer
+		buf.WriteByte('(')
+		for i := 0; i < len(s); i++ {
+			switch s[i] {
+			case '(', ')', '\\':
+				buf.WriteByte('\\')
+			}
+			buf.WriteByte(s[i])
+		}
+		buf.WriteByte(')')
+		return buf.String()
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("<FEFF")
+	for _, c := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&buf, "%04X", c)
+	}
+	buf.WriteByte('>')
+	return buf.String()
+}
+
+// pdfName encodes s as pdf name, e.g. "text/xml" becomes "/text#2Fxml".
+func pdfName(s string) string {
+	var buf bytes.Buffer
+	buf.WriteByte('/')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < '!' || c > '~' || bytes.IndexByte([]byte("#()<>[]{}/%"), c) >= 0 {
+			fmt.Fprintf(&buf, "#%02X", c)
+			continue
+		}
+		buf.WriteByte(c)
+	}
+	return buf.String()
+}
@@ -0,0 +1,528 @@
+package document
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Rich texts are formatted with a subset of Markdown:
+//
+//   - **bold**
+//
+//   - *italic* or _italic_
+//
+//   - [text](https://example.com) for links, the targets are limited to http,
+//     https and mailto, the text of other links is printed without a link
+//
+//   - lines starting with "- " or "* " are bullet points
+//
+// Every line break starts a new line. Markers without closing marker are
+// printed as they are, as well as characters escaped by a backslash, see
+// EscapeRichText.
+
+// richEscapable are the characters which are printed as they are after a
+// backslash.
+const richEscapable = `\*_[]-`
+
+// richBullet is printed in front of the lines of a bullet point.
+const richBullet = "•"
+
+// richRun is a part of a paragraph with the same style.
+type richRun struct {
+	text string
+	// bold "B", italic "I" or both "BI"
+	style string
+	link  string
+}
+
+// richParagraph is a line of the text, which is wrapped into richLines.
+type richParagraph struct {
+	runs   []richRun
+	bullet bool
+}
+
+// richSegment is the part of a richLine printed with one cell.
+type richSegment struct {
+	text  string
+	style string
+	link  string
+	width float64
+}
+
+// richLine is a wrapped line of a paragraph, the lines of bullet points are
+// indented.
+type richLine struct {
+	segments []richSegment
+	width    float64
+	indent   float64
+	// bullet is set for the first line of a bullet point
+	bullet bool
+}
+
+// EscapeRichText escapes the markup characters of a text, so it is printed as
+// it is by RichText.
+func EscapeRichText(txt string) string {
+	var sb strings.Builder
+
+	lineStart := true
+	for _, r := range txt {
+		if strings.ContainsRune(richEscapable, r) && (r != '-' || lineStart) {
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(r)
+
+		lineStart = r == '\n'
+	}
+
+	return sb.String()
+}
+
+// PlainRichText removes the markup of a text, e.g. for electronic invoices.
+// Links keep their label, bullet points start with "- ".
+func PlainRichText(txt string) string {
+	paragraphs := parseRichText(txt)
+	lines := make([]string, 0, len(paragraphs))
+
+	for _, paragraph := range paragraphs {
+		var sb strings.Builder
+		if paragraph.bullet {
+			sb.WriteString("- ")
+		}
+		for _, run := range paragraph.runs {
+			sb.WriteString(run.text)
+		}
+		lines = append(lines, sb.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// RichText prints a text with markup (see EscapeRichText) like MCell: the
+// lines are wrapped inside the width w starting at the current position, 0
+// uses the width up to the right margin. Only the horizontal alignment of
+// alignStr is used, justified lines are aligned left. The text breaks over
+// pages, afterwards the position is at the left margin below the text.
+//
+// Right-to-left documents print the runs of a line from right to left, the
+// bullet points on the right side.
+func (d *Doc) RichText(w, h float64, txtStr, alignStr string) {
+	d.richText(w, h, d.prepareText(txtStr), d.mirrorAlign(alignStr))
+}
+
+// CountRichTextLines returns the number of lines RichText prints for the text
+// within the width with the current font.
+func (d *Doc) CountRichTextLines(txtStr string, w float64) int {
+	return len(d.layoutRichText(parseRichText(d.prepareText(txtStr)), w))
+}
+
+// richText prints texts which are already prepared by prepareText.
+func (d *Doc) richText(w, h float64, txtStr, alignStr string) {
+	if w == 0 {
+		pw, _ := d.GetPageSize()
+		_, _, r, _ := d.GetMargins()
+		w = pw - r - d.GetX()
+	}
+
+	font := d.GetFont()
+	defer d.SetFontSpec(font)
+
+	x := d.GetX()
+	bullet := d.prepareText(richBullet)
+
+	for _, line := range d.layoutRichText(parseRichText(txtStr), w) {
+		offset := 0.
+		switch free := w - line.indent - line.width; {
+		case strings.Contains(alignStr, "R"):
+			offset = free
+		case strings.Contains(alignStr, "C"):
+			offset = free / 2
+		}
+
+		segments := line.segments
+		if d.IsRTL() {
+			segments = make([]richSegment, len(line.segments))
+			for i, segment := range line.segments {
+				segments[len(segments)-1-i] = segment
+			}
+		} else {
+			offset += line.indent
+		}
+
+		//the first cell breaks the page if needed, empty lines included
+		d.SetX(x + offset)
+		if len(segments) == 0 {
+			d.CellFormat(w-offset, h, "", "", 0, "", false, 0, "")
+		}
+
+		for _, segment := range segments {
+			d.SetFont("", richStyle(font.Style, segment.style, len(segment.link) > 0), 0)
+			d.CellFormat(segment.width, h, d.visualLine(segment.text), "", 0, "", false, 0, segment.link)
+		}
+
+		if line.bullet {
+			d.SetFont("", font.Style, 0)
+			if d.IsRTL() {
+				d.CellFormat(line.indent, h, bullet, "", 0, "R", false, 0, "")
+			} else {
+				d.SetX(x + offset - line.indent)
+				d.CellFormat(line.indent, h, bullet, "", 0, "", false, 0, "")
+			}
+		}
+
+		d.Ln(h)
+	}
+}
+
+// richStyle combines the style of the font with the style of a run, links are
+// underlined.
+func richStyle(fontStyle, runStyle string, link bool) string {
+	style := ""
+	for _, s := range "BI" {
+		if strings.ContainsRune(fontStyle, s) || strings.ContainsRune(runStyle, s) {
+			style += string(s)
+		}
+	}
+
+	if link || strings.Contains(fontStyle, "U") {
+		style += "U"
+	}
+
+	return style
+}
+
+// parseRichText splits a text into paragraphs of runs.
+func parseRichText(txt string) []richParagraph {
+	lines := strings.Split(txt, "\n")
+	paragraphs := make([]richParagraph, 0, len(lines))
+
+	for _, line := range lines {
+		paragraph := richParagraph{}
+
+		trimmed := strings.TrimLeft(line, " ")
+		if strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* ") {
+			paragraph.bullet = true
+			line = strings.TrimLeft(trimmed[2:], " ")
+		}
+
+		paragraph.runs = parseRichRuns(line)
+		paragraphs = append(paragraphs, paragraph)
+	}
+
+	return paragraphs
+}
+
+// parseRichRuns splits a line into runs by the markers. All markers are ASCII,
+// so the text is processed byte by byte, which works for UTF-8 and the code
+// pages of the core fonts.
+func parseRichRuns(line string) []richRun {
+	runs := make([]richRun, 0)
+
+	var text strings.Builder
+	bold := false
+	var italic byte
+
+	style := func() string {
+		style := ""
+		if bold {
+			style += "B"
+		}
+		if italic != 0 {
+			style += "I"
+		}
+		return style
+	}
+
+	flush := func() {
+		if text.Len() == 0 {
+			return
+		}
+
+		runs = append(runs, richRun{text: text.String(), style: style()})
+		text.Reset()
+	}
+
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+
+		switch {
+		case c == '\\' && i+1 < len(line) && strings.IndexByte(richEscapable, line[i+1]) >= 0:
+			i++
+			text.WriteByte(line[i])
+
+		case c == '*' && strings.HasPrefix(line[i:], "**") && (bold || opensRichMarker(line, i, "**")):
+			flush()
+			bold = !bold
+			i++
+
+		case (c == '*' || c == '_') && italic == c && closesRichMarker(line, i, 1):
+			flush()
+			italic = 0
+
+		case (c == '*' || c == '_') && italic == 0 && opensRichMarker(line, i, string(c)):
+			flush()
+			italic = c
+
+		case c == '[':
+			label, link, n := parseRichLink(line[i:])
+			if n == 0 {
+				text.WriteByte(c)
+				continue
+			}
+
+			//the label of a link which is not allowed is plain text
+			if link == "" {
+				text.WriteString(label)
+				i += n - 1
+				continue
+			}
+
+			flush()
+			runs = append(runs, richRun{text: label, style: style(), link: link})
+			i += n - 1
+
+		default:
+			text.WriteByte(c)
+		}
+	}
+
+	flush()
+
+	return runs
+}
+
+// opensRichMarker reports whether the marker at position i opens a styled run:
+// it is followed by text and closed later in the line. Underscores inside of
+// words (snake_case) are no markers.
+func opensRichMarker(line string, i int, marker string) bool {
+	start := i + len(marker)
+	if start >= len(line) || line[start] == ' ' {
+		return false
+	}
+
+	if marker == "_" && i > 0 && isRichWordChar(line[i-1]) {
+		return false
+	}
+
+	for j := start + 1; j < len(line); j++ {
+		if line[j] == '\\' {
+			j++
+			continue
+		}
+
+		if strings.HasPrefix(line[j:], marker) && closesRichMarker(line, j, len(marker)) {
+			//a single star must not be part of a double star
+			if marker == "*" && (line[j-1] == '*' || (j+1 < len(line) && line[j+1] == '*')) {
+				continue
+			}
+			return true
+		}
+	}
+
+	return false
+}
+
+// closesRichMarker reports whether the marker of length n at position i closes
+// a styled run: it follows text and an underscore is not inside of a word.
+func closesRichMarker(line string, i, n int) bool {
+	if i == 0 || line[i-1] == ' ' {
+		return false
+	}
+
+	if line[i] == '_' && i+n < len(line) && isRichWordChar(line[i+n]) {
+		return false
+	}
+
+	return true
+}
+
+// isRichWordChar reports whether the byte is part of a word, bytes of non-ASCII
+// characters are always part of a word.
+func isRichWordChar(c byte) bool {
+	return c >= 0x80 || c == '_' ||
+		(c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// richLinkSchemes are the allowed schemes of link targets, other targets could
+// run scripts or open local files in the pdf viewer.
+var richLinkSchemes = []string{"http://", "https://", "mailto:"}
+
+// parseRichLink parses a link [label](url) at the beginning of the text and
+// returns the length of the markup, 0 if it is no link. The url may contain
+// balanced parentheses, the link is empty if its scheme is not allowed.
+func parseRichLink(txt string) (label, link string, n int) {
+	end := strings.Index(txt, "](")
+	if end < 0 {
+		return "", "", 0
+	}
+
+	close, depth := -1, 0
+	for i := end + 2; i < len(txt) && close < 0; i++ {
+		switch txt[i] {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				close = i
+			}
+			depth--
+		}
+	}
+	if close < 0 {
+		return "", "", 0
+	}
+
+	label = txt[1:end]
+	link = strings.TrimSpace(txt[end+2 : close])
+	if len(label) == 0 || len(link) == 0 || strings.ContainsAny(link, " ") {
+		return "", "", 0
+	}
+
+	if !isRichLinkAllowed(link) {
+		link = ""
+	}
+
+	return label, link, close + 1
+}
+
+// isRichLinkAllowed reports whether the scheme of the link is allowed.
+func isRichLinkAllowed(link string) bool {
+	for _, scheme := range richLinkSchemes {
+		if len(link) > len(scheme) && strings.EqualFold(link[:len(scheme)], scheme) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// layoutRichText wraps the paragraphs into lines of the width with the current
+// font, words which are wider than the line are broken.
+func (d *Doc) layoutRichText(paragraphs []richParagraph, w float64) []richLine {
+	font := d.GetFont()
+	defer d.SetFontSpec(font)
+
+	d.SetFont("", font.Style, 0)
+	indent := d.GetStringWidth(d.prepareText(richBullet + " "))
+
+	//width of a space per style
+	spaces := make(map[string]float64)
+	spaceWidth := func(style string) float64 {
+		if width, ok := spaces[style]; ok {
+			return width
+		}
+
+		current := d.GetFont()
+		d.SetFont("", style, 0)
+		spaces[style] = d.GetStringWidth(" ")
+		d.SetFontSpec(current)
+
+		return spaces[style]
+	}
+
+	lines := make([]richLine, 0)
+
+	for _, paragraph := range paragraphs {
+		line := richLine{bullet: paragraph.bullet}
+		if paragraph.bullet {
+			line.indent = indent
+		}
+		available := w - line.indent
+		pendingSpace := false
+
+		push := func() {
+			lines = append(lines, line)
+			line = richLine{indent: line.indent}
+			pendingSpace = false
+		}
+
+		add := func(word string, style, link string, width float64) {
+			if len(line.segments) > 0 && pendingSpace {
+				last := &line.segments[len(line.segments)-1]
+
+				if last.style == style && last.link == link {
+					space := spaceWidth(style)
+					last.text += " " + word
+					last.width += space + width
+					line.width += space + width
+					return
+				}
+
+				//the space is not underlined with the link
+				if len(last.link) == 0 {
+					space := spaceWidth(last.style)
+					last.text += " "
+					last.width += space
+					line.width += space
+				} else {
+					space := spaceWidth(style)
+					word = " " + word
+					width += space
+				}
+			}
+
+			line.segments = append(line.segments, richSegment{text: word, style: style, link: link, width: width})
+			line.width += width
+			pendingSpace = false
+		}
+
+		for _, run := range paragraph.runs {
+			style := richStyle(font.Style, run.style, len(run.link) > 0)
+			d.SetFont("", style, 0)
+
+			words := strings.Split(run.text, " ")
+			for k, word := range words {
+				if k > 0 {
+					pendingSpace = true
+				}
+				if len(word) == 0 {
+					continue
+				}
+
+				width := d.GetStringWidth(word)
+
+				space := 0.
+				if pendingSpace && len(line.segments) > 0 {
+					space = spaceWidth(style)
+				}
+
+				if line.width+space+width > available && len(line.segments) > 0 {
+					push()
+				}
+
+				//break words which are wider than the line
+				for width > available {
+					head, tail := d.splitRichWord(word, available)
+					add(head, style, run.link, d.GetStringWidth(head))
+					push()
+
+					word = tail
+					width = d.GetStringWidth(word)
+				}
+
+				add(word, style, run.link, width)
+			}
+		}
+
+		push()
+	}
+
+	return lines
+}
+
+// splitRichWord splits a word at the last character fitting into the width,
+// the head contains at least one character.
+func (d *Doc) splitRichWord(word string, w float64) (head, tail string) {
+	size := 1
+	for i := 0; i < len(word); i += size {
+		size = 1
+		if d.unicode {
+			_, size = utf8.DecodeRuneInString(word[i:])
+		}
+
+		if i > 0 && d.GetStringWidth(word[:i+size]) > w {
+			return word[:i], word[i:]
+		}
+	}
+
+	return word, ""
+}
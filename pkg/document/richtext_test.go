@@ -0,0 +1,158 @@
+package document
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRichRuns(t *testing.T) {
+	assert.Equal(t, []richRun{
+		{text: "plain "},
+		{text: "bold", style: "B"},
+		{text: " and "},
+		{text: "italic", style: "I"},
+		{text: " "},
+		{text: "too", style: "I"},
+	}, parseRichRuns("plain **bold** and *italic* _too_"))
+
+	// nested styles
+	assert.Equal(t, []richRun{
+		{text: "bold ", style: "B"},
+		{text: "both", style: "BI"},
+	}, parseRichRuns("**bold _both_**"))
+
+	// links keep the style around them
+	assert.Equal(t, []richRun{
+		{text: "see "},
+		{text: "terms", style: "B", link: "https://example.com/terms"},
+	}, parseRichRuns("see **[terms](https://example.com/terms)**"))
+
+	// parentheses of the url are kept if they are balanced
+	assert.Equal(t, []richRun{
+		{text: "wiki", link: "https://en.wikipedia.org/wiki/Invoice_(disambiguation)"},
+		{text: ")"},
+	}, parseRichRuns("[wiki](https://en.wikipedia.org/wiki/Invoice_(disambiguation)))"))
+	assert.Equal(t, []richRun{{text: "mail", link: "mailto:office@example.com"}},
+		parseRichRuns("[mail](mailto:office@example.com)"))
+
+	// links with other schemes are printed as plain text
+	assert.Equal(t, []richRun{{text: "click here or there"}},
+		parseRichRuns("click [here](javascript:alert(1)) or [there](file:///etc/passwd)"))
+	assert.Equal(t, []richRun{{text: "relative"}}, parseRichRuns("[relative](/terms)"))
+
+	// markers without closing marker, inside of words or escaped are printed
+	assert.Equal(t, []richRun{{text: "5 * 3 = 15"}}, parseRichRuns("5 * 3 = 15"))
+	assert.Equal(t, []richRun{{text: "**open"}}, parseRichRuns("**open"))
+	assert.Equal(t, []richRun{{text: "snake_case_name"}}, parseRichRuns("snake_case_name"))
+	assert.Equal(t, []richRun{{text: "*not italic* [no](link)"}}, parseRichRuns(`\*not italic\* \[no](link)`))
+	assert.Equal(t, []richRun{{text: "[no link](with space)"}}, parseRichRuns("[no link](with space)"))
+}
+
+func TestParseRichText(t *testing.T) {
+	paragraphs := parseRichText("Items:\n- first\n  * second\n-no bullet\n\\- escaped")
+
+	assert.Len(t, paragraphs, 5)
+	assert.False(t, paragraphs[0].bullet)
+	assert.Equal(t, []richRun{{text: "first"}}, paragraphs[1].runs)
+	assert.True(t, paragraphs[1].bullet)
+	assert.Equal(t, []richRun{{text: "second"}}, paragraphs[2].runs)
+	assert.True(t, paragraphs[2].bullet)
+	assert.Equal(t, []richRun{{text: "-no bullet"}}, paragraphs[3].runs)
+	assert.False(t, paragraphs[3].bullet)
+	assert.Equal(t, []richRun{{text: "- escaped"}}, paragraphs[4].runs)
+	assert.False(t, paragraphs[4].bullet)
+}
+
+func TestEscapeRichText(t *testing.T) {
+	txt := "- *Star* [GmbH]\nsnake_case - 5 * 3"
+
+	escaped := EscapeRichText(txt)
+	assert.Equal(t, "\\- \\*Star\\* \\[GmbH\\]\nsnake\\_case - 5 \\* 3", escaped)
+
+	paragraphs := parseRichText(escaped)
+	assert.Equal(t, []richRun{{text: "- *Star* [GmbH]"}}, paragraphs[0].runs)
+	assert.Equal(t, []richRun{{text: "snake_case - 5 * 3"}}, paragraphs[1].runs)
+}
+
+func TestPlainRichText(t *testing.T) {
+	assert.Equal(t, "Thank you, pay within 14 days:\n- see terms\n- 5 * 3",
+		PlainRichText("Thank you, **pay** within _14 days_:\n* see [terms](https://example.com)\n- 5 * 3"))
+}
+
+func TestLayoutRichText(t *testing.T) {
+	doc := NewA4()
+
+	// the space between the words is part of the first segment
+	lines := doc.layoutRichText(parseRichText("a **b** c"), 100)
+	assert.Len(t, lines, 1)
+	assert.Equal(t, []string{"a ", "b ", "c"}, segmentTexts(lines[0]))
+
+	// lines are wrapped by words, long words are broken
+	lines = doc.layoutRichText(parseRichText("aaa bbb "+strings.Repeat("c", 20)), doc.GetStringWidth("aaa bbb"))
+	assert.Len(t, lines, 4)
+	assert.Equal(t, []string{"aaa bbb"}, segmentTexts(lines[0]))
+	for _, line := range lines {
+		assert.LessOrEqual(t, line.width, doc.GetStringWidth("aaa bbb")+0.001)
+	}
+
+	// bullet points are indented, empty lines are kept
+	lines = doc.layoutRichText(parseRichText("- item\n\nend"), 100)
+	assert.Len(t, lines, 3)
+	assert.True(t, lines[0].bullet)
+	assert.Greater(t, lines[0].indent, 0.)
+	assert.Empty(t, lines[1].segments)
+	assert.Equal(t, 0., lines[2].indent)
+
+	assert.Equal(t, 3, doc.CountRichTextLines("- item\n\nend", 100))
+}
+
+func segmentTexts(line richLine) []string {
+	texts := make([]string, 0)
+	for _, segment := range line.segments {
+		texts = append(texts, segment.text)
+	}
+	return texts
+}
+
+func TestRichText(t *testing.T) {
+	doc := NewA4()
+	doc.SetFont(FontFamilyDejaVu, "", 10)
+
+	txt := "Thank you for your order, **payment within 14 days** without _any_ deduction.\n" +
+		"- see our [terms](https://example.com/terms)\n- *questions?* call us\n\nBest regards"
+
+	doc.RichText(80, 5, txt, "L")
+	doc.RichText(0, 5, txt, "R")
+
+	// the font is restored afterwards
+	assert.Equal(t, FontSpec{Family: FontFamilyDejaVu, Style: "", Size: 10}, doc.GetFont())
+
+	// breaks over pages
+	doc.SetY(270)
+	doc.RichText(0, 5, strings.Repeat(txt+"\n", 3), "L")
+	assert.Equal(t, 2, doc.PageNo())
+
+	table, _ := NewDocTable(doc, [][]string{{"Name", "Amount"}, {"Suit\n- **3 pieces**\n- _blue_", "1"}})
+	table.SetHeadType(HeadFirstRow)
+	table.SetColTypes([]ColumnType{ColDyn, ColCalc})
+	table.SetCellTypesPerColumn([]CellType{CellRich, CellSingle})
+	assert.NoError(t, table.Generate())
+
+	var buf bytes.Buffer
+	assert.NoError(t, doc.Output(&buf))
+	assert.Contains(t, buf.String(), "/URI (https://example.com/terms)")
+}
+
+func TestRichTextPdfA3b(t *testing.T) {
+	doc := NewA4(WithConformance(ConformancePdfA3b), WithDirection(DirectionRTL))
+	doc.RichText(0, 5, "**שלום** [example](https://example.com)\n- עולם", "L")
+
+	var buf bytes.Buffer
+	assert.NoError(t, doc.Output(&buf))
+
+	// link annotations need the print flag
+	assert.Contains(t, buf.String(), "/Subtype /Link /F 4 ")
+}
@@ -0,0 +1,1354 @@
+package document
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// svgNamespace is the namespace of the drawn elements, elements of other
+// namespaces, e.g. the editor data of Inkscape, are ignored.
+const svgNamespace = "http://www.w3.org/2000/svg"
+
+// SvgError lists the features of an SVG image which cannot be drawn as vector
+// paths, see Image.Fallback.
+type SvgError struct {
+	Unsupported []string
+}
+
+func (e *SvgError) Error() string {
+	return "unsupported svg features: " + strings.Join(e.Unsupported, ", ")
+}
+
+type svgPoint struct {
+	X, Y float64
+}
+
+// svgSegment is a part of a path: 'M' and 'L' use the first point, 'C' is a
+// cubic Bézier curve with both control points and the end point, 'Z' closes
+// the sub path.
+type svgSegment struct {
+	op  byte
+	pts [3]svgPoint
+}
+
+// svgShape is a path in the coordinates of the viewBox with its paint, fill
+// and stroke are nil when not painted
+type svgShape struct {
+	path []svgSegment
+
+	fill      *Color
+	fillAlpha float64
+	evenOdd   bool
+
+	stroke      *Color
+	strokeAlpha float64
+	strokeWidth float64
+	cap         string
+	join        string
+	dash        []float64
+	dashPhase   float64
+}
+
+// svgImage contains the shapes in drawing order
+type svgImage struct {
+	// minX, minY, width and height of the viewBox
+	viewBox [4]float64
+	shapes  []svgShape
+}
+
+func (img *svgImage) ratio() float64 {
+	return img.viewBox[2] / img.viewBox[3]
+}
+
+// svgMatrix is the affine transformation (a b c d e f) mapping (x, y) to
+// (a*x + c*y + e, b*x + d*y + f)
+type svgMatrix [6]float64
+
+var svgIdentity = svgMatrix{1, 0, 0, 1, 0, 0}
+
+// multiply returns the transformation applying n first and then m
+func (m svgMatrix) multiply(n svgMatrix) svgMatrix {
+	return svgMatrix{
+		m[0]*n[0] + m[2]*n[1],
+		m[1]*n[0] + m[3]*n[1],
+		m[0]*n[2] + m[2]*n[3],
+		m[1]*n[2] + m[3]*n[3],
+		m[0]*n[4] + m[2]*n[5] + m[4],
+		m[1]*n[4] + m[3]*n[5] + m[5],
+	}
+}
+
+func (m svgMatrix) apply(p svgPoint) svgPoint {
+	return svgPoint{m[0]*p.X + m[2]*p.Y + m[4], m[1]*p.X + m[3]*p.Y + m[5]}
+}
+
+// scale is the mean scale of lengths, used for the stroke width
+func (m svgMatrix) scale() float64 {
+	return math.Sqrt(math.Abs(m[0]*m[3] - m[1]*m[2]))
+}
+
+// svgPaint is the fill or stroke, current uses the color property
+type svgPaint struct {
+	none    bool
+	current bool
+	color   Color
+}
+
+// svgStyle contains the inherited properties and the transformation into the
+// coordinates of the viewBox
+type svgStyle struct {
+	fill          svgPaint
+	fillOpacity   float64
+	evenOdd       bool
+	stroke        svgPaint
+	strokeOpacity float64
+	strokeWidth   float64
+	cap           string
+	join          string
+	dash          []float64
+	dashPhase     float64
+	color         Color
+	hidden        bool
+
+	// opacity of the groups multiplied, the opacity of a group is applied to
+	// its shapes one by one
+	opacity   float64
+	transform svgMatrix
+}
+
+var svgInitialStyle = svgStyle{
+	fill:          svgPaint{color: ColorBlack},
+	fillOpacity:   1,
+	stroke:        svgPaint{none: true},
+	strokeOpacity: 1,
+	strokeWidth:   1,
+	cap:           "butt",
+	join:          "miter",
+	color:         ColorBlack,
+	opacity:       1,
+	transform:     svgIdentity,
+}
+
+// svgProperties are the presentation attributes which are read, the
+// properties of svgUnsupportedProperties are reported unless they are none
+var svgProperties = []string{
+	"fill", "fill-opacity", "fill-rule", "stroke", "stroke-opacity", "stroke-width",
+	"stroke-linecap", "stroke-linejoin", "stroke-dasharray", "stroke-dashoffset",
+	"color", "display", "visibility", "opacity",
+	"clip-path", "mask", "filter", "marker-start", "marker-mid", "marker-end",
+}
+
+var svgUnsupportedProperties = map[string]bool{
+	"clip-path":    true,
+	"mask":         true,
+	"filter":       true,
+	"marker-start": true,
+	"marker-mid":   true,
+	"marker-end":   true,
+}
+
+// svgIgnoredElements contain no drawing, the contents of defs are only drawn
+// when referenced, which is reported with the reference
+var svgIgnoredElements = map[string]bool{
+	"title":    true,
+	"desc":     true,
+	"metadata": true,
+	"style":    true,
+	"defs":     true,
+}
+
+// svgColors are the basic color keywords of CSS
+var svgColors = map[string]Color{
+	"black":   {0, 0, 0},
+	"silver":  {192, 192, 192},
+	"gray":    {128, 128, 128},
+	"grey":    {128, 128, 128},
+	"white":   {255, 255, 255},
+	"maroon":  {128, 0, 0},
+	"red":     {255, 0, 0},
+	"purple":  {128, 0, 128},
+	"fuchsia": {255, 0, 255},
+	"magenta": {255, 0, 255},
+	"green":   {0, 128, 0},
+	"lime":    {0, 255, 0},
+	"olive":   {128, 128, 0},
+	"yellow":  {255, 255, 0},
+	"navy":    {0, 0, 128},
+	"blue":    {0, 0, 255},
+	"teal":    {0, 128, 128},
+	"aqua":    {0, 255, 255},
+	"cyan":    {0, 255, 255},
+	"orange":  {255, 165, 0},
+}
+
+// svgUnits are the absolute units in user units (px)
+var svgUnits = map[string]float64{
+	"":   1,
+	"px": 1,
+	"pt": 4. / 3,
+	"pc": 16,
+	"mm": 96 / 25.4,
+	"cm": 96 / 2.54,
+	"in": 96,
+}
+
+// svgNode is an element of the parsed XML, only attributes without namespace
+// are kept
+type svgNode struct {
+	name     xml.Name
+	attrs    map[string]string
+	text     string
+	children []*svgNode
+}
+
+type svgRule struct {
+	class        string
+	declarations [][2]string
+}
+
+type svgParser struct {
+	image       *svgImage
+	rules       []svgRule
+	unsupported []string
+}
+
+// isSvg reports whether the data is an XML document with a svg root element
+func isSvg(data []byte) bool {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return false
+		}
+
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local == "svg"
+		}
+	}
+}
+
+// parseSvg converts the shapes of the image into paths, a *SvgError lists
+// the features which cannot be drawn
+func parseSvg(data []byte) (*svgImage, error) {
+	root, err := parseSvgTree(data)
+	if err != nil {
+		return nil, err
+	}
+
+	viewBox, err := parseSvgViewBox(root)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &svgParser{image: &svgImage{viewBox: viewBox}}
+	p.collectStylesheets(root)
+
+	style, visible := p.computeStyle(root, svgInitialStyle)
+	if visible {
+		for _, child := range root.children {
+			p.walk(child, style)
+		}
+	}
+
+	if len(p.unsupported) > 0 {
+		return nil, &SvgError{Unsupported: p.unsupported}
+	}
+
+	return p.image, nil
+}
+
+func parseSvgTree(data []byte) (*svgNode, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	var root *svgNode
+	var stack []*svgNode
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrImageFormat, err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &svgNode{name: t.Name, attrs: make(map[string]string)}
+			for _, attr := range t.Attr {
+				if len(attr.Name.Space) == 0 {
+					node.attrs[attr.Name.Local] = attr.Value
+				}
+			}
+
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			} else if root == nil {
+				root = node
+			}
+
+			stack = append(stack, node)
+
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(t)
+			}
+		}
+	}
+
+	if root == nil || root.name.Local != "svg" {
+		return nil, fmt.Errorf("%w: the root element is not svg", ErrImageFormat)
+	}
+
+	return root, nil
+}
+
+// parseSvgViewBox returns the viewBox or the size of the image
+func parseSvgViewBox(root *svgNode) ([4]float64, error) {
+	if value, ok := root.attrs["viewBox"]; ok {
+		numbers, err := parseSvgNumbers(value)
+		if err == nil && len(numbers) == 4 && numbers[2] > 0 && numbers[3] > 0 {
+			return [4]float64{numbers[0], numbers[1], numbers[2], numbers[3]}, nil
+		}
+
+		return [4]float64{}, fmt.Errorf("%w: invalid viewBox %q", ErrImageFormat, value)
+	}
+
+	width, errWidth := parseSvgLength(root.attrs["width"])
+	height, errHeight := parseSvgLength(root.attrs["height"])
+	if errWidth != nil || errHeight != nil || width <= 0 || height <= 0 {
+		return [4]float64{}, fmt.Errorf("%w: the svg has neither a viewBox nor a width and height", ErrImageFormat)
+	}
+
+	return [4]float64{0, 0, width, height}, nil
+}
+
+func (p *svgParser) unsupport(feature string) {
+	for _, f := range p.unsupported {
+		if f == feature {
+			return
+		}
+	}
+
+	p.unsupported = append(p.unsupported, feature)
+}
+
+// collectStylesheets reads the class rules of the style elements, other
+// selectors are reported
+func (p *svgParser) collectStylesheets(node *svgNode) {
+	if node.name.Local == "style" {
+		css := node.text
+		for {
+			start := strings.Index(css, "/*")
+			if start < 0 {
+				break
+			}
+			end := strings.Index(css[start+2:], "*/")
+			if end < 0 {
+				css = css[:start]
+				break
+			}
+			css = css[:start] + css[start+2+end+2:]
+		}
+
+		for {
+			open := strings.IndexByte(css, '{')
+			if open < 0 {
+				break
+			}
+			close := strings.IndexByte(css[open:], '}')
+			if close < 0 {
+				break
+			}
+
+			selectors := css[:open]
+			declarations := parseSvgDeclarations(css[open+1 : open+close])
+			css = css[open+close+1:]
+
+			for _, selector := range strings.Split(selectors, ",") {
+				selector = strings.TrimSpace(selector)
+				if len(selector) == 0 {
+					continue
+				}
+
+				class := strings.TrimPrefix(selector, ".")
+				if len(class) == len(selector) || strings.ContainsAny(class, ".#:[ >+~*") {
+					p.unsupport("css selector " + selector)
+					continue
+				}
+
+				p.rules = append(p.rules, svgRule{class: class, declarations: declarations})
+			}
+		}
+	}
+
+	for _, child := range node.children {
+		p.collectStylesheets(child)
+	}
+}
+
+// parseSvgDeclarations splits the declarations of a style attribute or a css
+// rule, the names are lower case
+func parseSvgDeclarations(s string) [][2]string {
+	var declarations [][2]string
+
+	for _, declaration := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(declaration, ":")
+		if !ok {
+			continue
+		}
+
+		value = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "!important"))
+		declarations = append(declarations, [2]string{strings.ToLower(strings.TrimSpace(name)), value})
+	}
+
+	return declarations
+}
+
+func (p *svgParser) walk(node *svgNode, parent svgStyle) {
+	if len(node.name.Space) > 0 && node.name.Space != svgNamespace {
+		return
+	}
+
+	if svgIgnoredElements[node.name.Local] {
+		return
+	}
+
+	style, visible := p.computeStyle(node, parent)
+	if !visible {
+		return
+	}
+
+	switch node.name.Local {
+	case "g", "a":
+		for _, child := range node.children {
+			p.walk(child, style)
+		}
+
+	case "path":
+		p.addShape(style, parseSvgPath(node.attrs["d"]))
+
+	case "rect":
+		p.addShape(style, p.rectPath(node))
+
+	case "circle":
+		r := p.length(node, "r")
+		p.addShape(style, svgEllipsePath(p.length(node, "cx"), p.length(node, "cy"), r, r))
+
+	case "ellipse":
+		p.addShape(style, svgEllipsePath(p.length(node, "cx"), p.length(node, "cy"), p.length(node, "rx"), p.length(node, "ry")))
+
+	case "line":
+		p.addShape(style, []svgSegment{
+			{op: 'M', pts: [3]svgPoint{{p.length(node, "x1"), p.length(node, "y1")}}},
+			{op: 'L', pts: [3]svgPoint{{p.length(node, "x2"), p.length(node, "y2")}}},
+		})
+
+	case "polyline", "polygon":
+		//an odd number of coordinates ignores the last one
+		numbers, _ := parseSvgNumbers(node.attrs["points"])
+
+		var path []svgSegment
+		for i := 0; i+1 < len(numbers); i += 2 {
+			op := byte('L')
+			if i == 0 {
+				op = 'M'
+			}
+			path = append(path, svgSegment{op: op, pts: [3]svgPoint{{numbers[i], numbers[i+1]}}})
+		}
+
+		if node.name.Local == "polygon" && len(path) > 0 {
+			path = append(path, svgSegment{op: 'Z'})
+		}
+
+		p.addShape(style, path)
+
+	default:
+		p.unsupport("<" + node.name.Local + ">")
+	}
+}
+
+// computeStyle applies the presentation attributes, the class rules and the
+// style attribute in this order. The element and its children are not drawn
+// when it is not visible.
+func (p *svgParser) computeStyle(node *svgNode, parent svgStyle) (svgStyle, bool) {
+	properties := make(map[string]string)
+
+	for _, name := range svgProperties {
+		if value, ok := node.attrs[name]; ok {
+			properties[name] = value
+		}
+	}
+
+	for _, class := range strings.Fields(node.attrs["class"]) {
+		for _, rule := range p.rules {
+			if rule.class == class {
+				for _, declaration := range rule.declarations {
+					properties[declaration[0]] = declaration[1]
+				}
+			}
+		}
+	}
+
+	for _, declaration := range parseSvgDeclarations(node.attrs["style"]) {
+		properties[declaration[0]] = declaration[1]
+	}
+
+	style := parent
+
+	if value, ok := node.attrs["transform"]; ok && node.name.Local != "svg" {
+		transform, err := parseSvgTransform(value)
+		if err != nil {
+			p.unsupport("transform " + value)
+		}
+		style.transform = parent.transform.multiply(transform)
+	}
+
+	//in the order of svgProperties, the unsupported features are reported in a stable order
+	for _, name := range svgProperties {
+		value := strings.TrimSpace(properties[name])
+		if len(value) == 0 || value == "inherit" {
+			continue
+		}
+
+		if svgUnsupportedProperties[name] {
+			if value != "none" {
+				p.unsupport(name)
+			}
+			continue
+		}
+
+		switch name {
+		case "display":
+			if value == "none" {
+				return style, false
+			}
+
+		case "visibility":
+			style.hidden = value == "hidden" || value == "collapse"
+
+		case "color":
+			if color, ok := parseSvgColor(value); ok {
+				style.color = color
+			} else {
+				p.unsupport("color " + value)
+			}
+
+		case "fill":
+			style.fill = p.paint(name, value)
+
+		case "stroke":
+			style.stroke = p.paint(name, value)
+
+		case "fill-opacity":
+			style.fillOpacity = parseSvgOpacity(value)
+
+		case "stroke-opacity":
+			style.strokeOpacity = parseSvgOpacity(value)
+
+		case "opacity":
+			style.opacity = parent.opacity * parseSvgOpacity(value)
+
+		case "fill-rule":
+			style.evenOdd = value == "evenodd"
+
+		case "stroke-width":
+			if width, err := parseSvgLength(value); err == nil && width >= 0 {
+				style.strokeWidth = width
+			} else {
+				p.unsupport("stroke-width " + value)
+			}
+
+		case "stroke-linecap":
+			if value == "round" || value == "square" || value == "butt" {
+				style.cap = value
+			}
+
+		case "stroke-linejoin":
+			switch value {
+			case "round", "bevel":
+				style.join = value
+			default:
+				style.join = "miter"
+			}
+
+		case "stroke-dasharray":
+			style.dash = parseSvgDash(value)
+
+		case "stroke-dashoffset":
+			if phase, err := parseSvgLength(value); err == nil {
+				style.dashPhase = phase
+			}
+		}
+	}
+
+	return style, true
+}
+
+// paint parses a fill or stroke, references to gradients and patterns are
+// reported unless they have a fallback color
+func (p *svgParser) paint(name, value string) svgPaint {
+	switch value {
+	case "none", "transparent":
+		return svgPaint{none: true}
+	case "currentColor":
+		return svgPaint{current: true}
+	}
+
+	if strings.HasPrefix(value, "url(") {
+		end := strings.IndexByte(value, ')')
+		if end < 0 {
+			p.unsupport(name + " " + value)
+			return svgPaint{none: true}
+		}
+
+		fallback := strings.TrimSpace(value[end+1:])
+		if len(fallback) == 0 {
+			p.unsupport(name + " " + value[:end+1])
+			return svgPaint{none: true}
+		}
+
+		return p.paint(name, fallback)
+	}
+
+	color, ok := parseSvgColor(value)
+	if !ok {
+		p.unsupport(name + " " + value)
+		return svgPaint{none: true}
+	}
+
+	return svgPaint{color: color}
+}
+
+// addShape transforms the path into the coordinates of the viewBox
+func (p *svgParser) addShape(style svgStyle, path []svgSegment) {
+	if style.hidden || len(path) == 0 {
+		return
+	}
+
+	shape := svgShape{
+		path:    make([]svgSegment, len(path)),
+		evenOdd: style.evenOdd,
+		cap:     style.cap,
+		join:    style.join,
+	}
+
+	for i, segment := range path {
+		shape.path[i].op = segment.op
+		for j, pt := range segment.pts {
+			shape.path[i].pts[j] = style.transform.apply(pt)
+		}
+	}
+
+	if !style.fill.none {
+		color := style.fill.color
+		if style.fill.current {
+			color = style.color
+		}
+
+		shape.fill = &color
+		shape.fillAlpha = style.fillOpacity * style.opacity
+	}
+
+	if !style.stroke.none && style.strokeWidth > 0 {
+		color := style.stroke.color
+		if style.stroke.current {
+			color = style.color
+		}
+
+		scale := style.transform.scale()
+
+		shape.stroke = &color
+		shape.strokeAlpha = style.strokeOpacity * style.opacity
+		shape.strokeWidth = style.strokeWidth * scale
+		shape.dashPhase = style.dashPhase * scale
+		for _, length := range style.dash {
+			shape.dash = append(shape.dash, length*scale)
+		}
+	}
+
+	if shape.fill == nil && shape.stroke == nil {
+		return
+	}
+
+	p.image.shapes = append(p.image.shapes, shape)
+}
+
+// length returns the attribute in user units, missing attributes are 0
+func (p *svgParser) length(node *svgNode, name string) float64 {
+	value, ok := node.attrs[name]
+	if !ok {
+		return 0
+	}
+
+	length, err := parseSvgLength(value)
+	if err != nil {
+		p.unsupport(name + " " + value)
+	}
+
+	return length
+}
+
+func (p *svgParser) rectPath(node *svgNode) []svgSegment {
+	x, y := p.length(node, "x"), p.length(node, "y")
+	w, h := p.length(node, "width"), p.length(node, "height")
+	if w <= 0 || h <= 0 {
+		return nil
+	}
+
+	_, hasRx := node.attrs["rx"]
+	_, hasRy := node.attrs["ry"]
+	rx, ry := p.length(node, "rx"), p.length(node, "ry")
+	if !hasRx {
+		rx = ry
+	}
+	if !hasRy {
+		ry = rx
+	}
+	rx = math.Min(math.Max(rx, 0), w/2)
+	ry = math.Min(math.Max(ry, 0), h/2)
+
+	if rx == 0 || ry == 0 {
+		return []svgSegment{
+			{op: 'M', pts: [3]svgPoint{{x, y}}},
+			{op: 'L', pts: [3]svgPoint{{x + w, y}}},
+			{op: 'L', pts: [3]svgPoint{{x + w, y + h}}},
+			{op: 'L', pts: [3]svgPoint{{x, y + h}}},
+			{op: 'Z'},
+		}
+	}
+
+	//the corners are quarters of an ellipse
+	kx, ky := rx*svgKappa, ry*svgKappa
+	return []svgSegment{
+		{op: 'M', pts: [3]svgPoint{{x + rx, y}}},
+		{op: 'L', pts: [3]svgPoint{{x + w - rx, y}}},
+		{op: 'C', pts: [3]svgPoint{{x + w - rx + kx, y}, {x + w, y + ry - ky}, {x + w, y + ry}}},
+		{op: 'L', pts: [3]svgPoint{{x + w, y + h - ry}}},
+		{op: 'C', pts: [3]svgPoint{{x + w, y + h - ry + ky}, {x + w - rx + kx, y + h}, {x + w - rx, y + h}}},
+		{op: 'L', pts: [3]svgPoint{{x + rx, y + h}}},
+		{op: 'C', pts: [3]svgPoint{{x + rx - kx, y + h}, {x, y + h - ry + ky}, {x, y + h - ry}}},
+		{op: 'L', pts: [3]svgPoint{{x, y + ry}}},
+		{op: 'C', pts: [3]svgPoint{{x, y + ry - ky}, {x + rx - kx, y}, {x + rx, y}}},
+		{op: 'Z'},
+	}
+}
+
+// svgKappa is the distance of the control points of a quarter circle of
+// radius 1 drawn as a cubic Bézier curve
+const svgKappa = 0.5522847498
+
+func svgEllipsePath(cx, cy, rx, ry float64) []svgSegment {
+	if rx <= 0 || ry <= 0 {
+		return nil
+	}
+
+	kx, ky := rx*svgKappa, ry*svgKappa
+	return []svgSegment{
+		{op: 'M', pts: [3]svgPoint{{cx + rx, cy}}},
+		{op: 'C', pts: [3]svgPoint{{cx + rx, cy + ky}, {cx + kx, cy + ry}, {cx, cy + ry}}},
+		{op: 'C', pts: [3]svgPoint{{cx - kx, cy + ry}, {cx - rx, cy + ky}, {cx - rx, cy}}},
+		{op: 'C', pts: [3]svgPoint{{cx - rx, cy - ky}, {cx - kx, cy - ry}, {cx, cy - ry}}},
+		{op: 'C', pts: [3]svgPoint{{cx + kx, cy - ry}, {cx + rx, cy - ky}, {cx + rx, cy}}},
+		{op: 'Z'},
+	}
+}
+
+// parseSvgLength parses a number with an absolute unit into user units
+func parseSvgLength(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+
+	end := len(s)
+	for end > 0 && (s[end-1] >= 'a' && s[end-1] <= 'z' || s[end-1] == '%') {
+		end--
+	}
+
+	factor, ok := svgUnits[s[end:]]
+	if !ok {
+		return 0, fmt.Errorf("unsupported unit %q", s[end:])
+	}
+
+	v, err := strconv.ParseFloat(s[:end], 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return v * factor, nil
+}
+
+func parseSvgOpacity(s string) float64 {
+	var v float64
+	var err error
+
+	if strings.HasSuffix(s, "%") {
+		v, err = strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+		v /= 100
+	} else {
+		v, err = strconv.ParseFloat(s, 64)
+	}
+
+	if err != nil {
+		return 1
+	}
+
+	return math.Min(math.Max(v, 0), 1)
+}
+
+// parseSvgDash returns nil for solid lines, an odd number of lengths is
+// repeated
+func parseSvgDash(s string) []float64 {
+	if s == "none" {
+		return nil
+	}
+
+	var dash []float64
+	sum := 0.
+	for _, field := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' }) {
+		length, err := parseSvgLength(field)
+		if err != nil || length < 0 {
+			return nil
+		}
+		dash = append(dash, length)
+		sum += length
+	}
+
+	if sum == 0 {
+		return nil
+	}
+
+	if len(dash)%2 == 1 {
+		dash = append(dash, dash...)
+	}
+
+	return dash
+}
+
+// parseSvgColor parses hex colors, rgb() and the basic color keywords
+func parseSvgColor(s string) (Color, bool) {
+	s = strings.TrimSpace(s)
+
+	if strings.HasPrefix(s, "#") {
+		color, err := ParseHexColor(s)
+		return color, err == nil
+	}
+
+	if color, ok := svgColors[strings.ToLower(s)]; ok {
+		return color, true
+	}
+
+	lower := strings.ToLower(s)
+	var args string
+	switch {
+	case strings.HasPrefix(lower, "rgb(") && strings.HasSuffix(lower, ")"):
+		args = s[4 : len(s)-1]
+	case strings.HasPrefix(lower, "rgba(") && strings.HasSuffix(lower, ")"):
+		args = s[5 : len(s)-1]
+	default:
+		return Color{}, false
+	}
+
+	//the alpha of rgba is ignored like the alpha of hex colors
+	args, _, _ = strings.Cut(args, "/")
+	fields := strings.FieldsFunc(args, func(r rune) bool { return r == ',' || r == ' ' })
+	if len(fields) < 3 {
+		return Color{}, false
+	}
+
+	var components [3]int
+	for i := range components {
+		field := fields[i]
+		factor := 1.
+		if strings.HasSuffix(field, "%") {
+			field = strings.TrimSuffix(field, "%")
+			factor = 2.55
+		}
+
+		v, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return Color{}, false
+		}
+
+		components[i] = int(math.Round(math.Min(math.Max(v*factor, 0), 255)))
+	}
+
+	return Color{R: components[0], G: components[1], B: components[2]}, true
+}
+
+// parseSvgTransform parses a list of transformations, applied from right to
+// left
+func parseSvgTransform(s string) (svgMatrix, error) {
+	m := svgIdentity
+
+	s = strings.Trim(s, " ,\t\r\n")
+	for len(s) > 0 {
+		open := strings.IndexByte(s, '(')
+		close := strings.IndexByte(s, ')')
+		if open < 0 || close < open {
+			return svgIdentity, fmt.Errorf("invalid transform %q", s)
+		}
+
+		name := strings.TrimSpace(s[:open])
+		args, err := parseSvgNumbers(s[open+1 : close])
+		if err != nil {
+			return svgIdentity, err
+		}
+		s = strings.Trim(s[close+1:], " ,\t\r\n")
+
+		t, ok := svgTransformation(name, args)
+		if !ok {
+			return svgIdentity, fmt.Errorf("invalid transform %s", name)
+		}
+
+		m = m.multiply(t)
+	}
+
+	return m, nil
+}
+
+func svgTransformation(name string, args []float64) (svgMatrix, bool) {
+	switch {
+	case name == "matrix" && len(args) == 6:
+		return svgMatrix{args[0], args[1], args[2], args[3], args[4], args[5]}, true
+
+	case name == "translate" && len(args) == 1:
+		return svgMatrix{1, 0, 0, 1, args[0], 0}, true
+	case name == "translate" && len(args) == 2:
+		return svgMatrix{1, 0, 0, 1, args[0], args[1]}, true
+
+	case name == "scale" && len(args) == 1:
+		return svgMatrix{args[0], 0, 0, args[0], 0, 0}, true
+	case name == "scale" && len(args) == 2:
+		return svgMatrix{args[0], 0, 0, args[1], 0, 0}, true
+
+	case name == "rotate" && (len(args) == 1 || len(args) == 3):
+		sin, cos := math.Sincos(args[0] * math.Pi / 180)
+		rotation := svgMatrix{cos, sin, -sin, cos, 0, 0}
+		if len(args) == 1 {
+			return rotation, true
+		}
+
+		//rotates around the point (cx, cy)
+		to := svgMatrix{1, 0, 0, 1, args[1], args[2]}
+		back := svgMatrix{1, 0, 0, 1, -args[1], -args[2]}
+		return to.multiply(rotation).multiply(back), true
+
+	case name == "skewX" && len(args) == 1:
+		return svgMatrix{1, 0, math.Tan(args[0] * math.Pi / 180), 1, 0, 0}, true
+	case name == "skewY" && len(args) == 1:
+		return svgMatrix{1, math.Tan(args[0] * math.Pi / 180), 0, 1, 0, 0}, true
+	}
+
+	return svgIdentity, false
+}
+
+// svgScanner reads the numbers of path data, which are separated by spaces,
+// commas, signs or a second decimal point, e.g. "1.5.5-2" is 1.5, .5 and -2
+type svgScanner struct {
+	s   string
+	pos int
+}
+
+func (sc *svgScanner) skipSeparators() {
+	for sc.pos < len(sc.s) && strings.IndexByte(" \t\r\n,", sc.s[sc.pos]) >= 0 {
+		sc.pos++
+	}
+}
+
+func (sc *svgScanner) done() bool {
+	sc.skipSeparators()
+	return sc.pos >= len(sc.s)
+}
+
+func (sc *svgScanner) isDigit(pos int) bool {
+	return pos < len(sc.s) && sc.s[pos] >= '0' && sc.s[pos] <= '9'
+}
+
+func (sc *svgScanner) number() (float64, bool) {
+	sc.skipSeparators()
+	start := sc.pos
+	pos := sc.pos
+
+	if pos < len(sc.s) && (sc.s[pos] == '+' || sc.s[pos] == '-') {
+		pos++
+	}
+
+	digits := false
+	for sc.isDigit(pos) {
+		pos++
+		digits = true
+	}
+	if pos < len(sc.s) && sc.s[pos] == '.' {
+		pos++
+		for sc.isDigit(pos) {
+			pos++
+			digits = true
+		}
+	}
+	if !digits {
+		return 0, false
+	}
+
+	if pos < len(sc.s) && (sc.s[pos] == 'e' || sc.s[pos] == 'E') {
+		exp := pos + 1
+		if exp < len(sc.s) && (sc.s[exp] == '+' || sc.s[exp] == '-') {
+			exp++
+		}
+		if sc.isDigit(exp) {
+			for pos = exp; sc.isDigit(pos); pos++ {
+			}
+		}
+	}
+
+	v, err := strconv.ParseFloat(sc.s[start:pos], 64)
+	if err != nil {
+		return 0, false
+	}
+
+	sc.pos = pos
+	return v, true
+}
+
+// flag reads an arc flag, which needs no separator, e.g. "a1 1 0 00 1 1"
+func (sc *svgScanner) flag() (bool, bool) {
+	sc.skipSeparators()
+	if sc.pos < len(sc.s) && (sc.s[sc.pos] == '0' || sc.s[sc.pos] == '1') {
+		sc.pos++
+		return sc.s[sc.pos-1] == '1', true
+	}
+
+	return false, false
+}
+
+func (sc *svgScanner) point(relativeTo svgPoint) (svgPoint, bool) {
+	x, okX := sc.number()
+	y, okY := sc.number()
+
+	return svgPoint{relativeTo.X + x, relativeTo.Y + y}, okX && okY
+}
+
+func parseSvgNumbers(s string) ([]float64, error) {
+	sc := &svgScanner{s: s}
+
+	var numbers []float64
+	for !sc.done() {
+		v, ok := sc.number()
+		if !ok {
+			return nil, fmt.Errorf("invalid number at %q", s[sc.pos:])
+		}
+		numbers = append(numbers, v)
+	}
+
+	return numbers, nil
+}
+
+// parseSvgPath converts the path data into absolute moves, lines and cubic
+// curves. Like SVG renderers the path is drawn up to an error in the data.
+func parseSvgPath(d string) []svgSegment {
+	sc := &svgScanner{s: d}
+
+	var path []svgSegment
+	var cur, start, control svgPoint
+	var cmd, prev byte
+
+	for !sc.done() {
+		if c := sc.s[sc.pos]; c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' {
+			cmd = c
+			sc.pos++
+		} else if cmd == 0 || cmd == 'Z' || cmd == 'z' {
+			return path
+		}
+
+		//the path has to start with a move
+		if len(path) == 0 && cmd != 'M' && cmd != 'm' {
+			return nil
+		}
+
+		base := svgPoint{}
+		if cmd >= 'a' {
+			base = cur
+		}
+		upper := cmd &^ 0x20
+
+		var ok bool
+		switch upper {
+		case 'Z':
+			path = append(path, svgSegment{op: 'Z'})
+			cur = start
+			ok = true
+
+		case 'M':
+			var pt svgPoint
+			if pt, ok = sc.point(base); ok {
+				path = append(path, svgSegment{op: 'M', pts: [3]svgPoint{pt}})
+				cur, start = pt, pt
+				//following coordinates are lines
+				cmd = 'L' | cmd&0x20
+			}
+
+		case 'L':
+			var pt svgPoint
+			if pt, ok = sc.point(base); ok {
+				path = append(path, svgSegment{op: 'L', pts: [3]svgPoint{pt}})
+				cur = pt
+			}
+
+		case 'H':
+			var x float64
+			if x, ok = sc.number(); ok {
+				cur = svgPoint{base.X + x, cur.Y}
+				path = append(path, svgSegment{op: 'L', pts: [3]svgPoint{cur}})
+			}
+
+		case 'V':
+			var y float64
+			if y, ok = sc.number(); ok {
+				cur = svgPoint{cur.X, base.Y + y}
+				path = append(path, svgSegment{op: 'L', pts: [3]svgPoint{cur}})
+			}
+
+		case 'C', 'S':
+			c1 := cur
+			if upper == 'C' {
+				c1, ok = sc.point(base)
+			} else {
+				ok = true
+				if prev == 'C' || prev == 'S' {
+					c1 = svgPoint{2*cur.X - control.X, 2*cur.Y - control.Y}
+				}
+			}
+
+			c2, okC2 := sc.point(base)
+			pt, okPt := sc.point(base)
+			if ok = ok && okC2 && okPt; ok {
+				path = append(path, svgSegment{op: 'C', pts: [3]svgPoint{c1, c2, pt}})
+				cur, control = pt, c2
+			}
+
+		case 'Q', 'T':
+			q := cur
+			if upper == 'Q' {
+				q, ok = sc.point(base)
+			} else {
+				ok = true
+				if prev == 'Q' || prev == 'T' {
+					q = svgPoint{2*cur.X - control.X, 2*cur.Y - control.Y}
+				}
+			}
+
+			pt, okPt := sc.point(base)
+			if ok = ok && okPt; ok {
+				path = append(path, svgQuadratic(cur, q, pt))
+				cur, control = pt, q
+			}
+
+		case 'A':
+			rx, okRx := sc.number()
+			ry, okRy := sc.number()
+			angle, okAngle := sc.number()
+			large, okLarge := sc.flag()
+			sweep, okSweep := sc.flag()
+			pt, okPt := sc.point(base)
+			if ok = okRx && okRy && okAngle && okLarge && okSweep && okPt; ok {
+				path = append(path, svgArc(cur, rx, ry, angle, large, sweep, pt)...)
+				cur = pt
+			}
+		}
+
+		if !ok {
+			return path
+		}
+
+		prev = upper
+	}
+
+	return path
+}
+
+// svgQuadratic converts a quadratic Bézier curve into a cubic one
+func svgQuadratic(from, control, to svgPoint) svgSegment {
+	return svgSegment{op: 'C', pts: [3]svgPoint{
+		{from.X + 2./3*(control.X-from.X), from.Y + 2./3*(control.Y-from.Y)},
+		{to.X + 2./3*(control.X-to.X), to.Y + 2./3*(control.Y-to.Y)},
+		to,
+	}}
+}
+
+// svgArc converts an elliptical arc into cubic Bézier curves of at most a
+// quarter turn, see the implementation notes of the SVG specification
+func svgArc(from svgPoint, rx, ry, angle float64, large, sweep bool, to svgPoint) []svgSegment {
+	if from == to {
+		return nil
+	}
+
+	rx, ry = math.Abs(rx), math.Abs(ry)
+	if rx == 0 || ry == 0 {
+		return []svgSegment{{op: 'L', pts: [3]svgPoint{to}}}
+	}
+
+	sinPhi, cosPhi := math.Sincos(angle * math.Pi / 180)
+
+	//the start point in the coordinates of the ellipse
+	dx, dy := (from.X-to.X)/2, (from.Y-to.Y)/2
+	x1 := cosPhi*dx + sinPhi*dy
+	y1 := -sinPhi*dx + cosPhi*dy
+
+	//radii which are too small are scaled up
+	if lambda := x1*x1/(rx*rx) + y1*y1/(ry*ry); lambda > 1 {
+		rx *= math.Sqrt(lambda)
+		ry *= math.Sqrt(lambda)
+	}
+
+	numerator := rx*rx*ry*ry - rx*rx*y1*y1 - ry*ry*x1*x1
+	denominator := rx*rx*y1*y1 + ry*ry*x1*x1
+	coefficient := 0.
+	if numerator > 0 && denominator > 0 {
+		coefficient = math.Sqrt(numerator / denominator)
+	}
+	if large == sweep {
+		coefficient = -coefficient
+	}
+
+	cx1 := coefficient * rx * y1 / ry
+	cy1 := -coefficient * ry * x1 / rx
+	cx := cosPhi*cx1 - sinPhi*cy1 + (from.X+to.X)/2
+	cy := sinPhi*cx1 + cosPhi*cy1 + (from.Y+to.Y)/2
+
+	theta := svgAngle(1, 0, (x1-cx1)/rx, (y1-cy1)/ry)
+	delta := svgAngle((x1-cx1)/rx, (y1-cy1)/ry, (-x1-cx1)/rx, (-y1-cy1)/ry)
+	if !sweep && delta > 0 {
+		delta -= 2 * math.Pi
+	} else if sweep && delta < 0 {
+		delta += 2 * math.Pi
+	}
+
+	n := int(math.Ceil(math.Abs(delta)/(math.Pi/2) - 1e-9))
+	if n < 1 {
+		n = 1
+	}
+	step := delta / float64(n)
+	k := 4. / 3 * math.Tan(step/4)
+
+	point := func(t float64) (svgPoint, svgPoint) {
+		sin, cos := math.Sincos(t)
+		return svgPoint{cx + rx*cos*cosPhi - ry*sin*sinPhi, cy + rx*cos*sinPhi + ry*sin*cosPhi},
+			svgPoint{-rx*sin*cosPhi - ry*cos*sinPhi, -rx*sin*sinPhi + ry*cos*cosPhi}
+	}
+
+	segments := make([]svgSegment, n)
+	for i := range segments {
+		p1, d1 := point(theta + float64(i)*step)
+		p2, d2 := point(theta + float64(i+1)*step)
+		if i == n-1 {
+			p2 = to
+		}
+
+		segments[i] = svgSegment{op: 'C', pts: [3]svgPoint{
+			{p1.X + k*d1.X, p1.Y + k*d1.Y},
+			{p2.X - k*d2.X, p2.Y - k*d2.Y},
+			p2,
+		}}
+	}
+
+	return segments
+}
+
+// svgAngle is the signed angle from the vector u to v
+func svgAngle(ux, uy, vx, vy float64) float64 {
+	return math.Atan2(ux*vy-uy*vx, ux*vx+uy*vy)
+}
+
+// drawSvg draws the image into the area, which has the aspect ratio of the
+// image. The graphics state is restored afterwards.
+func (doc *Doc) drawSvg(img *svgImage, x, y, width, height float64) {
+	sx, sy := width/img.viewBox[2], height/img.viewBox[3]
+	scale := math.Sqrt(sx * sy)
+
+	fillR, fillG, fillB := doc.GetFillColor()
+	drawR, drawG, drawB := doc.GetDrawColor()
+	lineWidth := doc.GetLineWidth()
+	alpha, blendMode := doc.GetAlpha()
+	transparent := false
+
+	setAlpha := func(a float64) {
+		if a < 1 || transparent {
+			doc.SetAlpha(a, "Normal")
+			transparent = true
+		}
+	}
+
+	drawPath := func(path []svgSegment) {
+		for _, segment := range path {
+			var pts [3]svgPoint
+			for i, pt := range segment.pts {
+				pts[i] = svgPoint{x + (pt.X-img.viewBox[0])*sx, y + (pt.Y-img.viewBox[1])*sy}
+			}
+
+			switch segment.op {
+			case 'M':
+				doc.MoveTo(pts[0].X, pts[0].Y)
+			case 'L':
+				doc.LineTo(pts[0].X, pts[0].Y)
+			case 'C':
+				doc.CurveBezierCubicTo(pts[0].X, pts[0].Y, pts[1].X, pts[1].Y, pts[2].X, pts[2].Y)
+			case 'Z':
+				doc.ClosePath()
+			}
+		}
+	}
+
+	doc.TransformBegin()
+
+	for _, shape := range img.shapes {
+		if shape.fill != nil {
+			doc.SetFillColorSpec(*shape.fill)
+			setAlpha(shape.fillAlpha)
+			drawPath(shape.path)
+			if shape.evenOdd {
+				doc.DrawPath("F*")
+			} else {
+				doc.DrawPath("F")
+			}
+		}
+
+		if shape.stroke != nil {
+			doc.SetDrawColorSpec(*shape.stroke)
+			setAlpha(shape.strokeAlpha)
+			doc.SetLineWidth(shape.strokeWidth * scale)
+			doc.SetLineCapStyle(shape.cap)
+			doc.SetLineJoinStyle(shape.join)
+
+			dash := make([]float64, len(shape.dash))
+			for i, length := range shape.dash {
+				dash[i] = length * scale
+			}
+			doc.SetDashPattern(dash, shape.dashPhase*scale)
+
+			drawPath(shape.path)
+			doc.DrawPath("D")
+		}
+	}
+
+	doc.TransformEnd()
+
+	//gofpdf does not know about the restored state
+	doc.SetFillColor(fillR, fillG, fillB)
+	doc.SetDrawColor(drawR, drawG, drawB)
+	doc.SetLineWidth(lineWidth)
+	doc.SetLineCapStyle("butt")
+	doc.SetLineJoinStyle("miter")
+	doc.SetDashPattern([]float64{}, 0)
+	if transparent {
+		doc.SetAlpha(alpha, blendMode)
+	}
+}
@@ -0,0 +1,233 @@
+package document
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testSvg = `<?xml version="1.0" encoding="UTF-8"?>
+<!-- logo -->
+<svg xmlns="http://www.w3.org/2000/svg" xmlns:inkscape="http://www.inkscape.org/namespaces/inkscape" width="200mm" height="100mm" viewBox="0 0 200 100">
+  <title>Logo</title>
+  <defs><style>.cls-1{fill:#0a0;stroke:none} /* comment */ .cls-2,.cls-3{stroke:red;stroke-width:2}</style></defs>
+  <inkscape:namedview/>
+  <g transform="translate(10 20)" fill="blue" opacity="0.5">
+    <rect width="30" height="10" class="cls-1"/>
+    <circle cx="50" cy="5" r="5" style="fill:currentColor;color:rgb(255,0,0)"/>
+    <path d="M0,0 h10 v10 z" class="cls-2" fill="none"/>
+  </g>
+  <polygon points="0,0 10,0 10,10" fill-rule="evenodd"/>
+  <line x1="0" y1="0" x2="10" y2="10"/>
+  <rect width="10" height="10" display="none"/>
+</svg>`
+
+func testSvgData() *string {
+	encoded := base64.StdEncoding.EncodeToString([]byte(testSvg))
+	return &encoded
+}
+
+func assertPoint(t *testing.T, expected, actual svgPoint) {
+	assert.InDelta(t, expected.X, actual.X, 1e-6)
+	assert.InDelta(t, expected.Y, actual.Y, 1e-6)
+}
+
+func TestParseSvgPath(t *testing.T) {
+	// implicit lines after a move, relative commands and compact numbers
+	path := parseSvgPath("m10 10 5-5.5.5.5H0v2l1e1,0z")
+	assert.Equal(t, []byte{'M', 'L', 'L', 'L', 'L', 'L', 'Z'}, ops(path))
+	assertPoint(t, svgPoint{10, 10}, path[0].pts[0])
+	assertPoint(t, svgPoint{15, 4.5}, path[1].pts[0])
+	assertPoint(t, svgPoint{15.5, 5}, path[2].pts[0])
+	assertPoint(t, svgPoint{0, 5}, path[3].pts[0])
+	assertPoint(t, svgPoint{0, 7}, path[4].pts[0])
+	assertPoint(t, svgPoint{10, 7}, path[5].pts[0])
+
+	// the smooth curves reflect the last control point
+	path = parseSvgPath("M0 0C0 10 10 10 10 0S20-10 20 0Q25 5 30 0T40 0")
+	assert.Equal(t, []byte{'M', 'C', 'C', 'C', 'C'}, ops(path))
+	assertPoint(t, svgPoint{10, -10}, path[2].pts[0])
+	assertPoint(t, svgPoint{30 + 2./3*5, -2. / 3 * 5}, path[4].pts[0])
+	assertPoint(t, svgPoint{40, 0}, path[4].pts[2])
+
+	// the path is drawn up to the error
+	path = parseSvgPath("M0 0L10 10L20")
+	assert.Equal(t, []byte{'M', 'L'}, ops(path))
+
+	assert.Nil(t, parseSvgPath("L10 10"))
+	assert.Nil(t, parseSvgPath(""))
+}
+
+func ops(path []svgSegment) []byte {
+	var ops []byte
+	for _, segment := range path {
+		ops = append(ops, segment.op)
+	}
+	return ops
+}
+
+func TestSvgArc(t *testing.T) {
+	// a half circle of radius 10 with flags without separator
+	path := parseSvgPath("M0 0a10 10 0 01 20 0")
+	assert.Len(t, path, 3)
+	assertPoint(t, svgPoint{20, 0}, path[2].pts[2])
+	assertPoint(t, svgPoint{10, -10}, path[1].pts[2])
+
+	// the other sweep direction
+	path = parseSvgPath("M0 0A10 10 0 0 0 20 0")
+	assertPoint(t, svgPoint{10, 10}, path[1].pts[2])
+
+	// too small radii are scaled up
+	path = parseSvgPath("M0 0A1 1 0 0 1 20 0")
+	assertPoint(t, svgPoint{10, -10}, path[1].pts[2])
+
+	// the control points of a quarter circle
+	segments := svgArc(svgPoint{10, 0}, 10, 10, 0, false, true, svgPoint{0, 10})
+	assert.Len(t, segments, 1)
+	assertPoint(t, svgPoint{10, 10 * svgKappa}, segments[0].pts[0])
+	assertPoint(t, svgPoint{10 * svgKappa, 10}, segments[0].pts[1])
+
+	// zero radii draw a line
+	assert.Equal(t, []svgSegment{{op: 'L', pts: [3]svgPoint{{5, 5}}}}, svgArc(svgPoint{}, 0, 1, 0, false, false, svgPoint{5, 5}))
+}
+
+func TestParseSvgTransform(t *testing.T) {
+	m, err := parseSvgTransform("translate(10,20) scale(2)")
+	assert.NoError(t, err)
+	assertPoint(t, svgPoint{12, 22}, m.apply(svgPoint{1, 1}))
+	assert.InDelta(t, 2, m.scale(), 1e-9)
+
+	m, err = parseSvgTransform("rotate(90 10 10)")
+	assert.NoError(t, err)
+	assertPoint(t, svgPoint{10, 20}, m.apply(svgPoint{20, 10}))
+
+	m, err = parseSvgTransform("matrix(1 0 0 1 5 5), skewX(45)")
+	assert.NoError(t, err)
+	assertPoint(t, svgPoint{7, 6}, m.apply(svgPoint{1, 1}))
+
+	_, err = parseSvgTransform("perspective(2)")
+	assert.Error(t, err)
+}
+
+func TestParseSvgColor(t *testing.T) {
+	for value, expected := range map[string]Color{
+		"#f00":               {255, 0, 0},
+		"#00FF00":            {0, 255, 0},
+		"Navy":               {0, 0, 128},
+		"rgb(1, 2, 3)":       {1, 2, 3},
+		"rgba(100%,0%,0%,1)": {255, 0, 0},
+		"rgb(0 0 255 / 50%)": {0, 0, 255},
+	} {
+		color, ok := parseSvgColor(value)
+		assert.True(t, ok, value)
+		assert.Equal(t, expected, color, value)
+	}
+
+	_, ok := parseSvgColor("hsl(0, 100%, 50%)")
+	assert.False(t, ok)
+}
+
+func TestParseSvg(t *testing.T) {
+	img, err := parseSvg([]byte(testSvg))
+	assert.NoError(t, err)
+	assert.Equal(t, [4]float64{0, 0, 200, 100}, img.viewBox)
+	assert.Len(t, img.shapes, 5)
+
+	// the class rule is applied over the attribute of the group
+	rect := img.shapes[0]
+	assert.Equal(t, &Color{0, 170, 0}, rect.fill)
+	assert.Equal(t, 0.5, rect.fillAlpha)
+	assert.Nil(t, rect.stroke)
+	assertPoint(t, svgPoint{10, 20}, rect.path[0].pts[0])
+
+	circle := img.shapes[1]
+	assert.Equal(t, &Color{255, 0, 0}, circle.fill)
+
+	path := img.shapes[2]
+	assert.Nil(t, path.fill)
+	assert.Equal(t, &Color{255, 0, 0}, path.stroke)
+	assert.Equal(t, 2., path.strokeWidth)
+
+	polygon := img.shapes[3]
+	assert.Equal(t, &ColorBlack, polygon.fill)
+	assert.True(t, polygon.evenOdd)
+	assert.Equal(t, byte('Z'), polygon.path[3].op)
+
+	// the line is filled by default like in SVG, which paints nothing
+	assert.Equal(t, 1., img.shapes[4].fillAlpha)
+
+	// the size is used without viewBox
+	img, err = parseSvg([]byte(`<svg width="4in" height="2in"/>`))
+	assert.NoError(t, err)
+	assert.Equal(t, [4]float64{0, 0, 384, 192}, img.viewBox)
+	assert.Equal(t, 2., img.ratio())
+
+	_, err = parseSvg([]byte(`<svg width="100%"/>`))
+	assert.ErrorIs(t, err, ErrImageFormat)
+	_, err = parseSvg([]byte(`<svg viewBox="0 0 10"/>`))
+	assert.ErrorIs(t, err, ErrImageFormat)
+	_, err = parseSvg([]byte(`<svg viewBox="0 0 1 1"><path>`))
+	assert.ErrorIs(t, err, ErrImageFormat)
+}
+
+func TestParseSvgUnsupported(t *testing.T) {
+	_, err := parseSvg([]byte(`<svg viewBox="0 0 10 10">
+  <defs><linearGradient id="g"/></defs>
+  <style>svg rect{fill:red}</style>
+  <text x="0" y="5">Logo</text>
+  <text x="0" y="9">Inc</text>
+  <rect width="5" height="5" fill="url(#g)" clip-path="url(#c)"/>
+  <rect width="5" height="5" fill="url(#g) red" filter="none"/>
+  <circle r="50%"/>
+</svg>`))
+
+	var svgErr *SvgError
+	assert.ErrorAs(t, err, &svgErr)
+	assert.Equal(t, []string{"css selector svg rect", "<text>", "fill url(#g)", "clip-path", "r 50%"}, svgErr.Unsupported)
+	assert.Equal(t, "unsupported svg features: css selector svg rect, <text>, fill url(#g), clip-path, r 50%", err.Error())
+}
+
+func TestDrawSvg(t *testing.T) {
+	doc := NewA4()
+	doc.SetFillColor(1, 2, 3)
+	doc.SetLineWidth(0.3)
+
+	assert.NoError(t, doc.DrawImage(&Image{Data: testSvgData()}, 10, 10, 40, 40))
+
+	// the graphics state is restored
+	r, g, b := doc.GetFillColor()
+	assert.Equal(t, []int{1, 2, 3}, []int{r, g, b})
+	assert.Equal(t, 0.3, doc.GetLineWidth())
+	alpha, _ := doc.GetAlpha()
+	assert.Equal(t, 1., alpha)
+
+	CreatePDFInProjectRootOutFolder(doc.Fpdf, "TestDrawSvg.pdf")
+}
+
+func TestDrawImageFallback(t *testing.T) {
+	unsupported := base64.StdEncoding.EncodeToString([]byte(`<svg viewBox="0 0 10 10"><text>Logo</text></svg>`))
+	raster := base64.StdEncoding.EncodeToString(testPng(t))
+
+	doc := NewA4()
+	err := doc.DrawImage(&Image{Data: &unsupported}, 10, 10, 40, 40)
+	assert.EqualError(t, err, "unsupported svg features: <text>")
+
+	assert.NoError(t, doc.DrawImage(&Image{Data: &unsupported, Fallback: &Image{Data: &raster}}, 10, 10, 40, 40))
+
+	// svg images are not registered as images
+	_, _, err = doc.AddImage(&Image{Data: testSvgData()})
+	assert.ErrorIs(t, err, ErrImageFormat)
+
+	imageType, err := DetectImageType([]byte(testSvg))
+	assert.NoError(t, err)
+	assert.Equal(t, "SVG", imageType)
+}
+
+func TestFitImage(t *testing.T) {
+	x, y, w, h := fitImage(2, 0, 0, 40, 40)
+	assert.Equal(t, []float64{0, 10, 40, 20}, []float64{x, y, w, h})
+
+	x, y, w, h = fitImage(0.5, 0, 0, 40, 40)
+	assert.Equal(t, []float64{10, 0, 20, 40}, []float64{x, y, w, h})
+}
@@ -0,0 +1,127 @@
+package document
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+// producer is written into the document information dictionary and the XMP
+// metadata, both have to match.
+const producer = "pdf-invoice (gofpdf)"
+
+// metadata holds the document information which is written to the
+// information dictionary and the XMP metadata.
+type metadata struct {
+	title   string
+	author  string
+	subject string
+	creator string
+}
+
+// SetTitle wraps Fpdf.SetTitle and keeps the title for the XMP metadata.
+func (d *Doc) SetTitle(titleStr string, isUTF8 bool) {
+	d.meta.title = titleStr
+	d.Fpdf.SetTitle(titleStr, isUTF8)
+}
+
+// SetAuthor wraps Fpdf.SetAuthor and keeps the author for the XMP metadata.
+func (d *Doc) SetAuthor(authorStr string, isUTF8 bool) {
+	d.meta.author = authorStr
+	d.Fpdf.SetAuthor(authorStr, isUTF8)
+}
+
+// SetSubject wraps Fpdf.SetSubject and keeps the subject for the XMP
+// metadata.
+func (d *Doc) SetSubject(subjectStr string, isUTF8 bool) {
+	d.meta.subject = subjectStr
+	d.Fpdf.SetSubject(subjectStr, isUTF8)
+}
+
+// SetCreator wraps Fpdf.SetCreator and keeps the creator for the XMP
+// metadata.
+func (d *Doc) SetCreator(creatorStr string, isUTF8 bool) {
+	d.meta.creator = creatorStr
+	d.Fpdf.SetCreator(creatorStr, isUTF8)
+}
+
+// infoDictionary creates the document information dictionary matching the XMP
+// metadata.
+func (d *Doc) infoDictionary(tm time.Time) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("<<\n")
+	fmt.Fprintf(&buf, "/Producer %s\n", pdfTextString(producer))
+	if len(d.meta.title) > 0 {
+		fmt.Fprintf(&buf, "/Title %s\n", pdfTextString(d.meta.title))
+	}
+	if len(d.meta.author) > 0 {
+		fmt.Fprintf(&buf, "/Author %s\n", pdfTextString(d.meta.author))
+	}
+	if len(d.meta.subject) > 0 {
+		fmt.Fprintf(&buf, "/Subject %s\n", pdfTextString(d.meta.subject))
+	}
+	if len(d.meta.creator) > 0 {
+		fmt.Fprintf(&buf, "/Creator %s\n", pdfTextString(d.meta.creator))
+	}
+	fmt.Fprintf(&buf, "/CreationDate %s\n", pdfTextString(pdfDate(tm)))
+	fmt.Fprintf(&buf, "/ModDate %s\n", pdfTextString(pdfDate(tm)))
+	buf.WriteString(">>")
+	return buf.Bytes()
+}
+
+// xmpPacket creates the XMP metadata stream content of the document.
+func (d *Doc) xmpPacket(tm time.Time) []byte {
+	date := tm.UTC().Format("2006-01-02T15:04:05Z")
+
+	var buf bytes.Buffer
+	buf.WriteString("<?xpacket begin=\"\xef\xbb\xbf\" id=\"W5M0MpCehiHzreSzNTczkc9d\"?>\n")
+	buf.WriteString("<x:xmpmeta xmlns:x=\"adobe:ns:meta/\">\n")
+	buf.WriteString("<rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\">\n")
+
+	buf.WriteString("<rdf:Description rdf:about=\"\" xmlns:dc=\"http://purl.org/dc/elements/1.1/\">\n")
+	buf.WriteString("<dc:format>application/pdf</dc:format>\n")
+	if len(d.meta.title) > 0 {
+		fmt.Fprintf(&buf, "<dc:title><rdf:Alt><rdf:li xml:lang=\"x-default\">%s</rdf:li></rdf:Alt></dc:title>\n", xmlEscape(d.meta.title))
+	}
+	if len(d.meta.author) > 0 {
+		fmt.Fprintf(&buf, "<dc:creator><rdf:Seq><rdf:li>%s</rdf:li></rdf:Seq></dc:creator>\n", xmlEscape(d.meta.author))
+	}
+	if len(d.meta.subject) > 0 {
+		fmt.Fprintf(&buf, "<dc:description><rdf:Alt><rdf:li xml:lang=\"x-default\">%s</rdf:li></rdf:Alt></dc:description>\n", xmlEscape(d.meta.subject))
+	}
+	buf.WriteString("</rdf:Description>\n")
+
+	buf.WriteString("<rdf:Description rdf:about=\"\" xmlns:pdf=\"http://ns.adobe.com/pdf/1.3/\">\n")
+	fmt.Fprintf(&buf, "<pdf:Producer>%s</pdf:Producer>\n", xmlEscape(producer))
+	buf.WriteString("</rdf:Description>\n")
+
+	buf.WriteString("<rdf:Description rdf:about=\"\" xmlns:xmp=\"http://ns.adobe.com/xap/1.0/\">\n")
+	fmt.Fprintf(&buf, "<xmp:CreateDate>%s</xmp:CreateDate>\n", date)
+	fmt.Fprintf(&buf, "<xmp:ModifyDate>%s</xmp:ModifyDate>\n", date)
+	fmt.Fprintf(&buf, "<xmp:MetadataDate>%s</xmp:MetadataDate>\n", date)
+	if len(d.meta.creator) > 0 {
+		fmt.Fprintf(&buf, "<xmp:CreatorTool>%s</xmp:CreatorTool>\n", xmlEscape(d.meta.creator))
+	}
+	buf.WriteString("</rdf:Description>\n")
+
+	if d.conformance.IsPdfA() {
+		buf.WriteString(pdfaIdentification)
+	}
+
+	for _, description := range d.xmpDescriptions {
+		buf.WriteString(description)
+	}
+
+	buf.WriteString("</rdf:RDF>\n")
+	buf.WriteString("</x:xmpmeta>\n")
+	buf.WriteString("<?xpacket end=\"w\"?>")
+
+	return buf.Bytes()
+}
+
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
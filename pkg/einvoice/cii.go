@@ -0,0 +1,419 @@
+package einvoice
+
+import (
+	"encoding/xml"
+	"math"
+	"strconv"
+	"time"
+)
+
+// namespaces of the UN/CEFACT Cross-Industry-Invoice D16B
+const (
+	nsRsm = "urn:un:unece:uncefact:data:standard:CrossIndustryInvoice:100"
+	nsRam = "urn:un:unece:uncefact:data:standard:ReusableAggregateBusinessInformationEntity:100"
+	nsQdt = "urn:un:unece:uncefact:data:standard:QualifiedDataType:100"
+	nsUdt = "urn:un:unece:uncefact:data:standard:UnqualifiedDataType:100"
+)
+
+type ciiDocument struct {
+	XMLName  xml.Name `xml:"rsm:CrossIndustryInvoice"`
+	XmlnsRsm string   `xml:"xmlns:rsm,attr"`
+	XmlnsRam string   `xml:"xmlns:ram,attr"`
+	XmlnsQdt string   `xml:"xmlns:qdt,attr"`
+	XmlnsUdt string   `xml:"xmlns:udt,attr"`
+
+	Context     ciiContext     `xml:"rsm:ExchangedDocumentContext"`
+	Document    ciiHeader      `xml:"rsm:ExchangedDocument"`
+	Transaction ciiTransaction `xml:"rsm:SupplyChainTradeTransaction"`
+}
+
+type ciiContext struct {
+	BusinessProcess string `xml:"ram:BusinessProcessSpecifiedDocumentContextParameter>ram:ID,omitempty"`
+	GuidelineID     string `xml:"ram:GuidelineSpecifiedDocumentContextParameter>ram:ID"`
+}
+
+type ciiHeader struct {
+	ID        string      `xml:"ram:ID"`
+	TypeCode  string      `xml:"ram:TypeCode"`
+	IssueDate ciiDateTime `xml:"ram:IssueDateTime"`
+	Notes     []ciiNote   `xml:"ram:IncludedNote,omitempty"`
+}
+
+type ciiNote struct {
+	Content string `xml:"ram:Content"`
+}
+
+type ciiDateTime struct {
+	Value ciiDateString `xml:"udt:DateTimeString"`
+}
+
+type ciiDateString struct {
+	Format string `xml:"format,attr"`
+	Value  string `xml:",chardata"`
+}
+
+type ciiTransaction struct {
+	Lines      []ciiLine     `xml:"ram:IncludedSupplyChainTradeLineItem,omitempty"`
+	Agreement  ciiAgreement  `xml:"ram:ApplicableHeaderTradeAgreement"`
+	Delivery   struct{}      `xml:"ram:ApplicableHeaderTradeDelivery"`
+	Settlement ciiSettlement `xml:"ram:ApplicableHeaderTradeSettlement"`
+}
+
+type ciiLine struct {
+	LineID     string            `xml:"ram:AssociatedDocumentLineDocument>ram:LineID"`
+	Product    ciiProduct        `xml:"ram:SpecifiedTradeProduct"`
+	NetPrice   string            `xml:"ram:SpecifiedLineTradeAgreement>ram:NetPriceProductTradePrice>ram:ChargeAmount"`
+	Quantity   ciiQuantity       `xml:"ram:SpecifiedLineTradeDelivery>ram:BilledQuantity"`
+	Settlement ciiLineSettlement `xml:"ram:SpecifiedLineTradeSettlement"`
+}
+
+type ciiProduct struct {
+	Name        string `xml:"ram:Name"`
+	Description string `xml:"ram:Description,omitempty"`
+}
+
+type ciiQuantity struct {
+	UnitCode string `xml:"unitCode,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type ciiLineSettlement struct {
+	Tax         ciiTax `xml:"ram:ApplicableTradeTax"`
+	TotalAmount string `xml:"ram:SpecifiedTradeSettlementLineMonetarySummation>ram:LineTotalAmount"`
+}
+
+type ciiTax struct {
+	CalculatedAmount string `xml:"ram:CalculatedAmount,omitempty"`
+	TypeCode         string `xml:"ram:TypeCode"`
+	BasisAmount      string `xml:"ram:BasisAmount,omitempty"`
+	CategoryCode     string `xml:"ram:CategoryCode"`
+	Rate             string `xml:"ram:RateApplicablePercent"`
+}
+
+type ciiAgreement struct {
+	BuyerReference string   `xml:"ram:BuyerReference,omitempty"`
+	Seller         ciiParty `xml:"ram:SellerTradeParty"`
+	Buyer          ciiParty `xml:"ram:BuyerTradeParty"`
+}
+
+type ciiParty struct {
+	Name             string               `xml:"ram:Name"`
+	Legal            *ciiLegal            `xml:"ram:SpecifiedLegalOrganization,omitempty"`
+	Contact          *ciiContact          `xml:"ram:DefinedTradeContact,omitempty"`
+	Address          *ciiAddress          `xml:"ram:PostalTradeAddress,omitempty"`
+	URI              *ciiURI              `xml:"ram:URIUniversalCommunication,omitempty"`
+	TaxRegistrations []ciiTaxRegistration `xml:"ram:SpecifiedTaxRegistration,omitempty"`
+}
+
+type ciiLegal struct {
+	ID string `xml:"ram:ID"`
+}
+
+type ciiContact struct {
+	Name  string `xml:"ram:PersonName,omitempty"`
+	Phone string `xml:"ram:TelephoneUniversalCommunication>ram:CompleteNumber,omitempty"`
+	Email string `xml:"ram:EmailURIUniversalCommunication>ram:URIID,omitempty"`
+}
+
+type ciiAddress struct {
+	Zip       string `xml:"ram:PostcodeCode,omitempty"`
+	LineOne   string `xml:"ram:LineOne,omitempty"`
+	LineTwo   string `xml:"ram:LineTwo,omitempty"`
+	City      string `xml:"ram:CityName,omitempty"`
+	CountryID string `xml:"ram:CountryID"`
+}
+
+type ciiURI struct {
+	ID ciiSchemeID `xml:"ram:URIID"`
+}
+
+type ciiTaxRegistration struct {
+	ID ciiSchemeID `xml:"ram:ID"`
+}
+
+type ciiSchemeID struct {
+	SchemeID string `xml:"schemeID,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type ciiSettlement struct {
+	PaymentReference string                 `xml:"ram:PaymentReference,omitempty"`
+	Currency         string                 `xml:"ram:InvoiceCurrencyCode"`
+	PaymentMeans     *ciiPaymentMeans       `xml:"ram:SpecifiedTradeSettlementPaymentMeans,omitempty"`
+	Taxes            []ciiTax               `xml:"ram:ApplicableTradeTax,omitempty"`
+	Allowances       []ciiAllowance         `xml:"ram:SpecifiedTradeAllowanceCharge,omitempty"`
+	PaymentTerms     *ciiPaymentTerms       `xml:"ram:SpecifiedTradePaymentTerms,omitempty"`
+	Summation        ciiMonetarySummation   `xml:"ram:SpecifiedTradeSettlementHeaderMonetarySummation"`
+	InvoiceReference *ciiReferencedDocument `xml:"ram:InvoiceReferencedDocument,omitempty"`
+}
+
+type ciiReferencedDocument struct {
+	ID        string            `xml:"ram:IssuerAssignedID"`
+	IssueDate *ciiFormattedDate `xml:"ram:FormattedIssueDateTime,omitempty"`
+}
+
+type ciiFormattedDate struct {
+	Value ciiDateString `xml:"qdt:DateTimeString"`
+}
+
+type ciiAllowance struct {
+	ChargeIndicator bool   `xml:"ram:ChargeIndicator>udt:Indicator"`
+	ActualAmount    string `xml:"ram:ActualAmount"`
+	ReasonCode      string `xml:"ram:ReasonCode,omitempty"`
+	Reason          string `xml:"ram:Reason,omitempty"`
+	Tax             ciiTax `xml:"ram:CategoryTradeTax"`
+}
+
+type ciiPaymentMeans struct {
+	TypeCode    string          `xml:"ram:TypeCode"`
+	Account     ciiAccount      `xml:"ram:PayeePartyCreditorFinancialAccount"`
+	Institution *ciiInstitution `xml:"ram:PayeeSpecifiedCreditorFinancialInstitution,omitempty"`
+}
+
+type ciiAccount struct {
+	IBAN        string `xml:"ram:IBANID"`
+	AccountName string `xml:"ram:AccountName,omitempty"`
+}
+
+type ciiInstitution struct {
+	BIC string `xml:"ram:BICID"`
+}
+
+type ciiPaymentTerms struct {
+	DueDate ciiDateTime `xml:"ram:DueDateDateTime"`
+}
+
+type ciiMonetarySummation struct {
+	LineTotal      string      `xml:"ram:LineTotalAmount,omitempty"`
+	AllowanceTotal string      `xml:"ram:AllowanceTotalAmount,omitempty"`
+	TaxBasis       string      `xml:"ram:TaxBasisTotalAmount"`
+	TaxTotal       ciiCurrency `xml:"ram:TaxTotalAmount"`
+	GrandTotal     string      `xml:"ram:GrandTotalAmount"`
+	DuePayable     string      `xml:"ram:DuePayableAmount"`
+}
+
+type ciiCurrency struct {
+	Currency string `xml:"currencyID,attr"`
+	Value    string `xml:",chardata"`
+}
+
+// GenerateCII creates the UN/CEFACT Cross-Industry-Invoice xml of the invoice
+// restricted to the elements allowed in the given profile. The invoice is
+// validated before generation.
+func GenerateCII(inv *Invoice, profile Profile) ([]byte, error) {
+	if err := inv.Validate(profile); err != nil {
+		return nil, err
+	}
+
+	totals := inv.Totals()
+
+	typeCode := inv.TypeCode
+	if len(typeCode) == 0 {
+		typeCode = TypeCodeInvoice
+	}
+
+	doc := ciiDocument{
+		XmlnsRsm: nsRsm,
+		XmlnsRam: nsRam,
+		XmlnsQdt: nsQdt,
+		XmlnsUdt: nsUdt,
+		Context: ciiContext{
+			BusinessProcess: profile.businessProcess(),
+			GuidelineID:     profile.GuidelineID(),
+		},
+		Document: ciiHeader{
+			ID:        inv.Number,
+			TypeCode:  string(typeCode),
+			IssueDate: ciiDate(inv.IssueDate),
+		},
+	}
+
+	transaction := &doc.Transaction
+
+	transaction.Agreement = ciiAgreement{
+		BuyerReference: inv.BuyerReference,
+		Seller:         ciiPartyFrom(&inv.Seller, profile, true),
+		Buyer:          ciiPartyFrom(&inv.Buyer, profile, false),
+	}
+
+	transaction.Settlement = ciiSettlement{
+		Currency: inv.Currency,
+		Summation: ciiMonetarySummation{
+			TaxBasis:   formatAmount(totals.TaxBasis),
+			TaxTotal:   ciiCurrency{Currency: inv.Currency, Value: formatAmount(totals.TaxTotal)},
+			GrandTotal: formatAmount(totals.GrandTotal),
+			DuePayable: formatAmount(totals.DuePayable),
+		},
+	}
+
+	if ref := inv.PrecedingInvoice; ref != nil && profile.hasLines() {
+		transaction.Settlement.InvoiceReference = &ciiReferencedDocument{ID: ref.Number}
+
+		if !ref.IssueDate.IsZero() {
+			transaction.Settlement.InvoiceReference.IssueDate = &ciiFormattedDate{Value: ciiDate(ref.IssueDate).Value}
+		}
+	}
+
+	if profile.hasLines() {
+		for _, note := range inv.Notes {
+			doc.Document.Notes = append(doc.Document.Notes, ciiNote{Content: note})
+		}
+
+		for i, line := range inv.Lines {
+			transaction.Lines = append(transaction.Lines, ciiLineFrom(i, &line, profile))
+		}
+
+		settlement := &transaction.Settlement
+
+		if inv.Payment != nil {
+			settlement.PaymentReference = inv.Payment.Reference
+			settlement.PaymentMeans = &ciiPaymentMeans{
+				// 58 = SEPA credit transfer
+				TypeCode: "58",
+				Account: ciiAccount{
+					IBAN:        inv.Payment.IBAN,
+					AccountName: inv.Payment.AccountName,
+				},
+			}
+
+			if len(inv.Payment.BIC) > 0 {
+				settlement.PaymentMeans.Institution = &ciiInstitution{BIC: inv.Payment.BIC}
+			}
+		}
+
+		for _, breakdown := range inv.TaxBreakdowns() {
+			settlement.Taxes = append(settlement.Taxes, ciiTax{
+				CalculatedAmount: formatAmount(breakdown.TaxAmount),
+				TypeCode:         "VAT",
+				BasisAmount:      formatAmount(breakdown.BasisAmount),
+				CategoryCode:     taxCategory(breakdown.Rate),
+				Rate:             formatDecimal(breakdown.Rate),
+			})
+		}
+
+		for _, allowance := range inv.Allowances {
+			settlement.Allowances = append(settlement.Allowances, ciiAllowance{
+				ActualAmount: formatAmount(allowance.Amount),
+				// 95 = discount
+				ReasonCode: "95",
+				Reason:     allowance.reason(),
+				Tax: ciiTax{
+					TypeCode:     "VAT",
+					CategoryCode: taxCategory(allowance.TaxRate),
+					Rate:         formatDecimal(allowance.TaxRate),
+				},
+			})
+		}
+
+		if len(inv.Allowances) > 0 {
+			settlement.Summation.AllowanceTotal = formatAmount(totals.AllowanceTotal)
+		}
+
+		if inv.DueDate != nil {
+			settlement.PaymentTerms = &ciiPaymentTerms{DueDate: ciiDate(*inv.DueDate)}
+		}
+
+		settlement.Summation.LineTotal = formatAmount(totals.LineTotal)
+	}
+
+	out, err := xml.MarshalIndent(&doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), out...), nil
+}
+
+func ciiPartyFrom(party *Party, profile Profile, seller bool) ciiParty {
+	res := ciiParty{
+		Name: party.Name,
+	}
+
+	if len(party.CorporateRegisterNumber) > 0 {
+		res.Legal = &ciiLegal{ID: party.CorporateRegisterNumber}
+	}
+
+	if profile.hasDetails() && seller && (len(party.Email) > 0 || len(party.Phone) > 0) {
+		res.Contact = &ciiContact{Name: party.contactName(), Phone: party.Phone, Email: party.Email}
+	}
+
+	if len(party.CountryCode) > 0 {
+		res.Address = &ciiAddress{CountryID: party.CountryCode}
+
+		if profile.hasLines() {
+			res.Address.Zip = party.Zip
+			res.Address.LineOne = party.Street1
+			res.Address.LineTwo = party.Street2
+			res.Address.City = party.City
+		}
+	}
+
+	if address, scheme := party.electronicAddress(); profile.hasDetails() && len(address) > 0 {
+		res.URI = &ciiURI{ID: ciiSchemeID{SchemeID: scheme, Value: address}}
+	}
+
+	if len(party.VATID) > 0 {
+		res.TaxRegistrations = append(res.TaxRegistrations, ciiTaxRegistration{
+			ID: ciiSchemeID{SchemeID: "VA", Value: party.VATID},
+		})
+	}
+
+	return res
+}
+
+func ciiLineFrom(i int, line *Line, profile Profile) ciiLine {
+	id := line.ID
+	if len(id) == 0 {
+		id = strconv.Itoa(i + 1)
+	}
+
+	unitCode := line.UnitCode
+	if len(unitCode) == 0 {
+		unitCode = "C62"
+	}
+
+	res := ciiLine{
+		LineID:   id,
+		Product:  ciiProduct{Name: line.Name},
+		NetPrice: formatDecimal(line.NetPrice),
+		Quantity: ciiQuantity{UnitCode: unitCode, Value: formatDecimal(line.Quantity)},
+		Settlement: ciiLineSettlement{
+			Tax: ciiTax{
+				TypeCode:     "VAT",
+				CategoryCode: taxCategory(line.TaxRate),
+				Rate:         formatDecimal(line.TaxRate),
+			},
+			TotalAmount: formatAmount(line.NetAmount),
+		},
+	}
+
+	if profile.hasDetails() {
+		res.Product.Description = line.Description
+	}
+
+	return res
+}
+
+func ciiDate(date time.Time) ciiDateTime {
+	// format 102 = CCYYMMDD
+	return ciiDateTime{Value: ciiDateString{Format: "102", Value: date.Format("20060102")}}
+}
+
+// taxCategory returns the UNTDID 5305 code for the given rate: S (standard
+// rate) or Z (zero rated).
+func taxCategory(rate float64) string {
+	if rate == 0 {
+		return "Z"
+	}
+
+	return "S"
+}
+
+func formatAmount(value float64) string {
+	return strconv.FormatFloat(round(value), 'f', 2, 64)
+}
+
+// formatDecimal writes the value with up to 4 decimals without trailing zeros.
+func formatDecimal(value float64) string {
+	return strconv.FormatFloat(math.Round(value*10000)/10000, 'f', -1, 64)
+}
@@ -0,0 +1,194 @@
+package einvoice
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testInvoice() *Invoice {
+	issueDate := time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC)
+	dueDate := issueDate.AddDate(0, 0, 14)
+
+	return &Invoice{
+		Number:    "RE-2023-1",
+		IssueDate: issueDate,
+		DueDate:   &dueDate,
+		Currency:  "EUR",
+		Seller: Party{
+			Name:        "Seller GmbH",
+			Street1:     "Straße 1",
+			Zip:         "1010",
+			City:        "Wien",
+			CountryCode: "AT",
+			VATID:       "ATU12345678",
+			Email:       "office@seller.at",
+		},
+		Buyer: Party{
+			Name:        "Buyer AG",
+			CountryCode: "DE",
+		},
+		Lines: []Line{
+			{Name: "Consulting", Quantity: 2, UnitCode: "HUR", NetPrice: 100, NetAmount: 200, TaxRate: 20},
+			{Name: "Book", Description: "paperback", Quantity: 1, UnitCode: "C62", NetPrice: 10.55, NetAmount: 10.55, TaxRate: 10},
+			{Name: "Travel", Quantity: 1, UnitCode: "LS", NetPrice: 30, NetAmount: 30, TaxRate: 20},
+		},
+		Payment: &Payment{
+			IBAN:        "AT611904300234573201",
+			AccountName: "Seller GmbH",
+		},
+	}
+}
+
+func TestTaxBreakdowns(t *testing.T) {
+	breakdowns := testInvoice().TaxBreakdowns()
+
+	assert.Equal(t, []TaxBreakdown{
+		{Rate: 10, BasisAmount: 10.55, TaxAmount: 1.06},
+		{Rate: 20, BasisAmount: 230, TaxAmount: 46},
+	}, breakdowns)
+}
+
+func TestTotals(t *testing.T) {
+	totals := testInvoice().Totals()
+
+	assert.Equal(t, 240.55, totals.LineTotal)
+	assert.Equal(t, 47.06, totals.TaxTotal)
+	assert.Equal(t, 287.61, totals.GrandTotal)
+	assert.Equal(t, 287.61, totals.DuePayable)
+}
+
+func TestAllowances(t *testing.T) {
+	inv := testInvoice()
+	inv.Allowances = []Allowance{{Amount: 23, TaxRate: 20}}
+
+	assert.Equal(t, []TaxBreakdown{
+		{Rate: 10, BasisAmount: 10.55, TaxAmount: 1.06},
+		{Rate: 20, BasisAmount: 207, TaxAmount: 41.4},
+	}, inv.TaxBreakdowns())
+
+	totals := inv.Totals()
+	assert.Equal(t, 23.0, totals.AllowanceTotal)
+	assert.Equal(t, 217.55, totals.TaxBasis)
+	assert.Equal(t, 260.01, totals.DuePayable)
+
+	out, err := GenerateCII(inv, ProfileEN16931)
+	assert.NoError(t, err)
+
+	content := string(out)
+	assert.Contains(t, content, "<udt:Indicator>false</udt:Indicator>")
+	assert.Contains(t, content, "<ram:Reason>Discount</ram:Reason>")
+	assert.Contains(t, content, "<ram:AllowanceTotalAmount>23.00</ram:AllowanceTotalAmount>")
+
+	out, err = GenerateUBL(inv, ProfileEN16931)
+	assert.NoError(t, err)
+
+	content = string(out)
+	assert.Contains(t, content, "<cbc:ChargeIndicator>false</cbc:ChargeIndicator>")
+	assert.Contains(t, content, `<cbc:AllowanceTotalAmount currencyID="EUR">23.00</cbc:AllowanceTotalAmount>`)
+	assert.Contains(t, content, `<cbc:PayableAmount currencyID="EUR">260.01</cbc:PayableAmount>`)
+}
+
+func TestTotalsMinorUnits(t *testing.T) {
+	inv := testInvoice()
+	inv.Currency = "JPY"
+
+	// 10.55 * 10% = 1.055
+	assert.Equal(t, []TaxBreakdown{
+		{Rate: 10, BasisAmount: 11, TaxAmount: 1},
+		{Rate: 20, BasisAmount: 230, TaxAmount: 46},
+	}, inv.TaxBreakdowns())
+	assert.Equal(t, 288.0, inv.Totals().DuePayable)
+}
+
+func TestGenerateCIIProfiles(t *testing.T) {
+	for _, profile := range []Profile{ProfileMinimum, ProfileBasic, ProfileEN16931, ProfileExtended} {
+		out, err := GenerateCII(testInvoice(), profile)
+		assert.NoError(t, err)
+
+		// has to be well-formed
+		var res interface{}
+		assert.NoError(t, xml.Unmarshal(out, &res))
+
+		content := string(out)
+		assert.Contains(t, content, "<ram:ID>"+profile.GuidelineID()+"</ram:ID>")
+		assert.Contains(t, content, "<ram:GrandTotalAmount>287.61</ram:GrandTotalAmount>")
+		assert.Contains(t, content, `<ram:TaxTotalAmount currencyID="EUR">47.06</ram:TaxTotalAmount>`)
+
+		if profile == ProfileMinimum {
+			assert.NotContains(t, content, "IncludedSupplyChainTradeLineItem")
+			assert.NotContains(t, content, "LineTotalAmount")
+		} else {
+			assert.Equal(t, 3, strings.Count(content, "<ram:IncludedSupplyChainTradeLineItem>"))
+			assert.Contains(t, content, "<ram:IBANID>AT611904300234573201</ram:IBANID>")
+			assert.Contains(t, content, `<ram:BilledQuantity unitCode="HUR">2</ram:BilledQuantity>`)
+		}
+
+		if profile == ProfileBasic {
+			assert.NotContains(t, content, "paperback")
+		}
+		if profile == ProfileEN16931 {
+			assert.Contains(t, content, "<ram:Description>paperback</ram:Description>")
+		}
+	}
+}
+
+func TestGenerateCIIValidation(t *testing.T) {
+	inv := testInvoice()
+	inv.Number = ""
+	inv.Seller.VATID = ""
+	inv.Buyer.CountryCode = ""
+
+	_, err := GenerateCII(inv, ProfileEN16931)
+
+	valErr, ok := err.(*ValidationError)
+	assert.True(t, ok)
+	assert.Equal(t, []string{
+		"BR-02 invoice number",
+		"BR-CO-26 seller vat identifier or legal registration",
+		"BR-11 buyer country code",
+	}, valErr.Rules)
+
+	// buyer country is not part of MINIMUM
+	inv = testInvoice()
+	inv.Buyer.CountryCode = ""
+	_, err = GenerateCII(inv, ProfileMinimum)
+	assert.NoError(t, err)
+}
+
+func TestGenerateCIICreditNote(t *testing.T) {
+	inv := testInvoice()
+	inv.Number = "GS-2023-1"
+	inv.TypeCode = TypeCodeCreditNote
+	inv.PrecedingInvoice = &DocumentReference{Number: "RE-2023-1", IssueDate: time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC)}
+
+	out, err := GenerateCII(inv, ProfileEN16931)
+	assert.NoError(t, err)
+
+	content := string(out)
+	assert.Contains(t, content, "<ram:TypeCode>381</ram:TypeCode>")
+	assert.Contains(t, content, "<ram:IssuerAssignedID>RE-2023-1</ram:IssuerAssignedID>")
+	assert.Contains(t, content, `<qdt:DateTimeString format="102">20230110</qdt:DateTimeString>`)
+
+	// the reference follows the totals
+	assert.Less(t, strings.Index(content, "SpecifiedTradeSettlementHeaderMonetarySummation"), strings.Index(content, "InvoiceReferencedDocument"))
+
+	out, err = GenerateCII(inv, ProfileMinimum)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(out), "InvoiceReferencedDocument")
+}
+
+func TestUnitCode(t *testing.T) {
+	assert.Equal(t, "HUR", UnitCode("Std."))
+	assert.Equal(t, "KGM", UnitCode("kg"))
+	assert.Equal(t, "C62", UnitCode("stk"))
+}
+
+func TestCountryCode(t *testing.T) {
+	assert.Equal(t, "AT", CountryCode("ÖSTERREICH"))
+	assert.Equal(t, "DE", CountryCode("de"))
+	assert.Equal(t, "", CountryCode("Atlantis"))
+}
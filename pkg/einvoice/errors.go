@@ -0,0 +1,40 @@
+package einvoice
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hodl-repos/pdf-invoice/pkg/standardisedError"
+)
+
+// define custom error to catch later in withErrorHandler
+type ValidationError struct {
+	standardisedError.StandardisedError
+	Profile Profile  `json:"profile"`
+	Rules   []string `json:"rules"`
+}
+
+func (v *ValidationError) Error() string {
+	return fmt.Sprintf("invoice does not fulfill profile %s: %s", v.Profile, strings.Join(v.Rules, ", "))
+}
+
+func (v *ValidationError) GetStandardisedError() *standardisedError.StandardisedError {
+	return &v.StandardisedError
+}
+
+func newValidationError(profile Profile, rules []string) *ValidationError {
+	err := &ValidationError{
+		Profile: profile,
+		Rules:   rules,
+	}
+
+	err.StandardisedError = standardisedError.StandardisedError{
+		Type:   "https://example.net/validation-error",
+		Title:  "The invoice cannot be converted into an electronic invoice",
+		Status: http.StatusBadRequest,
+		Detail: err.Error(),
+	}
+
+	return err
+}
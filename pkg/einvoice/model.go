@@ -0,0 +1,353 @@
+package einvoice
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/text/currency"
+)
+
+// TypeCode is the UNTDID 1001 document type code of an invoice.
+type TypeCode string
+
+const (
+	TypeCodeInvoice    TypeCode = "380"
+	TypeCodeCreditNote TypeCode = "381"
+)
+
+// Invoice is the syntax independent representation of an electronic invoice
+// following the semantic model of EN 16931. All amounts are in Currency.
+type Invoice struct {
+	Number    string
+	TypeCode  TypeCode
+	IssueDate time.Time
+	DueDate   *time.Time
+	Currency  string
+
+	// BuyerReference is an identifier assigned by the buyer, used for routing
+	// the invoice (BT-10).
+	BuyerReference string
+
+	// PrecedingInvoice is the invoice corrected by a credit note (BG-3).
+	PrecedingInvoice *DocumentReference
+
+	Notes []string
+
+	Seller Party
+	Buyer  Party
+
+	Lines []Line
+	// Allowances are document level discounts (BG-20)
+	Allowances []Allowance
+
+	Payment *Payment
+}
+
+// DocumentReference references a previously issued invoice by its number and
+// issue date.
+type DocumentReference struct {
+	Number    string
+	IssueDate time.Time
+}
+
+// Party is a seller or buyer of an invoice.
+type Party struct {
+	Name string
+
+	Street1 string
+	Street2 string
+	Zip     string
+	City    string
+	// CountryCode has to be an ISO 3166-1 alpha-2 code
+	CountryCode string
+
+	VATID                   string
+	CorporateRegisterNumber string
+
+	// ContactName is the contact point of the party, the name is used when
+	// empty.
+	ContactName string
+	Email       string
+	Phone       string
+
+	// ElectronicAddress identifies the party for routing electronic invoices
+	// (BT-34, BT-49) in the scheme ElectronicAddressScheme. The email is used
+	// when empty.
+	ElectronicAddress       string
+	ElectronicAddressScheme string
+}
+
+// EAS codes (electronic address scheme) used for ElectronicAddressScheme
+const (
+	SchemeEmail     = "EM"
+	SchemeLeitwegID = "0204"
+)
+
+// contactName returns the name of the contact point.
+func (p *Party) contactName() string {
+	if len(p.ContactName) > 0 {
+		return p.ContactName
+	}
+
+	return p.Name
+}
+
+// electronicAddress returns the electronic address and its scheme, falls back
+// to the email.
+func (p *Party) electronicAddress() (string, string) {
+	if len(p.ElectronicAddress) > 0 {
+		return p.ElectronicAddress, p.ElectronicAddressScheme
+	}
+
+	if len(p.Email) > 0 {
+		return p.Email, SchemeEmail
+	}
+
+	return "", ""
+}
+
+// Line is a single invoice line. NetAmount is the line total without tax
+// after line discounts, NetPrice the net price of one unit.
+type Line struct {
+	ID          string
+	Name        string
+	Description string
+
+	Quantity float64
+	UnitCode string
+	NetPrice float64
+
+	NetAmount float64
+	TaxRate   float64
+}
+
+// Allowance is a document level discount without tax. It reduces the tax
+// basis of its tax rate.
+type Allowance struct {
+	Amount  float64
+	TaxRate float64
+	// Reason is required by BR-33, "Discount" is used when empty
+	Reason string
+}
+
+// reason returns the reason of the allowance.
+func (a *Allowance) reason() string {
+	if len(a.Reason) > 0 {
+		return a.Reason
+	}
+
+	return "Discount"
+}
+
+// Payment describes the credit transfer the buyer should use.
+type Payment struct {
+	IBAN        string
+	BIC         string
+	AccountName string
+	Reference   string
+}
+
+// TaxBreakdown is the sum of all lines with the same tax rate.
+type TaxBreakdown struct {
+	Rate        float64
+	BasisAmount float64
+	TaxAmount   float64
+}
+
+// Totals are the document level amounts of an invoice.
+type Totals struct {
+	LineTotal      float64
+	AllowanceTotal float64
+	TaxBasis       float64
+	TaxTotal       float64
+	GrandTotal     float64
+	DuePayable     float64
+}
+
+// TaxBreakdowns groups all lines by their tax rate, the allowances reduce the
+// basis amount of their rate. The tax amount of every group is calculated from
+// the basis amount, as required by BR-CO-17.
+func (inv *Invoice) TaxBreakdowns() []TaxBreakdown {
+	groups := make(map[float64]*TaxBreakdown)
+
+	group := func(rate float64) *TaxBreakdown {
+		res, ok := groups[rate]
+		if !ok {
+			res = &TaxBreakdown{Rate: rate}
+			groups[rate] = res
+		}
+
+		return res
+	}
+
+	for _, line := range inv.Lines {
+		group(line.TaxRate).BasisAmount += line.NetAmount
+	}
+
+	for _, allowance := range inv.Allowances {
+		group(allowance.TaxRate).BasisAmount -= allowance.Amount
+	}
+
+	res := make([]TaxBreakdown, 0, len(groups))
+	for _, group := range groups {
+		group.BasisAmount = inv.round(group.BasisAmount)
+		group.TaxAmount = inv.round(group.BasisAmount * group.Rate / 100)
+		res = append(res, *group)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Rate < res[j].Rate })
+
+	return res
+}
+
+// Totals calculates the document totals from the lines and allowances.
+func (inv *Invoice) Totals() Totals {
+	totals := Totals{}
+
+	for _, line := range inv.Lines {
+		totals.LineTotal += line.NetAmount
+	}
+
+	for _, allowance := range inv.Allowances {
+		totals.AllowanceTotal += allowance.Amount
+	}
+
+	for _, breakdown := range inv.TaxBreakdowns() {
+		totals.TaxTotal += breakdown.TaxAmount
+	}
+
+	totals.LineTotal = inv.round(totals.LineTotal)
+	totals.AllowanceTotal = inv.round(totals.AllowanceTotal)
+	totals.TaxBasis = inv.round(totals.LineTotal - totals.AllowanceTotal)
+	totals.TaxTotal = inv.round(totals.TaxTotal)
+	totals.GrandTotal = inv.round(totals.TaxBasis + totals.TaxTotal)
+	totals.DuePayable = totals.GrandTotal
+
+	return totals
+}
+
+// Validate checks the business rules of EN 16931 which cannot be guaranteed by
+// the mapping itself.
+func (inv *Invoice) Validate(profile Profile) error {
+	var missing []string
+
+	check := func(value, rule string) {
+		if len(strings.TrimSpace(value)) == 0 {
+			missing = append(missing, rule)
+		}
+	}
+
+	check(inv.Number, "BR-02 invoice number")
+	check(inv.Currency, "BR-05 invoice currency code")
+	check(inv.Seller.Name, "BR-06 seller name")
+	check(inv.Buyer.Name, "BR-07 buyer name")
+	check(inv.Seller.CountryCode, "BR-09 seller country code")
+
+	if inv.IssueDate.IsZero() {
+		missing = append(missing, "BR-03 invoice issue date")
+	}
+
+	if len(inv.Seller.VATID) == 0 && len(inv.Seller.CorporateRegisterNumber) == 0 {
+		missing = append(missing, "BR-CO-26 seller vat identifier or legal registration")
+	}
+
+	if profile.hasLines() {
+		check(inv.Buyer.CountryCode, "BR-11 buyer country code")
+
+		if len(inv.Lines) == 0 {
+			missing = append(missing, "BR-16 at least one invoice line")
+		}
+	}
+
+	if profile == ProfileXRechnung {
+		missing = append(missing, inv.xRechnungRules()...)
+	}
+
+	if len(missing) > 0 {
+		return newValidationError(profile, missing)
+	}
+
+	return nil
+}
+
+// UnitCode maps commonly used unit names to UN/ECE recommendation 20 codes.
+// Unknown units are mapped to C62 (one).
+func UnitCode(unit string) string {
+	switch strings.ToLower(strings.Trim(strings.TrimSpace(unit), ".")) {
+	case "h", "hr", "hrs", "hour", "hours", "std", "stunde", "stunden":
+		return "HUR"
+	case "min", "minute", "minutes", "minuten":
+		return "MIN"
+	case "d", "day", "days", "tag", "tage":
+		return "DAY"
+	case "kg":
+		return "KGM"
+	case "g":
+		return "GRM"
+	case "m":
+		return "MTR"
+	case "km":
+		return "KMT"
+	case "m2", "m²":
+		return "MTK"
+	case "l", "liter", "litre":
+		return "LTR"
+	case "pauschal", "flat", "lump sum":
+		return "LS"
+	default:
+		return "C62"
+	}
+}
+
+// CountryCode returns the ISO 3166-1 alpha-2 code of the given country if it is
+// already a code or one of the commonly used names, otherwise an empty string.
+func CountryCode(country string) string {
+	country = strings.TrimSpace(country)
+
+	if len(country) == 2 {
+		return strings.ToUpper(country)
+	}
+
+	switch strings.ToLower(country) {
+	case "austria", "österreich", "oesterreich":
+		return "AT"
+	case "germany", "deutschland":
+		return "DE"
+	case "switzerland", "schweiz", "suisse", "svizzera":
+		return "CH"
+	case "france", "frankreich":
+		return "FR"
+	case "italy", "italien", "italia":
+		return "IT"
+	case "liechtenstein":
+		return "LI"
+	case "netherlands", "niederlande", "nederland":
+		return "NL"
+	case "belgium", "belgien", "belgique":
+		return "BE"
+	case "luxembourg", "luxemburg":
+		return "LU"
+	default:
+		return ""
+	}
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// round rounds the value to the minor units (ISO 4217) of the invoice
+// currency, unknown currencies are rounded to 2 decimals.
+func (inv *Invoice) round(value float64) float64 {
+	cur, err := currency.ParseISO(inv.Currency)
+	if err != nil {
+		return round(value)
+	}
+
+	scale, _ := currency.Standard.Rounding(cur)
+	factor := math.Pow10(scale)
+
+	return math.Round(value*factor) / factor
+}
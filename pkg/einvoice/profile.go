@@ -0,0 +1,80 @@
+package einvoice
+
+// Profile determines which Factur-X / ZUGFeRD profile the generated
+// Cross-Industry-Invoice has to follow. The profile defines which elements are
+// written into the xml and which guideline is referenced in the document
+// context.
+type Profile string
+
+const (
+	ProfileMinimum  Profile = "MINIMUM"
+	ProfileBasic    Profile = "BASIC"
+	ProfileEN16931  Profile = "EN16931"
+	ProfileExtended Profile = "EXTENDED"
+	// ProfileXRechnung is the german CIUS of EN 16931 required by the public
+	// sector, it can be embedded or sent as plain xml (UBL or CII).
+	ProfileXRechnung Profile = "XRECHNUNG"
+)
+
+// FacturXFilename is the name the xml has to be embedded with, otherwise
+// receivers will not pick it up.
+const FacturXFilename = "factur-x.xml"
+
+// Valid returns true if the profile is one of the supported profiles.
+func (p Profile) Valid() bool {
+	switch p {
+	case ProfileMinimum, ProfileBasic, ProfileEN16931, ProfileExtended, ProfileXRechnung:
+		return true
+	default:
+		return false
+	}
+}
+
+// GuidelineID returns the specification identifier written into
+// ExchangedDocumentContext/GuidelineSpecifiedDocumentContextParameter.
+func (p Profile) GuidelineID() string {
+	switch p {
+	case ProfileMinimum:
+		return "urn:factur-x.eu:1p0:minimum"
+	case ProfileBasic:
+		return "urn:cen.eu:en16931:2017#compliant#urn:factur-x.eu:1p0:basic"
+	case ProfileExtended:
+		return "urn:cen.eu:en16931:2017#conformant#urn:factur-x.eu:1p0:extended"
+	case ProfileXRechnung:
+		return "urn:cen.eu:en16931:2017#compliant#urn:xeinkauf.de:kosit:xrechnung_3.0"
+	default:
+		return "urn:cen.eu:en16931:2017"
+	}
+}
+
+// ConformanceLevel returns the value of fx:ConformanceLevel used in the XMP
+// metadata of the pdf.
+func (p Profile) ConformanceLevel() string {
+	if p == ProfileEN16931 {
+		return "EN 16931"
+	}
+
+	return string(p)
+}
+
+// hasLines returns true if the profile contains invoice lines. MINIMUM only
+// transports the document totals.
+func (p Profile) hasLines() bool {
+	return p != ProfileMinimum
+}
+
+// hasDetails returns true if the profile allows descriptive elements like
+// line descriptions, seller contact or payment terms.
+func (p Profile) hasDetails() bool {
+	return p == ProfileEN16931 || p == ProfileExtended || p == ProfileXRechnung
+}
+
+// businessProcess returns the business process type (BT-23), only required
+// by XRechnung.
+func (p Profile) businessProcess() string {
+	if p == ProfileXRechnung {
+		return "urn:fdc:peppol.eu:2017:poacc:billing:01:1.0"
+	}
+
+	return ""
+}
@@ -0,0 +1,350 @@
+package einvoice
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+)
+
+// namespaces of the OASIS UBL 2.1 Invoice and CreditNote
+const (
+	nsUblInvoice    = "urn:oasis:names:specification:ubl:schema:xsd:Invoice-2"
+	nsUblCreditNote = "urn:oasis:names:specification:ubl:schema:xsd:CreditNote-2"
+	nsCac           = "urn:oasis:names:specification:ubl:schema:xsd:CommonAggregateComponents-2"
+	nsCbc           = "urn:oasis:names:specification:ubl:schema:xsd:CommonBasicComponents-2"
+)
+
+// ublInvoice is an Invoice or a CreditNote, the root element is set by
+// XMLName, credit notes use the elements named after them
+type ublInvoice struct {
+	XMLName  xml.Name
+	Xmlns    string `xml:"xmlns,attr"`
+	XmlnsCac string `xml:"xmlns:cac,attr"`
+	XmlnsCbc string `xml:"xmlns:cbc,attr"`
+
+	CustomizationID    string               `xml:"cbc:CustomizationID"`
+	ProfileID          string               `xml:"cbc:ProfileID,omitempty"`
+	ID                 string               `xml:"cbc:ID"`
+	IssueDate          string               `xml:"cbc:IssueDate"`
+	DueDate            string               `xml:"cbc:DueDate,omitempty"`
+	TypeCode           string               `xml:"cbc:InvoiceTypeCode,omitempty"`
+	CreditNoteTypeCode string               `xml:"cbc:CreditNoteTypeCode,omitempty"`
+	Notes              []string             `xml:"cbc:Note,omitempty"`
+	Currency           string               `xml:"cbc:DocumentCurrencyCode"`
+	BuyerReference     string               `xml:"cbc:BuyerReference,omitempty"`
+	BillingReference   *ublBillingReference `xml:"cac:BillingReference,omitempty"`
+
+	Seller          ublParty             `xml:"cac:AccountingSupplierParty>cac:Party"`
+	Buyer           ublParty             `xml:"cac:AccountingCustomerParty>cac:Party"`
+	PaymentMeans    *ublPaymentMeans     `xml:"cac:PaymentMeans,omitempty"`
+	Allowances      []ublAllowanceCharge `xml:"cac:AllowanceCharge,omitempty"`
+	TaxTotal        ublTaxTotal          `xml:"cac:TaxTotal"`
+	Totals          ublMonetaryTotal     `xml:"cac:LegalMonetaryTotal"`
+	Lines           []ublInvoiceLine     `xml:"cac:InvoiceLine,omitempty"`
+	CreditNoteLines []ublInvoiceLine     `xml:"cac:CreditNoteLine,omitempty"`
+}
+
+type ublBillingReference struct {
+	ID        string `xml:"cac:InvoiceDocumentReference>cbc:ID"`
+	IssueDate string `xml:"cac:InvoiceDocumentReference>cbc:IssueDate,omitempty"`
+}
+
+type ublParty struct {
+	EndpointID *ublSchemeID       `xml:"cbc:EndpointID,omitempty"`
+	Address    ublAddress         `xml:"cac:PostalAddress"`
+	TaxScheme  *ublPartyTaxScheme `xml:"cac:PartyTaxScheme,omitempty"`
+	Legal      ublLegalEntity     `xml:"cac:PartyLegalEntity"`
+	Contact    *ublContact        `xml:"cac:Contact,omitempty"`
+}
+
+type ublSchemeID struct {
+	SchemeID string `xml:"schemeID,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type ublAddress struct {
+	Street     string `xml:"cbc:StreetName,omitempty"`
+	Additional string `xml:"cbc:AdditionalStreetName,omitempty"`
+	City       string `xml:"cbc:CityName,omitempty"`
+	Zip        string `xml:"cbc:PostalZone,omitempty"`
+	Country    string `xml:"cac:Country>cbc:IdentificationCode"`
+}
+
+type ublPartyTaxScheme struct {
+	CompanyID string `xml:"cbc:CompanyID"`
+	TaxScheme string `xml:"cac:TaxScheme>cbc:ID"`
+}
+
+type ublLegalEntity struct {
+	Name      string `xml:"cbc:RegistrationName"`
+	CompanyID string `xml:"cbc:CompanyID,omitempty"`
+}
+
+type ublContact struct {
+	Name  string `xml:"cbc:Name,omitempty"`
+	Phone string `xml:"cbc:Telephone,omitempty"`
+	Email string `xml:"cbc:ElectronicMail,omitempty"`
+}
+
+type ublPaymentMeans struct {
+	Code string `xml:"cbc:PaymentMeansCode"`
+	// the due date of credit notes
+	DueDate   string              `xml:"cbc:PaymentDueDate,omitempty"`
+	PaymentID string              `xml:"cbc:PaymentID,omitempty"`
+	Account   ublFinancialAccount `xml:"cac:PayeeFinancialAccount"`
+}
+
+type ublFinancialAccount struct {
+	ID     string `xml:"cbc:ID"`
+	Name   string `xml:"cbc:Name,omitempty"`
+	Branch string `xml:"cac:FinancialInstitutionBranch>cbc:ID,omitempty"`
+}
+
+type ublAmount struct {
+	Currency string `xml:"currencyID,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type ublAllowanceCharge struct {
+	ChargeIndicator bool           `xml:"cbc:ChargeIndicator"`
+	ReasonCode      string         `xml:"cbc:AllowanceChargeReasonCode,omitempty"`
+	Reason          string         `xml:"cbc:AllowanceChargeReason,omitempty"`
+	Amount          ublAmount      `xml:"cbc:Amount"`
+	TaxCategory     ublTaxCategory `xml:"cac:TaxCategory"`
+}
+
+type ublTaxTotal struct {
+	TaxAmount ublAmount        `xml:"cbc:TaxAmount"`
+	Subtotals []ublTaxSubtotal `xml:"cac:TaxSubtotal"`
+}
+
+type ublTaxSubtotal struct {
+	TaxableAmount ublAmount      `xml:"cbc:TaxableAmount"`
+	TaxAmount     ublAmount      `xml:"cbc:TaxAmount"`
+	Category      ublTaxCategory `xml:"cac:TaxCategory"`
+}
+
+type ublTaxCategory struct {
+	ID        string `xml:"cbc:ID"`
+	Percent   string `xml:"cbc:Percent"`
+	TaxScheme string `xml:"cac:TaxScheme>cbc:ID"`
+}
+
+type ublMonetaryTotal struct {
+	LineExtension  ublAmount  `xml:"cbc:LineExtensionAmount"`
+	TaxExclusive   ublAmount  `xml:"cbc:TaxExclusiveAmount"`
+	TaxInclusive   ublAmount  `xml:"cbc:TaxInclusiveAmount"`
+	AllowanceTotal *ublAmount `xml:"cbc:AllowanceTotalAmount,omitempty"`
+	Payable        ublAmount  `xml:"cbc:PayableAmount"`
+}
+
+type ublInvoiceLine struct {
+	ID               string       `xml:"cbc:ID"`
+	Quantity         *ublQuantity `xml:"cbc:InvoicedQuantity,omitempty"`
+	CreditedQuantity *ublQuantity `xml:"cbc:CreditedQuantity,omitempty"`
+	LineExtension    ublAmount    `xml:"cbc:LineExtensionAmount"`
+	Item             ublItem      `xml:"cac:Item"`
+	Price            ublAmount    `xml:"cac:Price>cbc:PriceAmount"`
+}
+
+type ublQuantity struct {
+	UnitCode string `xml:"unitCode,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type ublItem struct {
+	Description string         `xml:"cbc:Description,omitempty"`
+	Name        string         `xml:"cbc:Name"`
+	TaxCategory ublTaxCategory `xml:"cac:ClassifiedTaxCategory"`
+}
+
+// GenerateUBL creates the OASIS UBL 2.1 Invoice xml of the invoice, or the
+// CreditNote xml of credit notes. UBL only supports the profiles based on the
+// complete semantic model (EN16931 and XRECHNUNG). The invoice is validated
+// before generation.
+func GenerateUBL(inv *Invoice, profile Profile) ([]byte, error) {
+	if profile != ProfileEN16931 && profile != ProfileXRechnung {
+		return nil, fmt.Errorf("einvoice: profile %s is not supported in UBL", profile)
+	}
+
+	if err := inv.Validate(profile); err != nil {
+		return nil, err
+	}
+
+	totals := inv.Totals()
+
+	typeCode := inv.TypeCode
+	if len(typeCode) == 0 {
+		typeCode = TypeCodeInvoice
+	}
+
+	amount := func(value float64) ublAmount {
+		return ublAmount{Currency: inv.Currency, Value: formatAmount(value)}
+	}
+
+	// credit notes have their own root element in UBL
+	creditNote := typeCode == TypeCodeCreditNote
+
+	doc := ublInvoice{
+		XMLName:         xml.Name{Local: "Invoice"},
+		Xmlns:           nsUblInvoice,
+		XmlnsCac:        nsCac,
+		XmlnsCbc:        nsCbc,
+		CustomizationID: profile.GuidelineID(),
+		ProfileID:       profile.businessProcess(),
+		ID:              inv.Number,
+		IssueDate:       inv.IssueDate.Format("2006-01-02"),
+		Notes:           inv.Notes,
+		Currency:        inv.Currency,
+		BuyerReference:  inv.BuyerReference,
+		Seller:          ublPartyFrom(&inv.Seller, true),
+		Buyer:           ublPartyFrom(&inv.Buyer, false),
+		TaxTotal:        ublTaxTotal{TaxAmount: amount(totals.TaxTotal)},
+		Totals: ublMonetaryTotal{
+			LineExtension: amount(totals.LineTotal),
+			TaxExclusive:  amount(totals.TaxBasis),
+			TaxInclusive:  amount(totals.GrandTotal),
+			Payable:       amount(totals.DuePayable),
+		},
+	}
+
+	if creditNote {
+		doc.XMLName.Local = "CreditNote"
+		doc.Xmlns = nsUblCreditNote
+		doc.CreditNoteTypeCode = string(typeCode)
+	} else {
+		doc.TypeCode = string(typeCode)
+	}
+
+	if ref := inv.PrecedingInvoice; ref != nil {
+		doc.BillingReference = &ublBillingReference{ID: ref.Number}
+
+		if !ref.IssueDate.IsZero() {
+			doc.BillingReference.IssueDate = ref.IssueDate.Format("2006-01-02")
+		}
+	}
+
+	if inv.Payment != nil {
+		doc.PaymentMeans = &ublPaymentMeans{
+			// 58 = SEPA credit transfer
+			Code:      "58",
+			PaymentID: inv.Payment.Reference,
+			Account: ublFinancialAccount{
+				ID:     inv.Payment.IBAN,
+				Name:   inv.Payment.AccountName,
+				Branch: inv.Payment.BIC,
+			},
+		}
+	}
+
+	// the CreditNote has the due date in the payment means
+	if inv.DueDate != nil {
+		dueDate := inv.DueDate.Format("2006-01-02")
+
+		if !creditNote {
+			doc.DueDate = dueDate
+		} else if doc.PaymentMeans != nil {
+			doc.PaymentMeans.DueDate = dueDate
+		}
+	}
+
+	for _, allowance := range inv.Allowances {
+		doc.Allowances = append(doc.Allowances, ublAllowanceCharge{
+			// 95 = discount
+			ReasonCode:  "95",
+			Reason:      allowance.reason(),
+			Amount:      amount(allowance.Amount),
+			TaxCategory: ublTaxCategoryFrom(allowance.TaxRate),
+		})
+	}
+
+	if len(inv.Allowances) > 0 {
+		allowanceTotal := amount(totals.AllowanceTotal)
+		doc.Totals.AllowanceTotal = &allowanceTotal
+	}
+
+	for _, breakdown := range inv.TaxBreakdowns() {
+		doc.TaxTotal.Subtotals = append(doc.TaxTotal.Subtotals, ublTaxSubtotal{
+			TaxableAmount: amount(breakdown.BasisAmount),
+			TaxAmount:     amount(breakdown.TaxAmount),
+			Category:      ublTaxCategoryFrom(breakdown.Rate),
+		})
+	}
+
+	for i, line := range inv.Lines {
+		id := line.ID
+		if len(id) == 0 {
+			id = strconv.Itoa(i + 1)
+		}
+
+		unitCode := line.UnitCode
+		if len(unitCode) == 0 {
+			unitCode = "C62"
+		}
+
+		res := ublInvoiceLine{
+			ID:            id,
+			LineExtension: amount(line.NetAmount),
+			Item: ublItem{
+				Description: line.Description,
+				Name:        line.Name,
+				TaxCategory: ublTaxCategoryFrom(line.TaxRate),
+			},
+			Price: ublAmount{Currency: inv.Currency, Value: formatDecimal(line.NetPrice)},
+		}
+
+		quantity := &ublQuantity{UnitCode: unitCode, Value: formatDecimal(line.Quantity)}
+		if creditNote {
+			res.CreditedQuantity = quantity
+			doc.CreditNoteLines = append(doc.CreditNoteLines, res)
+		} else {
+			res.Quantity = quantity
+			doc.Lines = append(doc.Lines, res)
+		}
+	}
+
+	out, err := xml.MarshalIndent(&doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), out...), nil
+}
+
+func ublPartyFrom(party *Party, seller bool) ublParty {
+	res := ublParty{
+		Address: ublAddress{
+			Street:     party.Street1,
+			Additional: party.Street2,
+			City:       party.City,
+			Zip:        party.Zip,
+			Country:    party.CountryCode,
+		},
+		Legal: ublLegalEntity{
+			Name:      party.Name,
+			CompanyID: party.CorporateRegisterNumber,
+		},
+	}
+
+	if address, scheme := party.electronicAddress(); len(address) > 0 {
+		res.EndpointID = &ublSchemeID{SchemeID: scheme, Value: address}
+	}
+
+	if len(party.VATID) > 0 {
+		res.TaxScheme = &ublPartyTaxScheme{CompanyID: party.VATID, TaxScheme: "VAT"}
+	}
+
+	if seller && (len(party.Email) > 0 || len(party.Phone) > 0) {
+		res.Contact = &ublContact{Name: party.contactName(), Phone: party.Phone, Email: party.Email}
+	}
+
+	return res
+}
+
+func ublTaxCategoryFrom(rate float64) ublTaxCategory {
+	return ublTaxCategory{
+		ID:        taxCategory(rate),
+		Percent:   formatDecimal(rate),
+		TaxScheme: "VAT",
+	}
+}
@@ -0,0 +1,49 @@
+package einvoice
+
+import "fmt"
+
+// FacturXXmpDescription returns the rdf:Description blocks which have to be
+// added to the XMP metadata of the pdf, so that receivers recognise the
+// embedded xml. This includes the PDF/A extension schema describing the fx
+// namespace.
+func FacturXXmpDescription(profile Profile) string {
+	return fmt.Sprintf(`<rdf:Description rdf:about="" xmlns:fx="urn:factur-x:pdfa:CrossIndustryDocument:invoice:1p0#">
+<fx:DocumentType>INVOICE</fx:DocumentType>
+<fx:DocumentFileName>%s</fx:DocumentFileName>
+<fx:Version>1.0</fx:Version>
+<fx:ConformanceLevel>%s</fx:ConformanceLevel>
+</rdf:Description>
+<rdf:Description rdf:about="" xmlns:pdfaExtension="http://www.aiim.org/pdfa/ns/extension/" xmlns:pdfaSchema="http://www.aiim.org/pdfa/ns/schema#" xmlns:pdfaProperty="http://www.aiim.org/pdfa/ns/property#">
+<pdfaExtension:schemas>
+<rdf:Bag>
+<rdf:li rdf:parseType="Resource">
+<pdfaSchema:schema>Factur-X PDFA Extension Schema</pdfaSchema:schema>
+<pdfaSchema:namespaceURI>urn:factur-x:pdfa:CrossIndustryDocument:invoice:1p0#</pdfaSchema:namespaceURI>
+<pdfaSchema:prefix>fx</pdfaSchema:prefix>
+<pdfaSchema:property>
+<rdf:Seq>
+%s%s%s%s</rdf:Seq>
+</pdfaSchema:property>
+</rdf:li>
+</rdf:Bag>
+</pdfaExtension:schemas>
+</rdf:Description>
+`,
+		FacturXFilename,
+		profile.ConformanceLevel(),
+		xmpProperty("DocumentFileName", "name of the embedded XML invoice file"),
+		xmpProperty("DocumentType", "INVOICE"),
+		xmpProperty("Version", "The actual version of the Factur-X XML schema"),
+		xmpProperty("ConformanceLevel", "The conformance level of the embedded Factur-X data"),
+	)
+}
+
+func xmpProperty(name, description string) string {
+	return fmt.Sprintf(`<rdf:li rdf:parseType="Resource">
+<pdfaProperty:name>%s</pdfaProperty:name>
+<pdfaProperty:valueType>Text</pdfaProperty:valueType>
+<pdfaProperty:category>external</pdfaProperty:category>
+<pdfaProperty:description>%s</pdfaProperty:description>
+</rdf:li>
+`, name, description)
+}
@@ -0,0 +1,115 @@
+package einvoice
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// Syntax is the xml syntax an electronic invoice is written in.
+type Syntax string
+
+const (
+	// SyntaxUBL is the OASIS UBL 2.1 Invoice
+	SyntaxUBL Syntax = "ubl"
+	// SyntaxCII is the UN/CEFACT Cross-Industry-Invoice
+	SyntaxCII Syntax = "cii"
+)
+
+var SyntaxParser = &syntaxParser{}
+
+type syntaxParser struct {
+}
+
+func (s *syntaxParser) Parse(input string) (*Syntax, error) {
+	syntax := Syntax(strings.ToLower(input))
+
+	if syntax != SyntaxUBL && syntax != SyntaxCII {
+		return nil, fmt.Errorf("unknown syntax %s", input)
+	}
+
+	return &syntax, nil
+}
+
+// Generate creates the xml of the invoice in the given syntax.
+func Generate(inv *Invoice, profile Profile, syntax Syntax) ([]byte, error) {
+	if syntax == SyntaxUBL {
+		return GenerateUBL(inv, profile)
+	}
+
+	return GenerateCII(inv, profile)
+}
+
+// leitwegIDRegex matches the coarse addressing, optional fine addressing and
+// check digits of a Leitweg-ID.
+var leitwegIDRegex = regexp.MustCompile(`^[0-9]{2,12}(-[0-9A-Z]{1,30})?-[0-9]{2}$`)
+
+// ValidLeitwegID checks the format and the check digits (ISO 7064 MOD 97-10)
+// of a Leitweg-ID, the routing identifier of german public sector buyers.
+func ValidLeitwegID(id string) bool {
+	id = strings.ToUpper(strings.TrimSpace(id))
+
+	if !leitwegIDRegex.MatchString(id) {
+		return false
+	}
+
+	parts := strings.Split(id, "-")
+	checkDigits := parts[len(parts)-1]
+
+	// letters are replaced by two digits (A = 10, ..., Z = 35)
+	var digits strings.Builder
+	for _, r := range strings.Join(parts[:len(parts)-1], "") {
+		if r >= 'A' && r <= 'Z' {
+			fmt.Fprintf(&digits, "%d", r-'A'+10)
+		} else {
+			digits.WriteRune(r)
+		}
+	}
+	digits.WriteString(checkDigits)
+
+	value, ok := new(big.Int).SetString(digits.String(), 10)
+	if !ok {
+		return false
+	}
+
+	return new(big.Int).Mod(value, big.NewInt(97)).Int64() == 1
+}
+
+// xRechnungRules checks the additional business rules of XRechnung and returns
+// all violated rules.
+func (inv *Invoice) xRechnungRules() []string {
+	var missing []string
+
+	check := func(value, rule string) {
+		if len(strings.TrimSpace(value)) == 0 {
+			missing = append(missing, rule)
+		}
+	}
+
+	check(inv.BuyerReference, "BR-DE-15 buyer reference")
+	check(inv.Seller.City, "BR-DE-3 seller city")
+	check(inv.Seller.Zip, "BR-DE-4 seller post code")
+	check(inv.Seller.Phone, "BR-DE-6 seller contact telephone number")
+	check(inv.Seller.Email, "BR-DE-7 seller contact email address")
+	check(inv.Buyer.City, "BR-DE-8 buyer city")
+	check(inv.Buyer.Zip, "BR-DE-9 buyer post code")
+
+	if address, _ := inv.Seller.electronicAddress(); len(address) == 0 {
+		missing = append(missing, "BT-34 seller electronic address")
+	}
+
+	if address, scheme := inv.Buyer.electronicAddress(); len(address) == 0 {
+		missing = append(missing, "BT-49 buyer electronic address")
+	} else if scheme == SchemeLeitwegID && !ValidLeitwegID(address) {
+		missing = append(missing, "BT-49 valid Leitweg-ID")
+	}
+
+	if inv.Payment == nil {
+		missing = append(missing, "BR-DE-1 payment instructions")
+	} else {
+		check(inv.Payment.IBAN, "BR-DE-23-a IBAN of the credit transfer")
+	}
+
+	return missing
+}
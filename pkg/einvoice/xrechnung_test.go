@@ -0,0 +1,122 @@
+package einvoice
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testXRechnung() *Invoice {
+	inv := testInvoice()
+	inv.BuyerReference = "04011000-1234512345-06"
+	inv.Seller.Phone = "+43 1 234567"
+	inv.Buyer.Zip = "10115"
+	inv.Buyer.City = "Berlin"
+	inv.Buyer.ElectronicAddress = inv.BuyerReference
+	inv.Buyer.ElectronicAddressScheme = SchemeLeitwegID
+	return inv
+}
+
+func TestValidLeitwegID(t *testing.T) {
+	assert.True(t, ValidLeitwegID("04011000-1234512345-06"))
+	assert.True(t, ValidLeitwegID("991-33333TEST-33"))
+	assert.False(t, ValidLeitwegID("04011000-1234512345-07"))
+	assert.False(t, ValidLeitwegID("04011000"))
+	assert.False(t, ValidLeitwegID(""))
+}
+
+func TestGenerateXRechnungUBL(t *testing.T) {
+	out, err := GenerateUBL(testXRechnung(), ProfileXRechnung)
+	assert.NoError(t, err)
+
+	var res interface{}
+	assert.NoError(t, xml.Unmarshal(out, &res))
+
+	content := string(out)
+	assert.Contains(t, content, `<Invoice xmlns="urn:oasis:names:specification:ubl:schema:xsd:Invoice-2"`)
+	assert.Contains(t, content, "<cbc:CustomizationID>urn:cen.eu:en16931:2017#compliant#urn:xeinkauf.de:kosit:xrechnung_3.0</cbc:CustomizationID>")
+	assert.Contains(t, content, "<cbc:ProfileID>urn:fdc:peppol.eu:2017:poacc:billing:01:1.0</cbc:ProfileID>")
+	assert.Contains(t, content, "<cbc:BuyerReference>04011000-1234512345-06</cbc:BuyerReference>")
+	assert.Contains(t, content, `<cbc:EndpointID schemeID="0204">04011000-1234512345-06</cbc:EndpointID>`)
+	assert.Contains(t, content, `<cbc:EndpointID schemeID="EM">office@seller.at</cbc:EndpointID>`)
+	assert.Contains(t, content, `<cbc:PayableAmount currencyID="EUR">287.61</cbc:PayableAmount>`)
+	assert.Contains(t, content, `<cbc:InvoicedQuantity unitCode="HUR">2</cbc:InvoicedQuantity>`)
+	assert.Equal(t, 3, strings.Count(content, "<cac:InvoiceLine>"))
+	assert.Equal(t, 2, strings.Count(content, "<cac:TaxSubtotal>"))
+}
+
+func TestGenerateXRechnungUBLCreditNote(t *testing.T) {
+	inv := testXRechnung()
+	inv.TypeCode = TypeCodeCreditNote
+	inv.PrecedingInvoice = &DocumentReference{Number: "RE-2023-1", IssueDate: time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC)}
+
+	out, err := GenerateUBL(inv, ProfileXRechnung)
+	assert.NoError(t, err)
+
+	var res interface{}
+	assert.NoError(t, xml.Unmarshal(out, &res))
+
+	content := string(out)
+	assert.Contains(t, content, `<CreditNote xmlns="urn:oasis:names:specification:ubl:schema:xsd:CreditNote-2"`)
+	assert.Contains(t, content, "<cbc:CreditNoteTypeCode>381</cbc:CreditNoteTypeCode>")
+	assert.Regexp(t, `<cac:InvoiceDocumentReference>\s*<cbc:ID>RE-2023-1</cbc:ID>\s*<cbc:IssueDate>2023-01-10</cbc:IssueDate>`, content)
+	assert.Contains(t, content, `<cbc:CreditedQuantity unitCode="HUR">2</cbc:CreditedQuantity>`)
+	assert.Equal(t, 3, strings.Count(content, "<cac:CreditNoteLine>"))
+	assert.NotContains(t, content, "InvoiceTypeCode")
+	assert.NotContains(t, content, "<cac:InvoiceLine>")
+
+	// the due date is part of the payment means
+	assert.NotContains(t, content, "<cbc:DueDate>")
+	assert.Regexp(t, `<cbc:PaymentMeansCode>58</cbc:PaymentMeansCode>\s*<cbc:PaymentDueDate>`, content)
+}
+
+func TestGenerateXRechnungCII(t *testing.T) {
+	out, err := GenerateCII(testXRechnung(), ProfileXRechnung)
+	assert.NoError(t, err)
+
+	content := string(out)
+	assert.Contains(t, content, "<ram:ID>urn:fdc:peppol.eu:2017:poacc:billing:01:1.0</ram:ID>")
+	assert.Contains(t, content, "<ram:ID>urn:cen.eu:en16931:2017#compliant#urn:xeinkauf.de:kosit:xrechnung_3.0</ram:ID>")
+	assert.Contains(t, content, "<ram:PersonName>Seller GmbH</ram:PersonName>")
+	assert.Contains(t, content, `<ram:URIID schemeID="0204">04011000-1234512345-06</ram:URIID>`)
+}
+
+func TestGenerateXRechnungValidation(t *testing.T) {
+	inv := testInvoice()
+	inv.Buyer.ElectronicAddress = "04011000-1234512345-07"
+	inv.Buyer.ElectronicAddressScheme = SchemeLeitwegID
+	inv.Payment = nil
+
+	_, err := GenerateUBL(inv, ProfileXRechnung)
+
+	valErr, ok := err.(*ValidationError)
+	assert.True(t, ok)
+	assert.Equal(t, []string{
+		"BR-DE-15 buyer reference",
+		"BR-DE-6 seller contact telephone number",
+		"BR-DE-8 buyer city",
+		"BR-DE-9 buyer post code",
+		"BT-49 valid Leitweg-ID",
+		"BR-DE-1 payment instructions",
+	}, valErr.Rules)
+}
+
+func TestGenerateUBLProfile(t *testing.T) {
+	_, err := GenerateUBL(testInvoice(), ProfileBasic)
+	assert.Error(t, err)
+
+	_, err = GenerateUBL(testInvoice(), ProfileEN16931)
+	assert.NoError(t, err)
+}
+
+func TestSyntaxParser(t *testing.T) {
+	syntax, err := SyntaxParser.Parse("UBL")
+	assert.NoError(t, err)
+	assert.Equal(t, SyntaxUBL, *syntax)
+
+	_, err = SyntaxParser.Parse("edifact")
+	assert.Error(t, err)
+}
@@ -0,0 +1,187 @@
+package fetcher
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// entry is a download, it is not changed after being cached
+type entry struct {
+	URL     string    `json:"url"`
+	ETag    string    `json:"etag,omitempty"`
+	Fetched time.Time `json:"fetched"`
+	Data    []byte    `json:"data"`
+}
+
+// cache keeps the last used downloads in memory and the last fetched downloads
+// in the directory if set. Errors of the directory are ignored, the download is
+// fetched again instead.
+type cache struct {
+	mu      sync.Mutex
+	size    int
+	dir     string
+	dirSize int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+func newCache(size int, dir string, dirSize int) (*cache, error) {
+	if len(dir) > 0 {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+
+	return &cache{
+		size:    size,
+		dir:     dir,
+		dirSize: dirSize,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}, nil
+}
+
+func (c *cache) get(url string) *entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[url]; ok {
+		c.order.MoveToFront(element)
+		return element.Value.(*entry)
+	}
+
+	e := c.read(url)
+	if e != nil {
+		c.add(e)
+	}
+
+	return e
+}
+
+func (c *cache) put(e *entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.add(e)
+	c.write(e)
+}
+
+// add puts the entry in front of the memory and removes the least recently
+// used entries
+func (c *cache) add(e *entry) {
+	if c.size <= 0 {
+		return
+	}
+
+	if element, ok := c.entries[e.URL]; ok {
+		element.Value = e
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[e.URL] = c.order.PushFront(e)
+
+	for c.order.Len() > c.size {
+		last := c.order.Back()
+		c.order.Remove(last)
+		delete(c.entries, last.Value.(*entry).URL)
+	}
+}
+
+func (c *cache) file(url string) string {
+	hash := sha256.Sum256([]byte(url))
+	return filepath.Join(c.dir, hex.EncodeToString(hash[:])+".json")
+}
+
+func (c *cache) read(url string) *entry {
+	if len(c.dir) == 0 {
+		return nil
+	}
+
+	content, err := os.ReadFile(c.file(url))
+	if err != nil {
+		return nil
+	}
+
+	e := &entry{}
+	if err := json.Unmarshal(content, e); err != nil || e.URL != url {
+		return nil
+	}
+
+	return e
+}
+
+// write replaces the file at once, so readers never see a partial entry
+func (c *cache) write(e *entry) {
+	if len(c.dir) == 0 {
+		return
+	}
+
+	content, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+
+	tmp, err := os.CreateTemp(c.dir, "*.tmp")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(content)
+	if closeErr := tmp.Close(); err != nil || closeErr != nil {
+		return
+	}
+
+	if err := os.Rename(tmp.Name(), c.file(e.URL)); err == nil {
+		c.prune()
+	}
+}
+
+// prune removes the least recently written files above the size of the
+// directory
+func (c *cache) prune() {
+	if c.dirSize <= 0 {
+		return
+	}
+
+	dirEntries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+
+	files := make([]os.FileInfo, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		if !strings.HasSuffix(dirEntry.Name(), ".json") {
+			continue
+		}
+
+		//removed in the meantime
+		info, err := dirEntry.Info()
+		if err != nil {
+			continue
+		}
+
+		files = append(files, info)
+	}
+
+	if len(files) <= c.dirSize {
+		return
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].ModTime().Before(files[j].ModTime())
+	})
+
+	for _, info := range files[:len(files)-c.dirSize] {
+		os.Remove(filepath.Join(c.dir, info.Name()))
+	}
+}
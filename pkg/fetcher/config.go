@@ -0,0 +1,43 @@
+package fetcher
+
+import "time"
+
+type Config struct {
+	//timeout of a download including redirects
+	Timeout time.Duration `env:"FETCH_TIMEOUT, default=10s"`
+	//max size of a downloaded image in bytes
+	MaxBytes int64 `env:"FETCH_MAX_BYTES, default=5000000"`
+
+	//only these hosts and their subdomains can be fetched when set
+	AllowHosts []string `env:"FETCH_ALLOW_HOSTS"`
+	//these hosts and their subdomains cannot be fetched
+	DenyHosts []string `env:"FETCH_DENY_HOSTS"`
+	//allows loopback, link-local and private addresses, only for development
+	AllowPrivate bool `env:"FETCH_ALLOW_PRIVATE, default=false"`
+
+	//downloads are used without asking the host again within this time, later
+	//they are revalidated with their ETag
+	CacheTTL time.Duration `env:"FETCH_CACHE_TTL, default=5m"`
+	//number of downloads kept in memory
+	CacheSize int `env:"FETCH_CACHE_SIZE, default=64"`
+	//directory keeping the downloads over restarts, not used when not set
+	CacheDir string `env:"FETCH_CACHE_DIR"`
+	//number of downloads kept in the directory, the least recently fetched are
+	//removed
+	CacheDirSize int `env:"FETCH_CACHE_DIR_SIZE, default=1024"`
+}
+
+func (c *Config) FetcherServiceConfig() *Config {
+	return c
+}
+
+// DefaultConfig returns the defaults of the environment variables.
+func DefaultConfig() *Config {
+	return &Config{
+		Timeout:      10 * time.Second,
+		MaxBytes:     5_000_000,
+		CacheTTL:     5 * time.Minute,
+		CacheSize:    64,
+		CacheDirSize: 1024,
+	}
+}
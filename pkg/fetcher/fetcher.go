@@ -0,0 +1,238 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrScheme      = errors.New("only http and https urls can be fetched")
+	ErrHost        = errors.New("the host cannot be fetched")
+	ErrAddress     = errors.New("the address of the host is not public")
+	ErrTooLarge    = errors.New("the download is too large")
+	ErrContentType = errors.New("the download is not an image")
+	ErrRedirects   = errors.New("too many redirects")
+)
+
+// maxRedirects is the number of redirects followed by a download
+const maxRedirects = 5
+
+// nonPublicNetworks are blocked in addition to the loopback, link-local,
+// private, multicast and unspecified addresses known by net.IP
+var nonPublicNetworks = []*net.IPNet{
+	mustParseCIDR("0.0.0.0/8"),
+	mustParseCIDR("100.64.0.0/10"),
+	mustParseCIDR("192.0.0.0/24"),
+	mustParseCIDR("198.18.0.0/15"),
+	mustParseCIDR("240.0.0.0/4"),
+	mustParseCIDR("64:ff9b::/96"),
+}
+
+func mustParseCIDR(s string) *net.IPNet {
+	_, network, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return network
+}
+
+// Fetcher downloads images from public hosts. The addresses are checked when
+// connecting, so neither redirects nor DNS answers can reach internal hosts.
+type Fetcher struct {
+	config *Config
+	client *http.Client
+	cache  *cache
+}
+
+// NewFetcher creates a fetcher, the cache directory is created if it does not
+// exist.
+func NewFetcher(config *Config) (*Fetcher, error) {
+	f := &Fetcher{config: config}
+
+	dialer := &net.Dialer{
+		Timeout: config.Timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			return f.checkAddress(address)
+		},
+	}
+
+	f.client = &http.Client{
+		Timeout: config.Timeout,
+		Transport: &http.Transport{
+			//a proxy would connect instead of the checked dialer
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   config.Timeout,
+			ResponseHeaderTimeout: config.Timeout,
+			MaxIdleConns:          10,
+			IdleConnTimeout:       90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return ErrRedirects
+			}
+			return f.checkURL(req.URL)
+		},
+	}
+
+	cache, err := newCache(config.CacheSize, config.CacheDir, config.CacheDirSize)
+	if err != nil {
+		return nil, err
+	}
+	f.cache = cache
+
+	return f, nil
+}
+
+// Fetch returns the image of the url, from the cache if it is fresh or not
+// modified.
+func (f *Fetcher) Fetch(rawURL string) ([]byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := f.checkURL(u); err != nil {
+		return nil, err
+	}
+
+	cached := f.cache.get(rawURL)
+	if cached != nil && time.Since(cached.Fetched) < f.config.CacheTTL {
+		return cached.Data, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), f.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached != nil && len(cached.ETag) > 0 {
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		refreshed := *cached
+		refreshed.Fetched = time.Now()
+		f.cache.put(&refreshed)
+
+		return refreshed.Data, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("wrong status @ get request: %s", resp.Status)
+	}
+
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("content-type"))
+	if err != nil || !strings.HasPrefix(mediaType, "image/") {
+		return nil, fmt.Errorf("%w: %s", ErrContentType, resp.Header.Get("content-type"))
+	}
+
+	if resp.ContentLength > f.config.MaxBytes {
+		return nil, ErrTooLarge
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, f.config.MaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > f.config.MaxBytes {
+		return nil, ErrTooLarge
+	}
+
+	f.cache.put(&entry{
+		URL:     rawURL,
+		ETag:    resp.Header.Get("ETag"),
+		Fetched: time.Now(),
+		Data:    data,
+	})
+
+	return data, nil
+}
+
+// checkURL checks the scheme and the host lists
+func (f *Fetcher) checkURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ErrScheme
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+
+	if len(f.config.AllowHosts) > 0 && !matchesHost(host, f.config.AllowHosts) {
+		return fmt.Errorf("%w: %s is not allowed", ErrHost, host)
+	}
+
+	if matchesHost(host, f.config.DenyHosts) {
+		return fmt.Errorf("%w: %s is denied", ErrHost, host)
+	}
+
+	return nil
+}
+
+// matchesHost reports whether the host is one of the hosts or a subdomain
+func matchesHost(host string, hosts []string) bool {
+	for _, h := range hosts {
+		h = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(h)), "*.")
+		if len(h) == 0 {
+			continue
+		}
+
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkAddress blocks connections to non-public addresses, the address is
+// already resolved
+func (f *Fetcher) checkAddress(address string) error {
+	if f.config.AllowPrivate {
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !isPublic(ip) {
+		return fmt.Errorf("%w: %s", ErrAddress, host)
+	}
+
+	return nil
+}
+
+func isPublic(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,202 @@
+package fetcher
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testConfig allows the loopback address of the test servers
+func testConfig() *Config {
+	config := DefaultConfig()
+	config.AllowPrivate = true
+	return config
+}
+
+func imageServer(t *testing.T, requests *int32) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+
+		switch r.URL.Path {
+		case "/logo.png":
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("content-type", "image/png")
+			w.Header().Set("ETag", `"v1"`)
+			w.Write([]byte("logo"))
+		case "/page.html":
+			w.Header().Set("content-type", "text/html")
+			w.Write([]byte("<html/>"))
+		case "/large.png":
+			w.Header().Set("content-type", "image/png")
+			w.Write([]byte(strings.Repeat("x", 100)))
+		case "/redirect":
+			http.Redirect(w, r, "http://localhost"+strings.TrimPrefix(r.Host, "127.0.0.1")+"/logo.png", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestFetch(t *testing.T) {
+	var requests int32
+	server := imageServer(t, &requests)
+
+	f, err := NewFetcher(testConfig())
+	assert.NoError(t, err)
+
+	data, err := f.Fetch(server.URL + "/logo.png")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("logo"), data)
+
+	// fresh downloads are taken from the cache
+	data, err = f.Fetch(server.URL + "/logo.png")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("logo"), data)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	_, err = f.Fetch(server.URL + "/page.html")
+	assert.ErrorIs(t, err, ErrContentType)
+
+	_, err = f.Fetch(server.URL + "/missing.png")
+	assert.Error(t, err)
+
+	_, err = f.Fetch("file:///etc/passwd")
+	assert.ErrorIs(t, err, ErrScheme)
+
+	config := testConfig()
+	config.MaxBytes = 10
+	f, err = NewFetcher(config)
+	assert.NoError(t, err)
+
+	_, err = f.Fetch(server.URL + "/large.png")
+	assert.ErrorIs(t, err, ErrTooLarge)
+}
+
+func TestFetchRevalidate(t *testing.T) {
+	var requests int32
+	server := imageServer(t, &requests)
+
+	config := testConfig()
+	config.CacheTTL = 0
+	config.CacheDir = t.TempDir()
+
+	f, err := NewFetcher(config)
+	assert.NoError(t, err)
+
+	_, err = f.Fetch(server.URL + "/logo.png")
+	assert.NoError(t, err)
+
+	// the cache of the directory is used after a restart, the server answers
+	// the ETag with not modified
+	f, err = NewFetcher(config)
+	assert.NoError(t, err)
+
+	data, err := f.Fetch(server.URL + "/logo.png")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("logo"), data)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+}
+
+func TestFetchBlocked(t *testing.T) {
+	var requests int32
+	server := imageServer(t, &requests)
+
+	// loopback is not public
+	f, err := NewFetcher(DefaultConfig())
+	assert.NoError(t, err)
+
+	_, err = f.Fetch(server.URL + "/logo.png")
+	assert.ErrorIs(t, err, ErrAddress)
+
+	// the host lists are checked for redirects too
+	config := testConfig()
+	config.DenyHosts = []string{"localhost"}
+	f, err = NewFetcher(config)
+	assert.NoError(t, err)
+
+	_, err = f.Fetch(server.URL + "/redirect")
+	assert.ErrorIs(t, err, ErrHost)
+
+	config = testConfig()
+	config.AllowHosts = []string{"*.example.com"}
+	f, err = NewFetcher(config)
+	assert.NoError(t, err)
+
+	_, err = f.Fetch(server.URL + "/logo.png")
+	assert.ErrorIs(t, err, ErrHost)
+
+	// only the redirect reached the server
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
+
+func TestMatchesHost(t *testing.T) {
+	hosts := []string{"example.com", "*.cdn.net"}
+
+	assert.True(t, matchesHost("example.com", hosts))
+	assert.True(t, matchesHost("img.example.com", hosts))
+	assert.True(t, matchesHost("a.cdn.net", hosts))
+	assert.False(t, matchesHost("badexample.com", hosts))
+	assert.False(t, matchesHost("example.org", hosts))
+}
+
+func TestIsPublic(t *testing.T) {
+	for _, ip := range []string{"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254",
+		"100.64.0.1", "0.0.0.0", "::1", "fe80::1", "fd00::1", "::ffff:127.0.0.1", "224.0.0.1"} {
+		assert.False(t, isPublic(net.ParseIP(ip)), ip)
+	}
+
+	for _, ip := range []string{"93.184.216.34", "2606:2800:220:1:248:1893:25c8:1946"} {
+		assert.True(t, isPublic(net.ParseIP(ip)), ip)
+	}
+}
+
+func TestCache(t *testing.T) {
+	c, err := newCache(2, "", 0)
+	assert.NoError(t, err)
+
+	for _, url := range []string{"a", "b", "c"} {
+		c.put(&entry{URL: url, Fetched: time.Now()})
+	}
+
+	// the least recently used entry is removed
+	assert.Nil(t, c.get("a"))
+	assert.NotNil(t, c.get("b"))
+	assert.NotNil(t, c.get("c"))
+}
+
+func TestCacheDirSize(t *testing.T) {
+	dir := t.TempDir()
+
+	c, err := newCache(0, dir, 2)
+	assert.NoError(t, err)
+
+	for i, url := range []string{"a", "b", "c"} {
+		c.put(&entry{URL: url, Fetched: time.Now()})
+
+		// the modification times have to differ for the order
+		modTime := time.Now().Add(time.Duration(i-3) * time.Minute)
+		assert.NoError(t, os.Chtimes(c.file(url), modTime, modTime))
+	}
+
+	files, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 2)
+
+	// the least recently written file is removed
+	assert.Nil(t, c.get("a"))
+	assert.NotNil(t, c.get("b"))
+	assert.NotNil(t, c.get("c"))
+}
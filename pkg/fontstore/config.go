@@ -0,0 +1,10 @@
+package fontstore
+
+type Config struct {
+	//directory with the TTF files of the font families, loaded at startup
+	FontDir string `env:"FONT_DIR"`
+}
+
+func (c *Config) FontStoreServiceConfig() *Config {
+	return c
+}
@@ -0,0 +1,110 @@
+package fontstore
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hodl-repos/pdf-invoice/pkg/document"
+)
+
+// styleSuffixes maps the suffixes of the file names to the font styles.
+var styleSuffixes = map[string]string{
+	"regular":     "",
+	"bold":        "B",
+	"italic":      "I",
+	"oblique":     "I",
+	"bolditalic":  "BI",
+	"boldoblique": "BI",
+}
+
+// FontStore holds the font families which can be used by documents in
+// addition to the bundled font.
+type FontStore struct {
+	fonts map[string]*document.Font
+}
+
+// NewFontStore loads all TTF files of the configured directory. The file name
+// defines the family and the style: <Family>-<Style>.ttf with the styles
+// Regular, Bold, Italic and BoldItalic, e.g. OpenSans-Bold.ttf. Files without
+// style are regular.
+func NewFontStore(config *Config) (*FontStore, error) {
+	store := &FontStore{fonts: make(map[string]*document.Font)}
+
+	if len(config.FontDir) == 0 {
+		return store, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(config.FontDir, "*.ttf"))
+	if err != nil {
+		return nil, err
+	}
+
+	families := make(map[string]map[string]string)
+	names := make(map[string]string)
+
+	for _, file := range files {
+		family, style := parseFileName(filepath.Base(file))
+
+		key := strings.ToLower(family)
+		if _, ok := families[key]; !ok {
+			families[key] = make(map[string]string)
+			names[key] = family
+		}
+		families[key][style] = file
+	}
+
+	for key, styles := range families {
+		font, err := document.LoadFont(names[key], styles)
+		if err != nil {
+			return nil, fmt.Errorf("font %s: %w", names[key], err)
+		}
+
+		store.fonts[key] = font
+	}
+
+	return store, nil
+}
+
+// parseFileName splits the file name into family and style.
+func parseFileName(name string) (string, string) {
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+
+	idx := strings.LastIndex(name, "-")
+	if idx < 0 {
+		return name, ""
+	}
+
+	if style, ok := styleSuffixes[strings.ToLower(name[idx+1:])]; ok {
+		return name[:idx], style
+	}
+
+	return name, ""
+}
+
+// Get returns the font family, the name is case insensitive.
+func (s *FontStore) Get(family string) (*document.Font, bool) {
+	if s == nil {
+		return nil, false
+	}
+
+	font, ok := s.fonts[strings.ToLower(family)]
+	return font, ok
+}
+
+// Families returns the names of all font families sorted by name.
+func (s *FontStore) Families() []string {
+	if s == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(s.fonts))
+	for _, font := range s.fonts {
+		names = append(names, font.Family)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,63 @@
+package fontstore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func copyFont(t *testing.T, dir, name string) {
+	content, err := os.ReadFile("../document/fonts/DejaVuSansCondensed.ttf")
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, name), content, 0o644))
+}
+
+func TestParseFileName(t *testing.T) {
+	tests := []struct {
+		name, family, style string
+	}{
+		{"OpenSans-Regular.ttf", "OpenSans", ""},
+		{"OpenSans-Bold.ttf", "OpenSans", "B"},
+		{"Open-Sans-BoldItalic.ttf", "Open-Sans", "BI"},
+		{"Brand.ttf", "Brand", ""},
+		{"Brand-Condensed.ttf", "Brand-Condensed", ""},
+	}
+
+	for _, test := range tests {
+		family, style := parseFileName(test.name)
+		assert.Equal(t, test.family, family, test.name)
+		assert.Equal(t, test.style, style, test.name)
+	}
+}
+
+func TestNewFontStore(t *testing.T) {
+	dir := t.TempDir()
+	copyFont(t, dir, "Brand-Regular.ttf")
+	copyFont(t, dir, "Brand-Bold.ttf")
+	copyFont(t, dir, "Corporate.ttf")
+
+	store, err := NewFontStore(&Config{FontDir: dir})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Brand", "Corporate"}, store.Families())
+
+	font, ok := store.Get("brand")
+	assert.True(t, ok)
+	assert.Len(t, font.Styles, 4)
+
+	_, ok = store.Get("unknown")
+	assert.False(t, ok)
+
+	store, err = NewFontStore(&Config{})
+	assert.NoError(t, err)
+	assert.Empty(t, store.Families())
+}
+
+func TestNewFontStoreInvalid(t *testing.T) {
+	dir := t.TempDir()
+	copyFont(t, dir, "Brand-Bold.ttf")
+
+	_, err := NewFontStore(&Config{FontDir: dir})
+	assert.Error(t, err)
+}
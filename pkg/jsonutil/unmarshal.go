@@ -78,6 +78,13 @@ func (v *UnmarshalError) Error() string {
 // Unmarshal provides a common implemetation of JSON unmarshalling with well
 // defined error handling
 func UnmarshalWithError(w http.ResponseWriter, r *http.Request, data interface{}) error {
+	return UnmarshalWithErrorLimit(w, r, data, maxBodyBytes)
+}
+
+// UnmarshalWithErrorLimit is UnmarshalWithError with a request size of
+// maxBytes instead of the default 64KB, for endpoints accepting larger
+// payloads
+func UnmarshalWithErrorLimit(w http.ResponseWriter, r *http.Request, data interface{}, maxBytes int64) error {
 	if t := r.Header.Get("content-type"); len(t) < 16 || t[:16] != "application/json" {
 		return &UnmarshalError{
 			StandardisedError: generateStandardisedErrorUnmarshal("content-type is not application/json",
@@ -88,7 +95,7 @@ func UnmarshalWithError(w http.ResponseWriter, r *http.Request, data interface{}
 
 	defer r.Body.Close()
 
-	r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
 
 	d := json.NewDecoder(r.Body)
 	d.DisallowUnknownFields()
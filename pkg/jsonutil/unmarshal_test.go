@@ -3,6 +3,7 @@ package jsonutil
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -33,6 +34,27 @@ func TestBodyTooLarge(t *testing.T) {
 	unmarshalTestHelper(t, []string{string(largeJSON)}, errors, http.StatusRequestEntityTooLarge)
 }
 
+func TestBodyLimit(t *testing.T) {
+	t.Parallel()
+
+	largeJSON := `{"name": "` + strings.Repeat("0", maxBodyBytes) + `"}`
+
+	unmarshal := func(maxBytes int64) error {
+		r := httptest.NewRequest("POST", "/", strings.NewReader(largeJSON))
+		r.Header.Set("content-type", "application/json")
+
+		return UnmarshalWithErrorLimit(httptest.NewRecorder(), r, &testData{}, maxBytes)
+	}
+
+	var unmarshalErr *UnmarshalError
+	if err := unmarshal(maxBodyBytes); !errors.As(err, &unmarshalErr) || unmarshalErr.Status != http.StatusRequestEntityTooLarge {
+		t.Errorf("unmarshal with the default limit expected a too large error, got %v", err)
+	}
+	if err := unmarshal(2 * maxBodyBytes); err != nil {
+		t.Errorf("unmarshal with a raised limit expected no error, got %v", err)
+	}
+}
+
 func TestInvalidHeader(t *testing.T) {
 	t.Parallel()
 
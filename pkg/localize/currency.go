@@ -0,0 +1,31 @@
+package localize
+
+import (
+	"golang.org/x/text/currency"
+)
+
+// symbolAfterAmount contains the regions writing the currency symbol after the
+// amount (12,50 €), all other regions write it before the amount (€ 12.50).
+var symbolAfterAmount = map[string]bool{
+	"BE": true, "BG": true, "CZ": true, "DE": true, "DK": true, "EE": true,
+	"ES": true, "FI": true, "FR": true, "GR": true, "HR": true, "HU": true,
+	"IT": true, "LT": true, "LU": true, "LV": true, "NO": true, "PL": true,
+	"PT": true, "RO": true, "SE": true, "SI": true, "SK": true,
+}
+
+// FMoney formats the amount with the minor units of the currency (ISO 4217)
+// and places the currency symbol as usual in the region of the client.
+func (client *LocalizeClient) FMoney(amount float64, cur currency.Unit) string {
+	scale, _ := currency.Standard.Rounding(cur)
+
+	number := client.printer.Sprintf("%.*f", scale, amount)
+	symbol := client.printer.Sprint(currency.Symbol(cur))
+
+	//non-breaking space, amount and symbol must not be wrapped
+	region, _ := client.tag.Region()
+	if symbolAfterAmount[region.String()] {
+		return number + "\u00a0" + symbol
+	}
+
+	return symbol + "\u00a0" + number
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/currency"
 )
 
 func TestNumberFormat(t *testing.T) {
@@ -13,3 +14,17 @@ func TestNumberFormat(t *testing.T) {
 
 	assert.Equal(t, "123.456.789,92", output)
 }
+
+func TestMoneyFormat(t *testing.T) {
+	client := func(lang, locale string) *LocalizeClient {
+		tag := createTag(lang, locale)
+		return &LocalizeClient{printer: createPrinter(lang, locale), tag: tag}
+	}
+
+	assert.Equal(t, "1.234,50\u00a0€", client("de", "de").FMoney(1234.5, currency.EUR))
+	assert.Equal(t, "€\u00a01\u00a0234,50", client("de", "at").FMoney(1234.5, currency.EUR))
+	assert.Equal(t, "CHF\u00a01’234.50", client("de", "ch").FMoney(1234.5, currency.CHF))
+	assert.Equal(t, "£\u00a01,234.50", client("en", "gb").FMoney(1234.5, currency.GBP))
+	assert.Equal(t, "¥\u00a01,235", client("en", "us").FMoney(1235, currency.JPY))
+	assert.Equal(t, "1\u00a0234,50\u00a0Ft", client("hu", "hu").FMoney(1234.5, currency.MustParseISO("HUF")))
+}
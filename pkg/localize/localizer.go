@@ -1,11 +1,11 @@
 package localize
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/BurntSushi/toml"
 	go2 "github.com/adam-hanna/arrayOperations"
+	"github.com/hodl-repos/pdf-invoice/pkg/logging"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
@@ -20,6 +20,7 @@ type LocalizeClient struct {
 	service   *LocalizeService
 	localizer *i18n.Localizer
 	printer   *message.Printer
+	tag       language.Tag
 }
 
 // NewLogger creates a new logger with the given configuration.
@@ -27,7 +28,7 @@ func NewLocalizeService(config *Config) *LocalizeService {
 	langs := strings.Split(config.LangKeys, ",")
 
 	if len(langs) == 0 {
-		fmt.Sprintln("only english will be avaliable")
+		logging.DefaultLogger().Warn("only english will be avaliable")
 	}
 
 	defLang := language.MustParse("en")
@@ -86,17 +87,22 @@ func (service *LocalizeService) CreateClient(locale string, preferedLangs ...str
 	localizer := service.createLocalizer(preferedLangs...)
 
 	bestLang := preferedLangs[0]
+	tag := createTag(bestLang, locale)
 
 	return &LocalizeClient{
 		service:   service,
 		localizer: localizer,
-		printer:   createPrinter(bestLang, locale),
+		printer:   message.NewPrinter(tag),
+		tag:       tag,
 	}
 }
 
+func createTag(bestLang, locale string) language.Tag {
+	return language.Make(bestLang + "-" + strings.ToUpper(locale))
+}
+
 func createPrinter(bestLang, locale string) *message.Printer {
-	lang := language.Make(bestLang + "-" + strings.ToUpper(locale))
-	return message.NewPrinter(lang)
+	return message.NewPrinter(createTag(bestLang, locale))
 }
 
 func (client *LocalizeClient) FFloat32(data float32) string {
@@ -65,6 +65,186 @@ var (
 			ID:    "ContractingParty",
 			Other: "Contracting Party",
 		},
+		{
+			ID:    "BuyerReference",
+			Other: "Your reference",
+		},
+		{
+			ID:    "LeitwegID",
+			Other: "Leitweg-ID",
+		},
+		{
+			ID:    "TaxRate",
+			Other: "Tax rate",
+		},
+		{
+			ID:    "TaxableAmount",
+			Other: "Taxable amount",
+		},
+		{
+			ID:    "Total",
+			Other: "Total",
+		},
+		{
+			ID:    "QrBillReceipt",
+			Other: "Receipt",
+		},
+		{
+			ID:    "QrBillPaymentPart",
+			Other: "Payment part",
+		},
+		{
+			ID:    "QrBillAccount",
+			Other: "Account / Payable to",
+		},
+		{
+			ID:    "QrBillReference",
+			Other: "Reference",
+		},
+		{
+			ID:    "QrBillAdditionalInformation",
+			Other: "Additional information",
+		},
+		{
+			ID:    "QrBillPayableBy",
+			Other: "Payable by",
+		},
+		{
+			ID:    "QrBillPayableByNameAddress",
+			Other: "Payable by (name/address)",
+		},
+		{
+			ID:    "QrBillCurrency",
+			Other: "Currency",
+		},
+		{
+			ID:    "QrBillAmount",
+			Other: "Amount",
+		},
+		{
+			ID:    "QrBillAcceptancePoint",
+			Other: "Acceptance point",
+		},
+		{
+			ID:    "QrBillSeparate",
+			Other: "Separate before paying in",
+		},
+		{
+			ID:    "CreditNoteNumber",
+			Other: "Credit note no.",
+		},
+		{
+			ID:    "CorrectedInvoice",
+			Other: "Corrected invoice",
+		},
+		{
+			ID:    "CorrectedInvoiceDate",
+			Other: "Invoice date",
+		},
+		{
+			ID:    "Invoice",
+			Other: "Invoice",
+		},
+		{
+			ID:    "Offer",
+			Other: "Offer",
+		},
+		{
+			ID:    "CreditNote",
+			Other: "Credit note",
+		},
+		{
+			ID:    "PaymentReminder",
+			Other: "Payment reminder",
+		},
+		{
+			ID:    "SecondReminder",
+			Other: "Second reminder",
+		},
+		{
+			ID:    "FinalNotice",
+			Other: "Final notice",
+		},
+		{
+			ID:    "DunningTextPaymentReminder",
+			Other: "We would like to remind you that the following invoices are still open. Please transfer the outstanding amount of {{.Amount}} by {{.DueDate}}. If you have already paid, please disregard this letter.",
+		},
+		{
+			ID:    "DunningTextSecondReminder",
+			Other: "Unfortunately we have not received your payment for the following invoices despite our reminder. Please transfer the outstanding amount of {{.Amount}} by {{.DueDate}} at the latest.",
+		},
+		{
+			ID:    "DunningTextFinalNotice",
+			Other: "Despite our reminders the following invoices are still unpaid. Please transfer the outstanding amount of {{.Amount}} by {{.DueDate}}, otherwise we will hand the claim over to collection without further notice.",
+		},
+		{
+			ID:    "PaymentDueBy",
+			Other: "Payable by",
+		},
+		{
+			ID:    "Paid",
+			Other: "Paid",
+		},
+		{
+			ID:    "Fees",
+			Other: "Fees",
+		},
+		{
+			ID:    "Interest",
+			Other: "Interest",
+		},
+		{
+			ID:    "Outstanding",
+			Other: "Outstanding",
+		},
+		{
+			ID:    "DunningNumber",
+			Other: "Reminder no.",
+		},
+		{
+			ID:    "InvoiceAmount",
+			Other: "Amount",
+		},
+		{
+			ID:    "DueOn",
+			Other: "Due",
+		},
+		{
+			ID:    "DeliveryNote",
+			Other: "Delivery note",
+		},
+		{
+			ID:    "DeliveryNoteNumber",
+			Other: "Delivery note no.",
+		},
+		{
+			ID:    "OrderConfirmation",
+			Other: "Order confirmation",
+		},
+		{
+			ID:    "OrderNumber",
+			Other: "Order no.",
+		},
+		{
+			ID:    "SerialNumber",
+			Other: "Serial no.",
+		},
+		{
+			ID:    "BatchNumber",
+			Other: "Batch no.",
+		},
+		{
+			ID:    "GoodsReceived",
+			Other: "Goods received in good condition",
+		},
+		{
+			ID:    "PlaceAndDate",
+			Other: "Place, date",
+		},
+		{
+			ID:    "Signature",
+			Other: "Signature",
+		},
 	}
 )
 
@@ -161,3 +341,285 @@ func (client *LocalizeClient) TranslateContractingParty() string {
 		MessageID: "ContractingParty",
 	})
 }
+
+func (client *LocalizeClient) TranslateBuyerReference() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "BuyerReference",
+	})
+}
+
+func (client *LocalizeClient) TranslateLeitwegID() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "LeitwegID",
+	})
+}
+
+func (client *LocalizeClient) TranslateTaxRate() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "TaxRate",
+	})
+}
+
+func (client *LocalizeClient) TranslateTaxableAmount() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "TaxableAmount",
+	})
+}
+
+func (client *LocalizeClient) TranslateTotal() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "Total",
+	})
+}
+
+func (client *LocalizeClient) TranslateQrBillReceipt() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "QrBillReceipt",
+	})
+}
+
+func (client *LocalizeClient) TranslateQrBillPaymentPart() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "QrBillPaymentPart",
+	})
+}
+
+func (client *LocalizeClient) TranslateQrBillAccount() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "QrBillAccount",
+	})
+}
+
+func (client *LocalizeClient) TranslateQrBillReference() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "QrBillReference",
+	})
+}
+
+func (client *LocalizeClient) TranslateQrBillAdditionalInformation() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "QrBillAdditionalInformation",
+	})
+}
+
+func (client *LocalizeClient) TranslateQrBillPayableBy() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "QrBillPayableBy",
+	})
+}
+
+func (client *LocalizeClient) TranslateQrBillPayableByNameAddress() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "QrBillPayableByNameAddress",
+	})
+}
+
+func (client *LocalizeClient) TranslateQrBillCurrency() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "QrBillCurrency",
+	})
+}
+
+func (client *LocalizeClient) TranslateQrBillAmount() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "QrBillAmount",
+	})
+}
+
+func (client *LocalizeClient) TranslateQrBillAcceptancePoint() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "QrBillAcceptancePoint",
+	})
+}
+
+func (client *LocalizeClient) TranslateQrBillSeparate() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "QrBillSeparate",
+	})
+}
+
+func (client *LocalizeClient) TranslateCreditNoteNumber() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "CreditNoteNumber",
+	})
+}
+
+func (client *LocalizeClient) TranslateCorrectedInvoice() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "CorrectedInvoice",
+	})
+}
+
+func (client *LocalizeClient) TranslateCorrectedInvoiceDate() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "CorrectedInvoiceDate",
+	})
+}
+
+func (client *LocalizeClient) TranslateInvoice() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "Invoice",
+	})
+}
+
+func (client *LocalizeClient) TranslateOffer() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "Offer",
+	})
+}
+
+func (client *LocalizeClient) TranslateCreditNote() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "CreditNote",
+	})
+}
+
+func (client *LocalizeClient) TranslatePaymentReminder() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "PaymentReminder",
+	})
+}
+
+func (client *LocalizeClient) TranslateSecondReminder() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "SecondReminder",
+	})
+}
+
+func (client *LocalizeClient) TranslateFinalNotice() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "FinalNotice",
+	})
+}
+
+func (client *LocalizeClient) TranslatePaymentDueBy() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "PaymentDueBy",
+	})
+}
+
+func (client *LocalizeClient) TranslatePaid() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "Paid",
+	})
+}
+
+func (client *LocalizeClient) TranslateFees() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "Fees",
+	})
+}
+
+func (client *LocalizeClient) TranslateInterest() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "Interest",
+	})
+}
+
+func (client *LocalizeClient) TranslateOutstanding() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "Outstanding",
+	})
+}
+
+func (client *LocalizeClient) TranslateDunningTextPaymentReminder(amount, dueDate string) string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "DunningTextPaymentReminder",
+		TemplateData: map[string]interface{}{
+			"Amount":  amount,
+			"DueDate": dueDate,
+		},
+	})
+}
+
+func (client *LocalizeClient) TranslateDunningTextSecondReminder(amount, dueDate string) string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "DunningTextSecondReminder",
+		TemplateData: map[string]interface{}{
+			"Amount":  amount,
+			"DueDate": dueDate,
+		},
+	})
+}
+
+func (client *LocalizeClient) TranslateDunningTextFinalNotice(amount, dueDate string) string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "DunningTextFinalNotice",
+		TemplateData: map[string]interface{}{
+			"Amount":  amount,
+			"DueDate": dueDate,
+		},
+	})
+}
+
+func (client *LocalizeClient) TranslateDunningNumber() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "DunningNumber",
+	})
+}
+
+func (client *LocalizeClient) TranslateInvoiceAmount() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "InvoiceAmount",
+	})
+}
+
+func (client *LocalizeClient) TranslateDueOn() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "DueOn",
+	})
+}
+
+func (client *LocalizeClient) TranslateDeliveryNote() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "DeliveryNote",
+	})
+}
+
+func (client *LocalizeClient) TranslateDeliveryNoteNumber() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "DeliveryNoteNumber",
+	})
+}
+
+func (client *LocalizeClient) TranslateOrderConfirmation() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "OrderConfirmation",
+	})
+}
+
+func (client *LocalizeClient) TranslateOrderNumber() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "OrderNumber",
+	})
+}
+
+func (client *LocalizeClient) TranslateSerialNumber() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "SerialNumber",
+	})
+}
+
+func (client *LocalizeClient) TranslateBatchNumber() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "BatchNumber",
+	})
+}
+
+func (client *LocalizeClient) TranslateGoodsReceived() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "GoodsReceived",
+	})
+}
+
+func (client *LocalizeClient) TranslatePlaceAndDate() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "PlaceAndDate",
+	})
+}
+
+func (client *LocalizeClient) TranslateSignature() string {
+	return client.localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID: "Signature",
+	})
+}
@@ -0,0 +1,10 @@
+package profilestore
+
+type Config struct {
+	//directory with the JSON files of the profiles, kept in memory when not set
+	ProfileDir string `env:"PROFILE_DIR"`
+}
+
+func (c *Config) ProfileStoreServiceConfig() *Config {
+	return c
+}
@@ -0,0 +1,179 @@
+package profilestore
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+var ErrProfileNotFound = errors.New("the profile does not exist")
+
+// ProfileStore holds the profiles of the tenants as JSON documents, the
+// content of the documents is given by the caller.
+type ProfileStore struct {
+	mu       sync.RWMutex
+	dir      string
+	profiles map[uuid.UUID]json.RawMessage
+}
+
+// NewProfileStore loads all profiles of the configured directory, the files
+// are named <id>.json. The directory is created if it does not exist. Without
+// directory the profiles are only kept in memory.
+func NewProfileStore(config *Config) (*ProfileStore, error) {
+	store := &ProfileStore{
+		dir:      config.ProfileDir,
+		profiles: make(map[uuid.UUID]json.RawMessage),
+	}
+
+	if len(store.dir) == 0 {
+		return store, nil
+	}
+
+	if err := os.MkdirAll(store.dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	files, err := filepath.Glob(filepath.Join(store.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		id, err := uuid.Parse(strings.TrimSuffix(filepath.Base(file), ".json"))
+		if err != nil {
+			return nil, fmt.Errorf("profile %s: the file name is not an id", filepath.Base(file))
+		}
+
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		if !json.Valid(content) {
+			return nil, fmt.Errorf("profile %s: invalid json", filepath.Base(file))
+		}
+
+		store.profiles[id] = content
+	}
+
+	return store, nil
+}
+
+// Create stores a new profile and returns its id.
+func (s *ProfileStore) Create(data json.RawMessage) (uuid.UUID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := uuid.New()
+
+	if err := s.write(id, data); err != nil {
+		return uuid.Nil, err
+	}
+
+	return id, nil
+}
+
+// Get returns the profile.
+func (s *ProfileStore) Get(id uuid.UUID) (json.RawMessage, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	data, ok := s.profiles[id]
+	return data, ok
+}
+
+// Update replaces an existing profile.
+func (s *ProfileStore) Update(id uuid.UUID, data json.RawMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.profiles[id]; !ok {
+		return ErrProfileNotFound
+	}
+
+	return s.write(id, data)
+}
+
+// Delete removes an existing profile.
+func (s *ProfileStore) Delete(id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.profiles[id]; !ok {
+		return ErrProfileNotFound
+	}
+
+	if len(s.dir) > 0 {
+		if err := os.Remove(s.file(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	delete(s.profiles, id)
+
+	return nil
+}
+
+// IDs returns the ids of all profiles sorted.
+func (s *ProfileStore) IDs() []uuid.UUID {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := make([]uuid.UUID, 0, len(s.profiles))
+	for id := range s.profiles {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		return bytes.Compare(ids[i][:], ids[j][:]) < 0
+	})
+
+	return ids
+}
+
+// write stores the profile in memory and in the directory, the file is
+// replaced at once so a crash does not leave a partial profile
+func (s *ProfileStore) write(id uuid.UUID, data json.RawMessage) error {
+	if !json.Valid(data) {
+		return fmt.Errorf("profile %s: invalid json", id)
+	}
+
+	//the caller may reuse the buffer
+	data = append(json.RawMessage(nil), data...)
+
+	if len(s.dir) > 0 {
+		tmp, err := os.CreateTemp(s.dir, id.String()+".*.tmp")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(tmp.Name())
+
+		if _, err := tmp.Write(data); err != nil {
+			tmp.Close()
+			return err
+		}
+		if err := tmp.Close(); err != nil {
+			return err
+		}
+
+		if err := os.Rename(tmp.Name(), s.file(id)); err != nil {
+			return err
+		}
+	}
+
+	s.profiles[id] = data
+
+	return nil
+}
+
+func (s *ProfileStore) file(id uuid.UUID) string {
+	return filepath.Join(s.dir, id.String()+".json")
+}
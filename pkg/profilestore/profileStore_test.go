@@ -0,0 +1,78 @@
+package profilestore
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfileStore(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := NewProfileStore(&Config{ProfileDir: dir})
+	assert.NoError(t, err)
+	assert.Empty(t, store.IDs())
+
+	id, err := store.Create(json.RawMessage(`{"name": "Brand"}`))
+	assert.NoError(t, err)
+	assert.FileExists(t, filepath.Join(dir, id.String()+".json"))
+
+	data, ok := store.Get(id)
+	assert.True(t, ok)
+	assert.JSONEq(t, `{"name": "Brand"}`, string(data))
+
+	assert.NoError(t, store.Update(id, json.RawMessage(`{"name": "Corporate"}`)))
+	assert.ErrorIs(t, store.Update(uuid.New(), json.RawMessage(`{}`)), ErrProfileNotFound)
+	assert.Error(t, store.Update(id, json.RawMessage(`{"name":`)))
+
+	// the profiles are loaded again after a restart
+	store, err = NewProfileStore(&Config{ProfileDir: dir})
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{id}, store.IDs())
+
+	data, ok = store.Get(id)
+	assert.True(t, ok)
+	assert.JSONEq(t, `{"name": "Corporate"}`, string(data))
+
+	assert.NoError(t, store.Delete(id))
+	assert.ErrorIs(t, store.Delete(id), ErrProfileNotFound)
+	assert.NoFileExists(t, filepath.Join(dir, id.String()+".json"))
+
+	_, ok = store.Get(id)
+	assert.False(t, ok)
+
+	// no temporary files are left
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestProfileStoreInMemory(t *testing.T) {
+	store, err := NewProfileStore(&Config{})
+	assert.NoError(t, err)
+
+	first, err := store.Create(json.RawMessage(`{}`))
+	assert.NoError(t, err)
+	second, err := store.Create(json.RawMessage(`{}`))
+	assert.NoError(t, err)
+
+	assert.ElementsMatch(t, []uuid.UUID{first, second}, store.IDs())
+}
+
+func TestNewProfileStoreInvalid(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "brand.json"), []byte(`{}`), 0o644))
+
+	_, err := NewProfileStore(&Config{ProfileDir: dir})
+	assert.Error(t, err)
+
+	dir = t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, uuid.NewString()+".json"), []byte(`{"name":`), 0o644))
+
+	_, err = NewProfileStore(&Config{ProfileDir: dir})
+	assert.Error(t, err)
+}
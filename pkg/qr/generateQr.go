@@ -2,8 +2,28 @@ package qr
 
 import "github.com/skip2/go-qrcode"
 
-func GenerateQrCode(qrContent string) (*[]byte, error) {
-	qr, err := qrcode.New(qrContent, qrcode.High)
+type config struct {
+	level qrcode.RecoveryLevel
+}
+
+// Option configures the generated qr code.
+type Option func(*config) *config
+
+// WithRecoveryLevel sets the error correction level, the default is high.
+func WithRecoveryLevel(level qrcode.RecoveryLevel) Option {
+	return func(c *config) *config {
+		c.level = level
+		return c
+	}
+}
+
+func GenerateQrCode(qrContent string, opts ...Option) (*[]byte, error) {
+	c := &config{level: qrcode.High}
+	for _, opt := range opts {
+		c = opt(c)
+	}
+
+	qr, err := qrcode.New(qrContent, c.level)
 
 	if err != nil {
 		return nil, err
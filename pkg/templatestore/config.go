@@ -0,0 +1,10 @@
+package templatestore
+
+type Config struct {
+	//directory with the YAML and JSON files of the templates, loaded at startup
+	TemplateDir string `env:"TEMPLATE_DIR"`
+}
+
+func (c *Config) TemplateStoreServiceConfig() *Config {
+	return c
+}
@@ -0,0 +1,239 @@
+package templatestore
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DefaultName is the name of the built-in template, it cannot be replaced by
+// a file.
+const DefaultName = "default"
+
+// BlockType is the kind of a block of a template. Blocks printing data of the
+// request are skipped when the request has no data for them.
+type BlockType string
+
+const (
+	// BlockHeader is the address of the recipient and the information block
+	BlockHeader BlockType = "HEADER"
+	// BlockSubject is the title line and introduction of the request
+	BlockSubject BlockType = "SUBJECT"
+	// BlockCustomerAddress is the contracting party of the request
+	BlockCustomerAddress BlockType = "CUSTOMER_ADDRESS"
+	// BlockItems is the table of the rows, see Block.Columns
+	BlockItems BlockType = "ITEMS"
+	// BlockTaxBreakdown is the vat summary, if requested by the invoice data
+	BlockTaxBreakdown BlockType = "TAX_BREAKDOWN"
+	// BlockSums is the net, discount, tax and gross sum
+	BlockSums BlockType = "SUMS"
+	// BlockSuffix is the text after the invoice data of the request
+	BlockSuffix BlockType = "SUFFIX"
+	// BlockSignature is the confirmation of receipt of delivery notes
+	BlockSignature BlockType = "SIGNATURE"
+	// BlockBankPayment is the bank payment data with qr code or the swiss QR-bill,
+	// which ends its page, the following blocks start on a new page
+	BlockBankPayment BlockType = "BANK_PAYMENT"
+	// BlockText is a text of the template, see Block.Text
+	BlockText BlockType = "TEXT"
+	// BlockSpace is a vertical space, see Block.Height
+	BlockSpace BlockType = "SPACE"
+)
+
+var blockTypes = []BlockType{
+	BlockHeader, BlockSubject, BlockCustomerAddress, BlockItems, BlockTaxBreakdown, BlockSums,
+	BlockSuffix, BlockSignature, BlockBankPayment, BlockText, BlockSpace,
+}
+
+// ColumnField is the value of a row shown by a column of the items.
+type ColumnField string
+
+const (
+	ColumnAmount        ColumnField = "AMOUNT"
+	ColumnSerialNumber  ColumnField = "SERIAL_NUMBER"
+	ColumnBatchNumber   ColumnField = "BATCH_NUMBER"
+	ColumnNet           ColumnField = "NET"
+	ColumnTaxPercentage ColumnField = "TAX_PERCENTAGE"
+	ColumnTax           ColumnField = "TAX"
+	ColumnDiscount      ColumnField = "DISCOUNT"
+	ColumnGross         ColumnField = "GROSS"
+)
+
+var columnFields = []ColumnField{
+	ColumnAmount, ColumnSerialNumber, ColumnBatchNumber, ColumnNet, ColumnTaxPercentage, ColumnTax, ColumnDiscount, ColumnGross,
+}
+
+// IsPrice reports whether the column shows a price, which is not shown on
+// documents without prices (delivery notes).
+func (f ColumnField) IsPrice() bool {
+	switch f {
+	case ColumnNet, ColumnTaxPercentage, ColumnTax, ColumnDiscount, ColumnGross:
+		return true
+	default:
+		return false
+	}
+}
+
+// Align is the horizontal alignment of a text block.
+type Align string
+
+const (
+	AlignLeft   Align = "LEFT"
+	AlignCenter Align = "CENTER"
+	AlignRight  Align = "RIGHT"
+)
+
+// Template describes the blocks of a document in the order they are printed.
+type Template struct {
+	// Name selects the template in the request, the file name without extension
+	// when not set
+	Name   string  `json:"name" yaml:"name"`
+	Blocks []Block `json:"blocks" yaml:"blocks"`
+}
+
+// Block is a part of the document, the block is printed below the previous
+// one unless it has a position.
+type Block struct {
+	Type BlockType `json:"type" yaml:"type"`
+
+	// Position prints the block at a fixed position of the current page, the
+	// following blocks continue below the previous block
+	Position *Position `json:"position,omitempty" yaml:"position,omitempty"`
+
+	// Font replaces the body font of the style inside the block
+	Font *Font `json:"font,omitempty" yaml:"font,omitempty"`
+
+	// Text of TEXT blocks, supports markup and the placeholders of the subject
+	Text string `json:"text,omitempty" yaml:"text,omitempty"`
+	// Align of TEXT blocks, LEFT when not set
+	Align Align `json:"align,omitempty" yaml:"align,omitempty"`
+
+	// Columns of ITEMS blocks beside the name, the columns requested by the
+	// invoice data when not set
+	Columns []Column `json:"columns,omitempty" yaml:"columns,omitempty"`
+
+	// Height of SPACE blocks in mm, one line of the body font when not set
+	Height float64 `json:"height,omitempty" yaml:"height,omitempty"`
+}
+
+// Position is the top left corner of a block in mm from the top left corner
+// of the page.
+type Position struct {
+	X float64 `json:"x" yaml:"x"`
+	Y float64 `json:"y" yaml:"y"`
+
+	// Width of the block in mm, up to the right margin when not set
+	Width float64 `json:"width,omitempty" yaml:"width,omitempty"`
+}
+
+// Font is given like the typography of the request: the bundled DejaVu, a
+// family of the font store or of a font given by data in the request, the
+// style REGULAR, BOLD, ITALIC or BOLD_ITALIC and the size in pt. Empty values
+// keep the body font.
+type Font struct {
+	Family string  `json:"family,omitempty" yaml:"family,omitempty"`
+	Style  string  `json:"style,omitempty" yaml:"style,omitempty"`
+	Size   float64 `json:"size,omitempty" yaml:"size,omitempty"`
+}
+
+// Column is a column of the items beside the name.
+type Column struct {
+	Field ColumnField `json:"field" yaml:"field"`
+
+	// Title replaces the localized title of the column
+	Title string `json:"title,omitempty" yaml:"title,omitempty"`
+
+	// Width in mm, 25 when not set
+	Width float64 `json:"width,omitempty" yaml:"width,omitempty"`
+}
+
+// Default is the built-in template, which prints the blocks like the
+// generator did before templates existed.
+var Default = &Template{
+	Name: DefaultName,
+	Blocks: []Block{
+		{Type: BlockHeader},
+		{Type: BlockSubject},
+		{Type: BlockCustomerAddress},
+		{Type: BlockItems},
+		{Type: BlockTaxBreakdown},
+		{Type: BlockSums},
+		{Type: BlockSpace},
+		{Type: BlockSuffix},
+		{Type: BlockSignature},
+		{Type: BlockBankPayment},
+	},
+}
+
+var ErrTemplateEmpty = errors.New("the template has no blocks")
+
+// Validate checks the types, columns and fonts of the blocks.
+func (t *Template) Validate() error {
+	if len(t.Blocks) == 0 {
+		return ErrTemplateEmpty
+	}
+
+	for i, block := range t.Blocks {
+		if err := block.validate(); err != nil {
+			return fmt.Errorf("block %d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+func (b *Block) validate() error {
+	if !contains(blockTypes, b.Type) {
+		return fmt.Errorf("unknown type %q", b.Type)
+	}
+
+	if b.Type == BlockText && len(b.Text) == 0 {
+		return errors.New("text blocks require a text")
+	}
+
+	switch b.Align {
+	case "", AlignLeft, AlignCenter, AlignRight:
+	default:
+		return fmt.Errorf("unknown align %q", b.Align)
+	}
+
+	for _, column := range b.Columns {
+		if !contains(columnFields, column.Field) {
+			return fmt.Errorf("unknown column field %q", column.Field)
+		}
+		if column.Width < 0 {
+			return fmt.Errorf("column %s: negative width", column.Field)
+		}
+	}
+
+	if b.Height < 0 {
+		return errors.New("negative height")
+	}
+
+	if b.Position != nil && (b.Position.X < 0 || b.Position.Y < 0 || b.Position.Width < 0) {
+		return errors.New("negative position")
+	}
+
+	if b.Font != nil {
+		switch b.Font.Style {
+		case "", "REGULAR", "BOLD", "ITALIC", "BOLD_ITALIC":
+		default:
+			return fmt.Errorf("unknown font style %q", b.Font.Style)
+		}
+
+		if b.Font.Size != 0 && (b.Font.Size < 4 || b.Font.Size > 36) {
+			return fmt.Errorf("font size %v is not between 4 and 36", b.Font.Size)
+		}
+	}
+
+	return nil
+}
+
+func contains[T comparable](values []T, value T) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,124 @@
+package templatestore
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TemplateStore holds the templates which can be selected by the request in
+// addition to the built-in template.
+type TemplateStore struct {
+	templates map[string]*Template
+}
+
+// NewTemplateStore loads all YAML (.yaml, .yml) and JSON (.json) files of the
+// configured directory. Unknown fields and invalid templates are errors, so
+// they are found at startup.
+func NewTemplateStore(config *Config) (*TemplateStore, error) {
+	store := &TemplateStore{templates: make(map[string]*Template)}
+
+	if len(config.TemplateDir) == 0 {
+		return store, nil
+	}
+
+	entries, err := os.ReadDir(config.TemplateDir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml" && ext != ".json") {
+			continue
+		}
+
+		template, err := LoadTemplate(filepath.Join(config.TemplateDir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("template %s: %w", entry.Name(), err)
+		}
+
+		key := strings.ToLower(template.Name)
+		if key == DefaultName {
+			return nil, fmt.Errorf("template %s: the name %s is reserved for the built-in template", entry.Name(), DefaultName)
+		}
+		if _, ok := store.templates[key]; ok {
+			return nil, fmt.Errorf("template %s: the name %s is used twice", entry.Name(), template.Name)
+		}
+
+		store.templates[key] = template
+	}
+
+	return store, nil
+}
+
+// LoadTemplate reads and validates a template file, the format is given by the
+// extension. The name is the file name without extension when not set.
+func LoadTemplate(file string) (*Template, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &Template{}
+
+	if strings.EqualFold(filepath.Ext(file), ".json") {
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(template)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		decoder.KnownFields(true)
+		err = decoder.Decode(template)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(template.Name) == 0 {
+		template.Name = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	}
+
+	if err := template.Validate(); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+// Get returns the template, the name is case insensitive. An empty name
+// returns the built-in template.
+func (s *TemplateStore) Get(name string) (*Template, bool) {
+	if len(name) == 0 || strings.EqualFold(name, DefaultName) {
+		return Default, true
+	}
+
+	if s == nil {
+		return nil, false
+	}
+
+	template, ok := s.templates[strings.ToLower(name)]
+	return template, ok
+}
+
+// Names returns the names of all templates sorted by name, including the
+// built-in template.
+func (s *TemplateStore) Names() []string {
+	names := []string{DefaultName}
+
+	if s != nil {
+		for _, template := range s.templates {
+			names = append(names, template.Name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,103 @@
+package templatestore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTemplate(t *testing.T, dir, name, content string) {
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+}
+
+func TestNewTemplateStore(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "compact.yaml", `
+name: Compact
+blocks:
+  - type: HEADER
+  - type: TEXT
+    text: "**{{.Number}}**"
+    align: RIGHT
+    position: {x: 25, y: 100, width: 60}
+    font: {family: DejaVu, style: BOLD, size: 12}
+  - type: ITEMS
+    columns:
+      - field: AMOUNT
+        width: 20
+      - field: GROSS
+        title: Total
+`)
+	writeTemplate(t, dir, "minimal.json", `{"blocks": [{"type": "HEADER"}, {"type": "SPACE", "height": 5}]}`)
+	writeTemplate(t, dir, "readme.txt", "not a template")
+
+	store, err := NewTemplateStore(&Config{TemplateDir: dir})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Compact", "default", "minimal"}, store.Names())
+
+	template, ok := store.Get("compact")
+	assert.True(t, ok)
+	assert.Len(t, template.Blocks, 3)
+	assert.Equal(t, AlignRight, template.Blocks[1].Align)
+	assert.Equal(t, &Position{X: 25, Y: 100, Width: 60}, template.Blocks[1].Position)
+	assert.Equal(t, &Font{Family: "DejaVu", Style: "BOLD", Size: 12}, template.Blocks[1].Font)
+	assert.Equal(t, []Column{{Field: ColumnAmount, Width: 20}, {Field: ColumnGross, Title: "Total"}}, template.Blocks[2].Columns)
+
+	template, ok = store.Get("minimal")
+	assert.True(t, ok)
+	assert.Equal(t, 5., template.Blocks[1].Height)
+
+	template, ok = store.Get("")
+	assert.True(t, ok)
+	assert.Equal(t, Default, template)
+
+	_, ok = store.Get("unknown")
+	assert.False(t, ok)
+
+	store, err = NewTemplateStore(&Config{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{DefaultName}, store.Names())
+
+	// a missing store only knows the built-in template
+	var nilStore *TemplateStore
+	_, ok = nilStore.Get("Default")
+	assert.True(t, ok)
+	assert.Equal(t, []string{DefaultName}, nilStore.Names())
+}
+
+func TestNewTemplateStoreInvalid(t *testing.T) {
+	tests := []struct {
+		name, file, content string
+	}{
+		{"unknown field", "a.yaml", "blocks:\n  - type: HEADER\n    colour: red\n"},
+		{"unknown json field", "a.json", `{"blocks": [{"type": "HEADER"}], "pages": 2}`},
+		{"unknown type", "a.yaml", "blocks:\n  - type: FOOTER\n"},
+		{"unknown column", "a.yaml", "blocks:\n  - type: ITEMS\n    columns:\n      - field: PRICE\n"},
+		{"text without text", "a.yaml", "blocks:\n  - type: TEXT\n"},
+		{"font size", "a.yaml", "blocks:\n  - type: SUMS\n    font: {size: 72}\n"},
+		{"no blocks", "a.yaml", "name: empty\n"},
+		{"reserved name", "default.yaml", "blocks:\n  - type: HEADER\n"},
+	}
+
+	for _, test := range tests {
+		dir := t.TempDir()
+		writeTemplate(t, dir, test.file, test.content)
+
+		_, err := NewTemplateStore(&Config{TemplateDir: dir})
+		assert.Error(t, err, test.name)
+	}
+
+	// the names are case insensitive
+	dir := t.TempDir()
+	writeTemplate(t, dir, "a.yaml", "name: Brand\nblocks:\n  - type: HEADER\n")
+	writeTemplate(t, dir, "b.yaml", "name: brand\nblocks:\n  - type: HEADER\n")
+
+	_, err := NewTemplateStore(&Config{TemplateDir: dir})
+	assert.Error(t, err)
+}
+
+func TestDefaultTemplate(t *testing.T) {
+	assert.NoError(t, Default.Validate())
+}